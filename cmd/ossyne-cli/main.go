@@ -31,12 +31,12 @@ open-source project maintainers with contributors.
 Find tasks, submit contributions, earn bounties, and build your
 reputation in the open-source world, all from your terminal.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) == 0 && cmd.Flags().NFlag() == 0 {
+			if len(args) == 0 && cmd.LocalNonPersistentFlags().NFlag() == 0 {
 				fmt.Print(ossyneBanner)
 				fmt.Println(cmd.Long)
 				fmt.Println()
 
-				p := tea.NewProgram(tui.InitModel(), tea.WithAltScreen())
+				p := tea.NewProgram(tui.InitModel(cli.ServerURL(), cli.ActiveProfile()), tea.WithAltScreen())
 				if _, err := p.Run(); err != nil {
 					fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 					os.Exit(1)
@@ -47,6 +47,7 @@ reputation in the open-source world, all from your terminal.`,
 		},
 	}
 
+	cli.AddGlobalFlags(rootCmd)
 	rootCmd.SetHelpTemplate(ossyneBanner + "\n" + rootCmd.HelpTemplate())
 
 	var versionCmd = &cobra.Command{
@@ -61,10 +62,15 @@ reputation in the open-source world, all from your terminal.`,
 	rootCmd.AddCommand(cli.NewUserCmd())
 	rootCmd.AddCommand(cli.NewProjectCmd())
 	rootCmd.AddCommand(cli.NewTaskCmd())
+	rootCmd.AddCommand(cli.NewClaimCmd())
+	rootCmd.AddCommand(cli.NewMilestoneCmd())
+	rootCmd.AddCommand(cli.NewCommentCmd())
 	rootCmd.AddCommand(cli.NewRatingsCmd())
 	rootCmd.AddCommand(cli.NewAdminCmd())
 	rootCmd.AddCommand(cli.NewPaymentCmd())
 	rootCmd.AddCommand(cli.NewAuthCmd())
+	rootCmd.AddCommand(cli.NewConfigCmd())
+	rootCmd.AddCommand(cli.NewProfileCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your command '%s'", err)
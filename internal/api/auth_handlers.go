@@ -0,0 +1,186 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"time"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	Service *services.SessionService
+}
+
+func (h *SessionHandler) RefreshSession(c echo.Context) error {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Refresh token is required"})
+	}
+	tokens, err := h.Service.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSession) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired refresh token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to refresh session: %v", err)})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h *SessionHandler) ListSessions(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	sessions, err := h.Service.List(user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to list sessions: %v", err)})
+	}
+	if current := currentSession(c); current != nil {
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == current.ID
+		}
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) RevokeSession(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid session ID"})
+	}
+	if err := h.Service.Revoke(user.ID, uint(sessionID)); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Session with ID %d not found", sessionID)})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to revoke session: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Session revoked"})
+}
+
+func (h *SessionHandler) Logout(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	session := currentSession(c)
+	if session == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No active session"})
+	}
+	if err := h.Service.Revoke(user.ID, session.ID); err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to revoke session: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "Logged out"})
+}
+
+type DeviceAuthHandler struct {
+	Service *services.DeviceAuthService
+}
+
+func (h *DeviceAuthHandler) RequestDeviceCode(c echo.Context) error {
+	verificationURI := fmt.Sprintf("%s://%s/auth/device", c.Scheme(), c.Request().Host)
+	resp, err := h.Service.Start(verificationURI, c.Request().UserAgent(), c.RealIP())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to start device login: %v", err)})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeviceAuthHandler) PollDeviceToken(c echo.Context) error {
+	var req struct {
+		DeviceCode string `json:"device_code"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.DeviceCode == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Device code is required"})
+	}
+	tokens, err := h.Service.Poll(req.DeviceCode, c.Request().UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAuthorizationPending),
+			errors.Is(err, services.ErrSlowDown),
+			errors.Is(err, services.ErrAccessDenied),
+			errors.Is(err, services.ErrExpiredToken):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to complete device login: %v", err)})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+type APITokenHandler struct {
+	Service *services.APITokenService
+}
+
+func (h *APITokenHandler) CreateToken(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	var req struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Token name is required"})
+	}
+	if req.ExpiresInDays < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "expires_in_days cannot be negative"})
+	}
+	token, err := h.Service.Create(user.ID, req.Name, req.Scopes, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScope) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to create API token: %v", err)})
+	}
+	return c.JSON(http.StatusCreated, token)
+}
+
+func (h *APITokenHandler) ListTokens(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	tokens, err := h.Service.List(user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to list API tokens: %v", err)})
+	}
+	return c.JSON(http.StatusOK, tokens)
+}
+
+func (h *APITokenHandler) RevokeToken(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	tokenID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid token ID"})
+	}
+	if err := h.Service.Revoke(user.ID, uint(tokenID)); err != nil {
+		if errors.Is(err, services.ErrAPITokenNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("API token with ID %d not found", tokenID)})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to revoke API token: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "API token revoked"})
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"github.com/labstack/echo/v4"
+)
+
+type CommentHandler struct {
+	Service *services.CommentService
+}
+
+type commentRequest struct {
+	Body     string `json:"body"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// commentError turns an error from CommentService into the matching HTTP
+// response.
+func commentError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidComment):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrCommentNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrNotCommentAuthor),
+		errors.Is(err, services.ErrNotInThread):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+func (h *CommentHandler) ListTaskComments(c echo.Context) error {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	comments, err := h.Service.ListForTask(uint(taskID))
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) CreateTaskComment(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req commentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	comment, err := h.Service.AddToTask(user, uint(taskID), req.ParentID, req.Body)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) ListContributionComments(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	contributionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid contribution ID"})
+	}
+	comments, err := h.Service.ListForContribution(user, uint(contributionID))
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) CreateContributionComment(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	contributionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid contribution ID"})
+	}
+	var req commentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	comment, err := h.Service.AddToContribution(user, uint(contributionID), req.ParentID, req.Body)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusCreated, comment)
+}
+
+func (h *CommentHandler) UpdateComment(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	var req commentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	comment, err := h.Service.Edit(user, uint(commentID), req.Body)
+	if err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid comment ID"})
+	}
+	if err := h.Service.Delete(user, uint(commentID)); err != nil {
+		return commentError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Comment %d deleted", commentID)})
+}
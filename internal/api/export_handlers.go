@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"strings"
+	"github.com/labstack/echo/v4"
+)
+
+type ExportHandler struct {
+	Service *services.ExportService
+	Access  *services.ProjectAccessService
+}
+
+// ExportProject streams the project's data. format is json (the default),
+// which includes every table, or csv, which exports the one named by table.
+func (h *ExportHandler) ExportProject(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+	table := c.QueryParam("table")
+	switch format {
+	case "json":
+	case "csv":
+		if table == "" {
+			table = models.ExportTableTasks
+		}
+		if !services.IsValidExportTable(table) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid table %q, expected one of %s", table, strings.Join(models.ExportTables, ", "))})
+		}
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Format must be json or csv"})
+	}
+	project, err := h.Access.AuthorizeProject(user, uint(projectID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+
+	// The status is sent before the first row, so a failure half way can only
+	// cut the stream short.
+	res := c.Response()
+	if format == "csv" {
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="project-%d-%s.csv"`, project.ID, table))
+		res.WriteHeader(http.StatusOK)
+		err = h.Service.WriteCSV(res, project, table)
+	} else {
+		res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="project-%d-export.json"`, project.ID))
+		res.WriteHeader(http.StatusOK)
+		err = h.Service.WriteJSON(res, project)
+	}
+	if err != nil {
+		fmt.Printf("[ERROR]: Export of project %d aborted: %v\n", project.ID, err)
+	}
+	return nil
+}
+
+// ImportProject restores a JSON export into the project, which must not have
+// any tasks yet.
+func (h *ExportHandler) ImportProject(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	doc := new(models.ProjectExport)
+	if err := c.Bind(doc); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	result, err := h.Service.Import(user, uint(projectID), doc)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidExport):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrProjectNotEmpty):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return projectAccessError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
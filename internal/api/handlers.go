@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"ossyne/internal/db"
 	"ossyne/internal/models"
 	"ossyne/internal/services"
 	"strconv"
+	"strings"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -26,7 +28,8 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, user)
 }
-//Development-only handler for creating claims without authentication
+//Development-only handler for creating claims without authentication.
+//Claims made here skip maintainer approval and are accepted straight away.
 func (h *ClaimHandler) CreateClaimDev(c echo.Context) error {
 	var req struct {
 		TaskID uint `json:"task_id"`
@@ -40,49 +43,20 @@ func (h *ClaimHandler) CreateClaimDev(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task ID and User ID are required for dev mode"})
 	}
 
-	claim := &models.Claim{
-		TaskID: req.TaskID,
-		UserID: req.UserID,
-	}
-
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start transaction"})
-	}
-
-	var task models.Task
-	if err := tx.First(&task, claim.TaskID).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "Task not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch task"})
-	}
-
-	if task.Status != "open" {
-		tx.Rollback()
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task is not open for claiming"})
-	}
-
-	if err := tx.Create(claim).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to create claim"})
-	}
-
-	task.Status = "claimed"
-	if err := tx.Save(&task).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update task status"})
+	claim, err := h.Service.Request(req.TaskID, req.UserID)
+	if err != nil {
+		return claimError(c, err)
 	}
-
-	if err := tx.Commit().Error; err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to commit transaction"})
+	claim, err = h.Service.Accept(claim.ID, nil, "Accepted automatically (dev mode)")
+	if err != nil {
+		return claimError(c, err)
 	}
-
 	return c.JSON(http.StatusCreated, claim)
 }
 
-type ProjectHandler struct{}
+type ProjectHandler struct {
+	Access *services.ProjectAccessService
+}
 
 func (h *ProjectHandler) CreateProject(c echo.Context) error {
 	project := new(models.Project)
@@ -126,21 +100,186 @@ func (h *ProjectHandler) ListUserProjects(c echo.Context) error {
 	return c.JSON(http.StatusOK, projects)
 }
 
-type TaskHandler struct{}
+// UpdateProject edits the fields present in the request body.
+func (h *ProjectHandler) UpdateProject(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	var req struct {
+		Title      *string   `json:"title"`
+		ShortDesc  *string   `json:"short_desc"`
+		RepoURL    *string   `json:"repo_url"`
+		Tags       *[]string `json:"tags"`
+		Visibility *string   `json:"visibility"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	project, err := h.Access.AuthorizeProject(user, uint(projectID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		if *req.Title == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Title cannot be empty"})
+		}
+		updates["title"] = *req.Title
+	}
+	if req.ShortDesc != nil {
+		updates["short_desc"] = *req.ShortDesc
+	}
+	if req.RepoURL != nil {
+		updates["repo_url"] = *req.RepoURL
+	}
+	if req.Tags != nil {
+		updates["tags"] = models.JSONStringSlice(*req.Tags)
+	}
+	if req.Visibility != nil {
+		if *req.Visibility != "public" && *req.Visibility != "private" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Visibility must be 'public' or 'private'"})
+		}
+		updates["visibility"] = *req.Visibility
+	}
+	if len(updates) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No fields to update"})
+	}
+
+	if err := db.DB.Model(project).Updates(updates).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to update project: %v", err)})
+	}
+	db.DB.First(project, project.ID)
+	return c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject soft-deletes a project; its tasks disappear from listings
+// until the project is restored. Bounties still in escrow must be refunded or
+// paid out first.
+func (h *ProjectHandler) DeleteProject(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	project, err := h.Access.AuthorizeProject(user, uint(projectID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+
+	var escrowed int64
+	db.DB.Model(&models.Task{}).
+		Where("project_id = ? AND bounty_escrow_id IS NOT NULL AND bounty_escrow_id <> '' AND status NOT IN ?", project.ID,
+			[]string{models.TaskStatusCompleted, models.TaskStatusArchived}).
+		Count(&escrowed)
+	if escrowed > 0 {
+		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("Project '%s' has %d tasks with escrowed bounties; archive or refund them first", project.Title, escrowed)})
+	}
+
+	if err := db.DB.Delete(project).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to delete project: %v", err)})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Project '%s' deleted. Restore it with 'osm project restore %d'.", project.Title, project.ID)})
+}
+
+func (h *ProjectHandler) RestoreProject(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	project, err := h.Access.AuthorizeDeletedProject(user, uint(projectID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+
+	if err := db.DB.Unscoped().Model(project).Update("deleted_at", nil).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to restore project: %v", err)})
+	}
+	project.DeletedAt = gorm.DeletedAt{}
+	return c.JSON(http.StatusOK, project)
+}
 
+type TaskHandler struct {
+	Access       *services.ProjectAccessService
+	States       *services.TaskStateService
+	Tasks        *services.TaskService
+	Dependencies *services.TaskDependencyService
+	Milestones   *services.MilestoneService
+	Claims       *services.ClaimService
+}
+
+// CreateTask adds an open task to a project. Status, bounty and the links to
+// GitHub issues and the manifest are not taken from the request: they change
+// through the task lifecycle, bounty escrow, issue import and manifest apply.
 func (h *TaskHandler) CreateTask(c echo.Context) error {
-	task := new(models.Task)
-	if err := c.Bind(task); err != nil {
+	var req struct {
+		ProjectID       uint     `json:"project_id"`
+		Title           string   `json:"title"`
+		Description     string   `json:"description"`
+		DifficultyLevel string   `json:"difficulty_level"`
+		EstimatedHours  int      `json:"estimated_hours"`
+		Tags            []string `json:"tags"`
+		SkillsRequired  []string `json:"skills_required"`
+		ClaimPolicy     string   `json:"claim_policy"`
+		MaxClaimants    int      `json:"max_claimants"`
+		MilestoneID     *uint    `json:"milestone_id"`
+	}
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	task := &models.Task{
+		ProjectID:       req.ProjectID,
+		Title:           req.Title,
+		Description:     req.Description,
+		DifficultyLevel: req.DifficultyLevel,
+		EstimatedHours:  req.EstimatedHours,
+		Tags:            req.Tags,
+		SkillsRequired:  req.SkillsRequired,
+		Status:          models.TaskStatusOpen,
+		ClaimPolicy:     req.ClaimPolicy,
+		MaxClaimants:    req.MaxClaimants,
+		MilestoneID:     req.MilestoneID,
+	}
 
 	if task.ProjectID == 0 {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Project ID is required"})
 	}
-
-	var project models.Project
-	if err := db.DB.First(&project, task.ProjectID).Error; err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Project with ID %d not found", task.ProjectID)})
+	if task.ClaimPolicy == "" {
+		task.ClaimPolicy = models.ClaimPolicyExclusive
+	}
+	if !models.IsValidClaimPolicy(task.ClaimPolicy) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid claim_policy '%s'", task.ClaimPolicy)})
+	}
+	if task.ClaimPolicy == models.ClaimPolicyLimited {
+		if task.MaxClaimants < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "max_claimants must be at least 1 for the limited claim policy"})
+		}
+	} else {
+		task.MaxClaimants = 0
+	}
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	if _, err := h.Access.AuthorizeProject(user, task.ProjectID); err != nil {
+		return projectAccessError(c, err)
+	}
+	if task.MilestoneID != nil {
+		if err := h.Milestones.CheckAssignable(*task.MilestoneID, task.ProjectID); err != nil {
+			return milestoneError(c, err)
+		}
 	}
 	result := db.DB.Create(&task)
 	if result.Error != nil {
@@ -150,85 +289,518 @@ func (h *TaskHandler) CreateTask(c echo.Context) error {
 	return c.JSON(http.StatusCreated, task)
 }
 
+// UpdateTask edits the fields present in the request body. Status and bounty
+// have their own endpoints.
+func (h *TaskHandler) UpdateTask(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		Title           *string   `json:"title"`
+		Description     *string   `json:"description"`
+		DifficultyLevel *string   `json:"difficulty_level"`
+		EstimatedHours  *int      `json:"estimated_hours"`
+		Tags            *[]string `json:"tags"`
+		SkillsRequired  *[]string `json:"skills_required"`
+		ClaimPolicy     *string   `json:"claim_policy"`
+		MaxClaimants    *int      `json:"max_claimants"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	task, err := h.Access.AuthorizeTask(user, uint(taskID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		if *req.Title == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Title cannot be empty"})
+		}
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.DifficultyLevel != nil {
+		switch *req.DifficultyLevel {
+		case "easy", "medium", "hard":
+			updates["difficulty_level"] = *req.DifficultyLevel
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Difficulty must be easy, medium or hard"})
+		}
+	}
+	if req.EstimatedHours != nil {
+		if *req.EstimatedHours < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Estimated hours cannot be negative"})
+		}
+		updates["estimated_hours"] = *req.EstimatedHours
+	}
+	if req.Tags != nil {
+		updates["tags"] = models.JSONStringSlice(*req.Tags)
+	}
+	if req.SkillsRequired != nil {
+		updates["skills_required"] = models.JSONStringSlice(*req.SkillsRequired)
+	}
+	if req.ClaimPolicy != nil || req.MaxClaimants != nil {
+		// Changing who may work on a task once claims are accepted would
+		// strand or over-admit claimants.
+		if task.Status != models.TaskStatusOpen {
+			return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("The claim policy can only change while the task is open (current status: %s)", task.Status)})
+		}
+		policy, maxClaimants := task.ClaimPolicy, task.MaxClaimants
+		if req.ClaimPolicy != nil {
+			policy = *req.ClaimPolicy
+		}
+		if req.MaxClaimants != nil {
+			maxClaimants = *req.MaxClaimants
+		}
+		if !models.IsValidClaimPolicy(policy) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid claim_policy '%s'", policy)})
+		}
+		if policy != models.ClaimPolicyLimited {
+			maxClaimants = 0
+		} else if maxClaimants < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "max_claimants must be at least 1 for the limited claim policy"})
+		}
+		updates["claim_policy"] = policy
+		updates["max_claimants"] = maxClaimants
+	}
+	if len(updates) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No fields to update"})
+	}
+
+	if err := db.DB.Model(task).Updates(updates).Error; err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to update task: %v", err)})
+	}
+	db.DB.First(task, task.ID)
+	return c.JSON(http.StatusOK, task)
+}
+
+// ArchiveTask archives a task, refunding any bounty still in escrow.
+func (h *TaskHandler) ArchiveTask(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if _, err := h.Access.AuthorizeTask(user, uint(taskID)); err != nil {
+		return projectAccessError(c, err)
+	}
+
+	task, err := h.Tasks.Archive(uint(taskID), &user.ID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIllegalTaskTransition):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrTaskNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to archive task: %v", err)})
+	}
+	return c.JSON(http.StatusOK, task)
+}
+
+// ListTasks searches tasks. See parseTaskSearch for the query parameters.
+// The body is one page of tasks; when more match, the X-Next-Cursor header
+// carries the cursor for the next page.
 func (h *TaskHandler) ListTasks(c echo.Context) error {
-	var tasks []models.Task
-	projectIDStr := c.QueryParam("project_id")
-	status := c.QueryParam("status")
-	query := db.DB.Model(&models.Task{})
+	search, err := parseTaskSearch(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	page, err := h.Tasks.Search(search)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaskSearch) || errors.Is(err, services.ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if err := h.Dependencies.FillBlockedBy(page.Tasks); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	if page.NextCursor != "" {
+		c.Response().Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+	return c.JSON(http.StatusOK, page.Tasks)
+}
 
-	if projectIDStr != "" {
-		projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project_id"})
+// parseTaskSearch reads the task search query parameters: q, project_id,
+// milestone_id, status, tags, skills, difficulty (comma separated lists may
+// also be repeated), min_bounty, max_bounty, min_hours, max_hours, sort,
+// limit and cursor.
+func parseTaskSearch(c echo.Context) (services.TaskSearch, error) {
+	search := services.TaskSearch{
+		Text:         c.QueryParam("q"),
+		Status:       c.QueryParam("status"),
+		Tags:         queryList(c, "tags"),
+		Skills:       queryList(c, "skills"),
+		Difficulties: queryList(c, "difficulty"),
+		Sort:         c.QueryParam("sort"),
+		Cursor:       c.QueryParam("cursor"),
+	}
+	for param, target := range map[string]*uint{"project_id": &search.ProjectID, "milestone_id": &search.MilestoneID} {
+		if value := c.QueryParam(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return search, fmt.Errorf("Invalid %s", param)
+			}
+			*target = uint(id)
 		}
-		query = query.Where("project_id = ?", projectID)
 	}
-	if status != "" {
-		query = query.Where("status = ?", status)
+	for param, target := range map[string]**float64{"min_bounty": &search.MinBounty, "max_bounty": &search.MaxBounty} {
+		if value := c.QueryParam(param); value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+			if err != nil || amount < 0 {
+				return search, fmt.Errorf("Invalid %s", param)
+			}
+			*target = &amount
+		}
 	}
-	query.Find(&tasks)
-	return c.JSON(http.StatusOK, tasks)
+	for param, target := range map[string]**int{"min_hours": &search.MinHours, "max_hours": &search.MaxHours} {
+		if value := c.QueryParam(param); value != "" {
+			hours, err := strconv.Atoi(value)
+			if err != nil || hours < 0 {
+				return search, fmt.Errorf("Invalid %s", param)
+			}
+			*target = &hours
+		}
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return search, fmt.Errorf("Invalid limit")
+		}
+		search.Limit = limit
+	}
+	return search, nil
 }
 
-type ClaimHandler struct{}
+// queryList collects a list query parameter given either repeated or comma
+// separated.
+func queryList(c echo.Context, param string) []string {
+	var values []string
+	for _, raw := range c.QueryParams()[param] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
 
-func (h *ClaimHandler) CreateClaim(c echo.Context) error {
+// GetTaskDependencies returns the task's dependency tree.
+func (h *TaskHandler) GetTaskDependencies(c echo.Context) error {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	tree, err := h.Dependencies.Tree(uint(taskID))
+	if err != nil {
+		if errors.Is(err, services.ErrTaskNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", taskID)})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, tree)
+}
+
+func (h *TaskHandler) AddTaskDependency(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
 	var req struct {
-		TaskID uint `json:"task_id"`
+		DependsOnID uint `json:"depends_on_id"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	if req.DependsOnID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "depends_on_id is required"})
+	}
+	if _, err := h.Access.AuthorizeTask(user, uint(taskID)); err != nil {
+		return projectAccessError(c, err)
+	}
+
+	dependency, err := h.Dependencies.Add(uint(taskID), req.DependsOnID, &user.ID)
+	if err != nil {
+		return dependencyError(c, err)
+	}
+	return c.JSON(http.StatusCreated, dependency)
+}
+
+func (h *TaskHandler) RemoveTaskDependency(c echo.Context) error {
 	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
 	if !ok || user == nil {
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
 	}
-
-	claim := &models.Claim{
-		TaskID: req.TaskID,
-		UserID: user.ID,
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	dependsOnID, err := strconv.ParseUint(c.Param("depends_on_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid prerequisite task ID"})
+	}
+	if _, err := h.Access.AuthorizeTask(user, uint(taskID)); err != nil {
+		return projectAccessError(c, err)
 	}
 
-	if claim.TaskID == 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task ID is required"})
+	if err := h.Dependencies.Remove(uint(taskID), uint(dependsOnID)); err != nil {
+		return dependencyError(c, err)
 	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Task %d no longer depends on task %d", taskID, dependsOnID)})
+}
 
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start transaction"})
+func dependencyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrDependencyCycle),
+		errors.Is(err, services.ErrDependencyExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrDependencyNotFound),
+		errors.Is(err, services.ErrTaskNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
 
+func (h *TaskHandler) GetTaskHistory(c echo.Context) error {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
 	var task models.Task
-	if err := tx.First(&task, claim.TaskID).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", claim.TaskID)})
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", taskID)})
+	}
+	history, err := h.States.History(uint(taskID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, history)
+}
+
+// UpdateTaskStatus lets project maintainers move a task along its lifecycle.
+// The contributor holding the claim may also mark it in progress. Moves with
+// side effects go through the service that owns them: archiving refunds the
+// bounty, reopening releases the claims, and claiming, submitting and
+// completing only happen through claims and contributions.
+func (h *TaskHandler) UpdateTaskStatus(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
 	}
-	if task.Status != "open" {
-		tx.Rollback()
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("Task '%s' is not open for claims (current status: %s)", task.Title, task.Status)})
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if !services.IsValidTaskStatus(req.Status) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid status '%s'", req.Status)})
+	}
+
+	if _, err := h.Access.AuthorizeTask(user, uint(taskID)); err != nil {
+		if !errors.Is(err, services.ErrNotProjectMaintainer) || req.Status != models.TaskStatusInProgress {
+			return projectAccessError(c, err)
+		}
+		var claims int64
+		if err := db.DB.Model(&models.Claim{}).
+			Where("task_id = ? AND user_id = ? AND status = ?", taskID, user.ID, models.ClaimStatusAccepted).
+			Count(&claims).Error; err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to check claims: %v", err)})
+		}
+		if claims == 0 {
+			return projectAccessError(c, err)
+		}
 	}
 
-	var existingClaim models.Claim
-	if err := tx.Where("task_id = ? AND user_id = ?", claim.TaskID, claim.UserID).First(&existingClaim).Error; err == nil {
-		tx.Rollback()
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("User %s has already claimed task '%s'", user.Username, task.Title)})
+	if req.Status == models.TaskStatusArchived || req.Status == models.TaskStatusOpen {
+		if msg, missing := missingTokenScope(c, models.ScopeProjectsWrite); missing {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": msg})
+		}
 	}
 
-	claim.Status = "pending"
-	if err := tx.Create(&claim).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to create claim: %v", err)})
+	var task *models.Task
+	switch req.Status {
+	case models.TaskStatusInProgress, models.TaskStatusClaimed:
+		task, err = h.States.TransitionDirectly(uint(taskID), req.Status, &user.ID, req.Reason)
+	case models.TaskStatusArchived:
+		task, err = h.Tasks.Archive(uint(taskID), &user.ID, req.Reason)
+	case models.TaskStatusOpen:
+		var current models.Task
+		if err := db.DB.Select("id", "status").First(&current, taskID).Error; err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", taskID)})
+		}
+		if current.Status == models.TaskStatusArchived {
+			task, err = h.Tasks.Reopen(uint(taskID), &user.ID, req.Reason)
+		} else {
+			task, err = h.Claims.Release(uint(taskID), &user.ID, req.Reason)
+		}
+	case models.TaskStatusSubmitted:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Tasks are submitted by submitting a contribution"})
+	case models.TaskStatusCompleted:
+		return c.JSON(http.StatusConflict, map[string]string{"error": "Tasks are completed by accepting their contribution"})
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrIllegalTaskTransition) || errors.Is(err, services.ErrTransitionNotDirect) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return projectAccessError(c, err)
 	}
+	return c.JSON(http.StatusOK, task)
+}
+
+type ClaimHandler struct {
+	Service *services.ClaimService
+	Access  *services.ProjectAccessService
+}
 
-	if err := tx.Model(&task).Update("status", "claimed").Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to update task status: %v", err)})
+// claimError turns an error from ClaimService into the matching HTTP response.
+func claimError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTaskNotOpen),
+		errors.Is(err, services.ErrAlreadyClaimed),
+		errors.Is(err, services.ErrClaimNotPending),
+		errors.Is(err, services.ErrClaimLimitReached),
+		errors.Is(err, services.ErrWorkSubmitted),
+		errors.Is(err, services.ErrTaskBlocked),
+		errors.Is(err, services.ErrIllegalTaskTransition):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrNoActiveClaim):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+func (h *ClaimHandler) CreateClaim(c echo.Context) error {
+	var req struct {
+		TaskID uint `json:"task_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	if req.TaskID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task ID is required"})
 	}
 
-	tx.Commit()
+	claim, err := h.Service.Request(req.TaskID, user.ID)
+	if err != nil {
+		return claimError(c, err)
+	}
 	return c.JSON(http.StatusCreated, claim)
 }
 
+// ListPendingClaims shows the claims waiting on the caller's approval.
+func (h *ClaimHandler) ListPendingClaims(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	var taskID uint64
+	if taskIDStr := c.QueryParam("task_id"); taskIDStr != "" {
+		var err error
+		taskID, err = strconv.ParseUint(taskIDStr, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task_id"})
+		}
+	}
+	claims, err := h.Service.ListPending(user, uint(taskID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, claims)
+}
+
+// Unclaim withdraws the caller's claim on a task and reopens the task if the
+// claim had been accepted.
+func (h *ClaimHandler) Unclaim(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	claim, err := h.Service.Withdraw(uint(taskID), user.ID, req.Reason)
+	if err != nil {
+		return claimError(c, err)
+	}
+	return c.JSON(http.StatusOK, claim)
+}
+
+func (h *ClaimHandler) AcceptClaim(c echo.Context) error {
+	return h.reviewClaim(c, h.Service.Accept)
+}
+
+func (h *ClaimHandler) RejectClaim(c echo.Context) error {
+	return h.reviewClaim(c, h.Service.Reject)
+}
+
+func (h *ClaimHandler) reviewClaim(c echo.Context, decide func(claimID uint, actorID *uint, note string) (*models.Claim, error)) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	claimID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid claim ID"})
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if _, err := h.Access.AuthorizeClaim(user, uint(claimID)); err != nil {
+		return projectAccessError(c, err)
+	}
+
+	claim, err := decide(uint(claimID), &user.ID, req.Note)
+	if err != nil {
+		return claimError(c, err)
+	}
+	return c.JSON(http.StatusOK, claim)
+}
+
 func (h *ClaimHandler) ListClaims(c echo.Context) error {
 	var claims []models.Claim
 	userIDStr := c.QueryParam("user_id")
@@ -254,12 +826,28 @@ func (h *ClaimHandler) ListClaims(c echo.Context) error {
 		query = query.Where("status = ?", status)
 	}
 
-	query.Preload("Task").Preload("User").Find(&claims)
+	query.Preload("Task").Preload("User").Preload("Mentor").Find(&claims)
 	return c.JSON(http.StatusOK, claims)
 }
 
 type ContributionHandler struct {
 	Service *services.ContributionService
+	Access  *services.ProjectAccessService
+}
+
+// contributionError turns an error from ContributionService into the matching
+// HTTP response.
+func contributionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTaskNotAcceptingWork),
+		errors.Is(err, services.ErrAlreadySubmitted),
+		errors.Is(err, services.ErrContributionNotPending):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrNoClaimForSubmission),
+		errors.Is(err, services.ErrSelfReview):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
 }
 
 func (h *ContributionHandler) CreateContribution(c echo.Context) error {
@@ -275,44 +863,13 @@ func (h *ContributionHandler) CreateContribution(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
 	}
 
-	contribution := &models.Contribution{
-		TaskID: req.TaskID,
-		UserID: user.ID,
-		PRURL:  req.PRURL,
-	}
-
-	if contribution.TaskID == 0 || contribution.PRURL == "" {
+	if req.TaskID == 0 || req.PRURL == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task ID and PR URL are required"})
 	}
-
-	var task models.Task
-	if err := db.DB.First(&task, contribution.TaskID).Error; err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", contribution.TaskID)})
-	}
-	if task.Status != "claimed" && task.Status != "in_progress" {
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("Task '%s' is not in a state to accept contributions (current status: %s)", task.Title, task.Status)})
-	}
-
-	var existingContribution models.Contribution
-	if err := db.DB.Where("task_id = ? AND user_id = ?", contribution.TaskID, contribution.UserID).First(&existingContribution).Error; err == nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("User %s has already submitted a contribution for task '%s'", user.Username, task.Title)})
-	}
-
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start transaction"})
-	}
-
-	contribution.VerificationStatus = "unverified"
-	if err := tx.Create(&contribution).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to create contribution: %v", err)})
-	}
-	if err := tx.Model(&task).Update("status", "submitted").Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to update task status: %v", err)})
+	contribution, err := h.Service.Submit(req.TaskID, user.ID, req.PRURL, "Contribution submitted")
+	if err != nil {
+		return contributionError(c, err)
 	}
-	tx.Commit()
 	return c.JSON(http.StatusCreated, contribution)
 }
 
@@ -331,40 +888,10 @@ func (h *ContributionHandler) CreateContributionDev(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Task ID, User ID, and PR URL are required for dev mode"})
 	}
 
-	contribution := &models.Contribution{
-		TaskID: req.TaskID,
-		UserID: req.UserID,
-		PRURL:  req.PRURL,
-	}
-
-	var task models.Task
-	if err := db.DB.First(&task, contribution.TaskID).Error; err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("Task with ID %d not found", contribution.TaskID)})
-	}
-	if task.Status != "claimed" && task.Status != "in_progress" {
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("Task '%s' is not in a state to accept contributions (current status: %s)", task.Title, task.Status)})
-	}
-
-	var existingContribution models.Contribution
-	if err := db.DB.Where("task_id = ? AND user_id = ?", contribution.TaskID, contribution.UserID).First(&existingContribution).Error; err == nil {
-		return c.JSON(http.StatusConflict, map[string]string{"error": fmt.Sprintf("User %d has already submitted a contribution for task '%s'", req.UserID, task.Title)})
-	}
-
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to start transaction"})
-	}
-
-	contribution.VerificationStatus = "unverified"
-	if err := tx.Create(&contribution).Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to create contribution: %v", err)})
-	}
-	if err := tx.Model(&task).Update("status", "submitted").Error; err != nil {
-		tx.Rollback()
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to update task status: %v", err)})
+	contribution, err := h.Service.Submit(req.TaskID, req.UserID, req.PRURL, "Contribution submitted (dev mode)")
+	if err != nil {
+		return contributionError(c, err)
 	}
-	tx.Commit()
 	return c.JSON(http.StatusCreated, contribution)
 }
 
@@ -404,15 +931,25 @@ func (h *ContributionHandler) AcceptContribution(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid contribution ID"})
 	}
-	var contribution models.Contribution
-	if err := db.DB.First(&contribution, contributionID).Error; err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "Contribution not found"})
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	contribution, err := h.Access.AuthorizeContribution(user, uint(contributionID))
+	if err != nil {
+		return projectAccessError(c, err)
 	}
 
 	if h.Service == nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Contribution service not initialized"})
 	}
-	if err := h.Service.VerifyAndAcceptContribution(uint(contributionID), contribution.PRURL); err != nil {
+	if contribution.UserID == user.ID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": services.ErrSelfReview.Error()})
+	}
+	if err := h.Service.VerifyAndAcceptContribution(uint(contributionID), contribution.PRURL, &user.ID); err != nil {
+		if errors.Is(err, services.ErrSelfReview) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to accept contribution: %v", err)})
 	}
 
@@ -431,10 +968,20 @@ func (h *ContributionHandler) RejectContribution(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	if _, err := h.Access.AuthorizeContribution(user, uint(contributionID)); err != nil {
+		return projectAccessError(c, err)
+	}
 	if h.Service == nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Contribution service not initialized"})
 	}
-	if err := h.Service.RejectContribution(uint(contributionID), req.Reason); err != nil {
+	if err := h.Service.RejectContribution(uint(contributionID), req.Reason, &user.ID); err != nil {
+		if errors.Is(err, services.ErrContributionNotPending) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to reject contribution: %v", err)})
 	}
 
@@ -442,30 +989,128 @@ func (h *ContributionHandler) RejectContribution(c echo.Context) error {
 }
 
 type MentorHandler struct {
-	Service *services.ContributionService
+	Service *services.MentorService
 }
 
+// mentorError turns an error from MentorService into the matching HTTP
+// response.
+func mentorError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotClaimMentor):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrNotVolunteer),
+		errors.Is(err, services.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyVolunteered),
+		errors.Is(err, services.ErrAlreadyEndorsed),
+		errors.Is(err, services.ErrClaimNotActive),
+		errors.Is(err, services.ErrSelfMentoring),
+		errors.Is(err, services.ErrMaintainerMentor),
+		errors.Is(err, services.ErrTaskNotOpen):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+// EndorseUser lets the mentor assigned to a claim endorse the claim's
+// contributor. The endorsed user is always the claimant; it is not taken from
+// the request.
 func (h *MentorHandler) EndorseUser(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
 	var req struct {
-		MentorID  uint   `json:"mentor_id"`
-		UserID    uint   `json:"user_id"`
-		RelatedID uint   `json:"related_id"`
-		Notes     string `json:"notes"`
+		ClaimID uint   `json:"claim_id"`
+		Notes   string `json:"notes"`
 	}
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
-	if req.MentorID == 0 || req.UserID == 0 || req.RelatedID == 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Mentor ID, User ID, and Related ID are required"})
+	if req.ClaimID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Claim ID is required"})
 	}
-	if h.Service == nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Contribution service not initialized"})
+
+	claim, err := h.Service.Endorse(user, req.ClaimID, req.Notes)
+	if err != nil {
+		return mentorError(c, err)
+	}
+	return c.JSON(http.StatusOK, claim)
+}
+
+// AssignClaimMentor sets or changes the mentor of a claim. A null mentor_id
+// removes the mentor.
+func (h *MentorHandler) AssignClaimMentor(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	claimID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid claim ID"})
+	}
+	var req struct {
+		MentorID *uint `json:"mentor_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+
+	claim, err := h.Service.AssignMentor(user, uint(claimID), req.MentorID)
+	if err != nil {
+		return mentorError(c, err)
+	}
+	return c.JSON(http.StatusOK, claim)
+}
+
+func (h *MentorHandler) ListVolunteers(c echo.Context) error {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	volunteers, err := h.Service.Volunteers(uint(taskID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, volunteers)
+}
+
+func (h *MentorHandler) Volunteer(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
 	}
-	if err := h.Service.MentorEndorsements(req.MentorID, req.UserID, req.RelatedID, req.Notes); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to endorse user: %v", err)})
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{"message": "User endorsed successfully!"})
+	volunteer, err := h.Service.Volunteer(user, uint(taskID), req.Note)
+	if err != nil {
+		return mentorError(c, err)
+	}
+	return c.JSON(http.StatusCreated, volunteer)
+}
+
+func (h *MentorHandler) WithdrawVolunteer(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	if err := h.Service.Withdraw(user, uint(taskID)); err != nil {
+		return mentorError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("No longer volunteering to mentor task %d", taskID)})
 }
 
 type SkillHandler struct{}
@@ -542,11 +1187,13 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 
 type PaymentHandler struct {
 	Service *services.PaymentService
+	Access  *services.ProjectAccessService
 }
 
-func NewPaymentHandler() *PaymentHandler {
+func NewPaymentHandler(access *services.ProjectAccessService) *PaymentHandler {
 	return &PaymentHandler{
 		Service: services.NewPaymentService(),
+		Access:  access,
 	}
 }
 
@@ -590,6 +1237,13 @@ func (h *PaymentHandler) RefundTaskBounty(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
 	}
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	if _, err := h.Access.AuthorizeTask(user, uint(taskID)); err != nil {
+		return projectAccessError(c, err)
+	}
 	if err := h.Service.RefundTaskBounty(uint(taskID), req.Reason); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to refund task bounty: %v", err)})
 	}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"ossyne/internal/config"
+	"ossyne/internal/db"
+	"ossyne/internal/db/dbtest"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"testing"
+)
+
+// login returns a session access token for user.
+func login(t *testing.T, user *models.User) string {
+	t.Helper()
+	tokens, err := services.NewSessionService().Create(user.ID, "handlers_test")
+	if err != nil {
+		t.Fatalf("failed to create session for %s: %v", user.Username, err)
+	}
+	return tokens.AccessToken
+}
+
+func TestMaintainerRoutesForbidden(t *testing.T) {
+	dbtest.Use(t)
+	owner := dbtest.CreateUser(t, "owner")
+	contributor := dbtest.CreateUser(t, "contributor")
+	stranger := dbtest.CreateUser(t, "stranger", models.RoleMaintainer)
+	admin := dbtest.CreateUser(t, "admin", models.RoleAdmin)
+	project := models.Project{OwnerID: owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	dbtest.Create(t, &project)
+	task := models.Task{ProjectID: project.ID, Title: "Task", Status: models.TaskStatusInProgress, ClaimPolicy: models.ClaimPolicyExclusive}
+	dbtest.Create(t, &task)
+	claim := models.Claim{TaskID: task.ID, UserID: contributor.ID, Status: models.ClaimStatusPending}
+	dbtest.Create(t, &claim)
+	contribution := models.Contribution{
+		TaskID: task.ID, UserID: contributor.ID,
+		PRURL: "https://github.com/ossyne/project/pull/1", VerificationStatus: models.VerificationStatusUnverified,
+	}
+	dbtest.Create(t, &contribution)
+
+	routes := []struct {
+		method, path, body string
+	}{
+		{http.MethodPatch, fmt.Sprintf("/api/tasks/%d", task.ID), `{"title":"Renamed"}`},
+		{http.MethodPut, fmt.Sprintf("/api/tasks/%d/archive", task.ID), `{"reason":"spam"}`},
+		{http.MethodPut, fmt.Sprintf("/api/claims/%d/accept", claim.ID), `{}`},
+		{http.MethodPut, fmt.Sprintf("/api/claims/%d/reject", claim.ID), `{}`},
+		{http.MethodPut, fmt.Sprintf("/api/contributions/%d/accept", contribution.ID), `{}`},
+		{http.MethodPut, fmt.Sprintf("/api/contributions/%d/reject", contribution.ID), `{"reason":"no"}`},
+	}
+	users := []*models.User{stranger, admin, contributor}
+
+	server := NewServer(config.Config{AppEnv: config.EnvProduction})
+	for _, user := range users {
+		token := login(t, user)
+		for _, route := range routes {
+			t.Run(user.Username+"/"+route.method+" "+route.path, func(t *testing.T) {
+				rec := serve(t, server, route.method, route.path, token, route.body)
+				if rec.Code != http.StatusForbidden {
+					t.Errorf("%s %s = %d %s, want %d", route.method, route.path, rec.Code, rec.Body, http.StatusForbidden)
+				}
+			})
+		}
+	}
+
+	var gotTask models.Task
+	var gotClaim models.Claim
+	var gotContribution models.Contribution
+	if err := db.DB.First(&gotTask, task.ID).Error; err != nil {
+		t.Fatalf("failed to reload task: %v", err)
+	}
+	if err := db.DB.First(&gotClaim, claim.ID).Error; err != nil {
+		t.Fatalf("failed to reload claim: %v", err)
+	}
+	if err := db.DB.First(&gotContribution, contribution.ID).Error; err != nil {
+		t.Fatalf("failed to reload contribution: %v", err)
+	}
+	if gotTask.Title != task.Title || gotTask.Status != task.Status {
+		t.Errorf("task changed to %q (%s)", gotTask.Title, gotTask.Status)
+	}
+	if gotClaim.Status != models.ClaimStatusPending {
+		t.Errorf("claim status = %s, want %s", gotClaim.Status, models.ClaimStatusPending)
+	}
+	if gotContribution.VerificationStatus != models.VerificationStatusUnverified {
+		t.Errorf("contribution status = %s, want %s", gotContribution.VerificationStatus, models.VerificationStatusUnverified)
+	}
+}
+
+func TestAcceptOwnContributionForbidden(t *testing.T) {
+	dbtest.Use(t)
+	owner := dbtest.CreateUser(t, "owner")
+	project := models.Project{OwnerID: owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	dbtest.Create(t, &project)
+	task := models.Task{ProjectID: project.ID, Title: "Task", Status: models.TaskStatusSubmitted, ClaimPolicy: models.ClaimPolicyExclusive}
+	dbtest.Create(t, &task)
+	dbtest.Create(t, &models.Claim{TaskID: task.ID, UserID: owner.ID, Status: models.ClaimStatusAccepted})
+	contribution := models.Contribution{
+		TaskID: task.ID, UserID: owner.ID,
+		PRURL: "https://github.com/ossyne/project/pull/1", VerificationStatus: models.VerificationStatusUnverified,
+	}
+	dbtest.Create(t, &contribution)
+
+	server := NewServer(config.Config{AppEnv: config.EnvProduction})
+	path := fmt.Sprintf("/api/contributions/%d/accept", contribution.ID)
+	if rec := serve(t, server, http.MethodPut, path, login(t, owner), `{}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("PUT %s = %d %s, want %d", path, rec.Code, rec.Body, http.StatusForbidden)
+	}
+	var got models.Contribution
+	if err := db.DB.First(&got, contribution.ID).Error; err != nil {
+		t.Fatalf("failed to reload contribution: %v", err)
+	}
+	if got.VerificationStatus != models.VerificationStatusUnverified {
+		t.Errorf("contribution status = %s, want %s", got.VerificationStatus, models.VerificationStatusUnverified)
+	}
+}
+
+func TestReviewContributionTokenScope(t *testing.T) {
+	dbtest.Use(t)
+	owner := dbtest.CreateUser(t, "owner")
+	contributor := dbtest.CreateUser(t, "contributor")
+	project := models.Project{OwnerID: owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	dbtest.Create(t, &project)
+	task := models.Task{ProjectID: project.ID, Title: "Task", Status: models.TaskStatusSubmitted, ClaimPolicy: models.ClaimPolicyExclusive}
+	dbtest.Create(t, &task)
+	contribution := models.Contribution{
+		TaskID: task.ID, UserID: contributor.ID,
+		PRURL: "https://github.com/ossyne/project/pull/1", VerificationStatus: models.VerificationStatusUnverified,
+	}
+	dbtest.Create(t, &contribution)
+
+	tokens := services.NewAPITokenService()
+	writeToken, err := tokens.Create(owner.ID, "contributions", []string{models.ScopeContributionsWrite}, 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	projectsToken, err := tokens.Create(owner.ID, "projects", []string{models.ScopeProjectsWrite}, 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	server := NewServer(config.Config{AppEnv: config.EnvProduction})
+	path := fmt.Sprintf("/api/contributions/%d/reject", contribution.ID)
+	if rec := serve(t, server, http.MethodPut, path, writeToken.Token, `{"reason":"no"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("with %s: PUT %s = %d %s, want %d", models.ScopeContributionsWrite, path, rec.Code, rec.Body, http.StatusForbidden)
+	}
+	if rec := serve(t, server, http.MethodPut, path, projectsToken.Token, `{"reason":"no"}`); rec.Code != http.StatusOK {
+		t.Errorf("with %s: PUT %s = %d %s, want %d", models.ScopeProjectsWrite, path, rec.Code, rec.Body, http.StatusOK)
+	}
+}
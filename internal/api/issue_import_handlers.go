@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"strings"
+	"github.com/labstack/echo/v4"
+)
+
+type IssueImportHandler struct {
+	Service *services.IssueImportService
+}
+
+// ImportIssues creates or updates tasks from the open GitHub issues of a
+// project's repository. The optional labels in the body narrow the import to
+// issues carrying all of them.
+func (h *IssueImportHandler) ImportIssues(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	var req struct {
+		Labels []string `json:"labels"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	labels := []string{}
+	for _, label := range req.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	result, err := h.Service.Import(user, uint(projectID), labels)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotGitHubRepo), errors.Is(err, services.ErrTooManyIssues):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrGitHubRequest):
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		}
+		return projectAccessError(c, err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"github.com/labstack/echo/v4"
+)
+
+type ManifestHandler struct {
+	Service *services.ManifestService
+}
+
+// manifestError turns an error from ManifestService into the matching HTTP
+// response.
+func manifestError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidManifest):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrManifestConflict),
+		errors.Is(err, services.ErrIllegalTaskTransition):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+// PlanManifest compares the project with the manifest in the body and
+// returns the changes applying it would make.
+func (h *ManifestHandler) PlanManifest(c echo.Context) error {
+	return h.handle(c, h.Service.Plan)
+}
+
+// ApplyManifest makes the project match the manifest in the body and returns
+// the changes made.
+func (h *ManifestHandler) ApplyManifest(c echo.Context) error {
+	return h.handle(c, h.Service.Apply)
+}
+
+func (h *ManifestHandler) handle(c echo.Context, run func(*models.User, uint, *models.ProjectManifest) (*models.ManifestPlan, error)) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	manifest := new(models.ProjectManifest)
+	if err := c.Bind(manifest); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	plan, err := run(user, uint(projectID), manifest)
+	if err != nil {
+		return manifestError(c, err)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
@@ -2,35 +2,143 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
-	"ossyne/internal/db"
 	"ossyne/internal/models"
+	"ossyne/internal/services"
 	"strings"
 	"github.com/labstack/echo/v4"
 )
 
 type contextKey string
 
-const userContextKey contextKey = "user"
-func AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		authHeader := c.Request().Header.Get("Authorization")
-		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization header is required"})
-		}
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Authorization header format must be Bearer {token}"})
+const (
+	userContextKey     contextKey = "user"
+	sessionContextKey  contextKey = "session"
+	apiTokenContextKey contextKey = "api_token"
+)
+
+// tokenRouteScopes lists the only routes personal access tokens may call and
+// the scope each one needs. Keys are "METHOD path" as registered with echo.
+var tokenRouteScopes = map[string]string{
+	"POST /api/tasks":                                   models.ScopeTasksWrite,
+	"PATCH /api/tasks/:id":                              models.ScopeTasksWrite,
+	"PUT /api/tasks/:id/archive":                        models.ScopeProjectsWrite,
+	"PUT /api/tasks/:id/status":                         models.ScopeTasksWrite,
+	"POST /api/tasks/:id/dependencies":                  models.ScopeTasksWrite,
+	"DELETE /api/tasks/:id/dependencies/:depends_on_id": models.ScopeTasksWrite,
+	"POST /api/tasks/:id/comments":                      models.ScopeTasksWrite,
+	"POST /api/projects/:id/import-issues":              models.ScopeTasksWrite,
+	"POST /api/projects/:id/manifest/plan":              models.ScopeProjectsWrite,
+	"POST /api/projects/:id/manifest/apply":             models.ScopeProjectsWrite,
+	"POST /api/projects/:id/import":                     models.ScopeProjectsWrite,
+	"GET /api/projects/:id/export":                      models.ScopeProjectsExport,
+	"GET /api/projects/:id/time-report":                 models.ScopeProjectsExport,
+	"POST /api/contributions":                           models.ScopeContributionsWrite,
+	"POST /api/tasks/:id/time":                          models.ScopeContributionsWrite,
+	"DELETE /api/time-entries/:id":                      models.ScopeContributionsWrite,
+	"PUT /api/contributions/:id/accept":                 models.ScopeProjectsWrite,
+	"PUT /api/contributions/:id/reject":                 models.ScopeProjectsWrite,
+	"GET /api/contributions/:id/comments":               models.ScopeContributionsRead,
+	"POST /api/contributions/:id/comments":              models.ScopeContributionsWrite,
+	"GET /api/users/me/payments":                        models.ScopePaymentsRead,
+	"GET /api/users/:user_id/payments":                  models.ScopePaymentsRead,
+}
+
+// AuthMiddleware accepts both interactive session tokens and personal access
+// tokens. Personal access tokens are limited to the routes in tokenRouteScopes.
+func AuthMiddleware(sessions *services.SessionService, tokens *services.APITokenService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, errResp := bearerToken(c)
+			if errResp != "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": errResp})
+			}
+			if strings.HasPrefix(token, services.APITokenPrefix) {
+				return authenticateAPIToken(c, next, tokens, token)
+			}
+			user, session, err := sessions.Authenticate(token)
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidSession) {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired token"})
+				}
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate token"})
+			}
+			ctx := context.WithValue(c.Request().Context(), userContextKey, user)
+			ctx = context.WithValue(ctx, sessionContextKey, session)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
 		}
-		token := parts[1]
-		var user models.User
-		result := db.DB.Where("github_access_token = ?", token).First(&user)
-		if result.Error != nil {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired token"})
+	}
+}
+
+func authenticateAPIToken(c echo.Context, next echo.HandlerFunc, tokens *services.APITokenService, token string) error {
+	user, apiToken, err := tokens.Authenticate(token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIToken) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid, expired or revoked API token"})
 		}
-		ctx := context.WithValue(c.Request().Context(), userContextKey, &user)
-		c.SetRequest(c.Request().WithContext(ctx))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to validate token"})
+	}
+	scope, ok := tokenRouteScopes[c.Request().Method+" "+c.Path()]
+	if !ok {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Personal access tokens cannot be used for this endpoint"})
+	}
+	if !apiToken.HasScope(scope) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": fmt.Sprintf("API token is missing the '%s' scope", scope)})
+	}
+	ctx := context.WithValue(c.Request().Context(), userContextKey, user)
+	ctx = context.WithValue(ctx, apiTokenContextKey, apiToken)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return next(c)
+}
+
+func bearerToken(c echo.Context) (string, string) {
+	authHeader := c.Request().Header.Get("Authorization")
+	if authHeader == "" {
+		return "", "Authorization header is required"
+	}
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return "", "Authorization header format must be Bearer {token}"
+	}
+	return parts[1], ""
+}
 
-		return next(c)
+// missingTokenScope reports the scope a personal access token still needs for
+// a request whose route scope is not enough on its own, such as archiving a
+// task through the status endpoint. Session logins are never restricted.
+func missingTokenScope(c echo.Context, scope string) (string, bool) {
+	apiToken, ok := c.Request().Context().Value(apiTokenContextKey).(*models.APIToken)
+	if !ok || apiToken == nil || apiToken.HasScope(scope) {
+		return "", false
+	}
+	return fmt.Sprintf("API token is missing the '%s' scope", scope), true
+}
+
+func currentSession(c echo.Context) *models.Session {
+	session, _ := c.Request().Context().Value(sessionContextKey).(*models.Session)
+	return session
+}
+
+// RequireRole only lets through authenticated users holding at least one of
+// the given roles. It must run after AuthMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+			if !ok || user == nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+			}
+			for _, role := range roles {
+				if user.HasRole(role) {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, map[string]string{"error": fmt.Sprintf("This action requires one of the roles: %s", strings.Join(roles, ", "))})
+		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"time"
+	"github.com/labstack/echo/v4"
+)
+
+type MilestoneHandler struct {
+	Service *services.MilestoneService
+	Access  *services.ProjectAccessService
+}
+
+// milestoneError turns an error from MilestoneService into the matching HTTP
+// response.
+func milestoneError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrMilestoneNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrMilestoneProjectMismatch),
+		errors.Is(err, services.ErrNoFundableTasks):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+// parseDueDate accepts a plain date (due at the end of that day, UTC) or an
+// RFC 3339 timestamp.
+func parseDueDate(value string) (*time.Time, error) {
+	if due, err := time.Parse("2006-01-02", value); err == nil {
+		due = due.Add(24*time.Hour - time.Second)
+		return &due, nil
+	}
+	due, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("due_date must look like 2006-01-02 or 2006-01-02T15:04:05Z")
+	}
+	return &due, nil
+}
+
+// authorizeMilestone loads the milestone and checks user maintains its project.
+func (h *MilestoneHandler) authorizeMilestone(user *models.User, milestoneID uint) (*models.Milestone, error) {
+	milestone, err := h.Service.Get(milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Access.AuthorizeProject(user, milestone.ProjectID); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (h *MilestoneHandler) ListMilestones(c echo.Context) error {
+	var projectID uint64
+	if projectIDStr := c.QueryParam("project_id"); projectIDStr != "" {
+		var err error
+		projectID, err = strconv.ParseUint(projectIDStr, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project_id"})
+		}
+	}
+	milestones, err := h.Service.List(uint(projectID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, milestones)
+}
+
+// GetMilestone returns a milestone together with its tasks.
+func (h *MilestoneHandler) GetMilestone(c echo.Context) error {
+	milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid milestone ID"})
+	}
+	milestone, err := h.Service.Get(uint(milestoneID))
+	if err != nil {
+		return milestoneError(c, err)
+	}
+	tasks, err := h.Service.Tasks(milestone.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"milestone": milestone,
+		"tasks":     tasks,
+	})
+}
+
+func (h *MilestoneHandler) CreateMilestone(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	var req struct {
+		ProjectID   uint   `json:"project_id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Kind        string `json:"kind"`
+		DueDate     string `json:"due_date"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.ProjectID == 0 || req.Title == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Project ID and title are required"})
+	}
+	if req.Kind == "" {
+		req.Kind = models.MilestoneKindMilestone
+	}
+	if !models.IsValidMilestoneKind(req.Kind) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid kind '%s', expected milestone or epic", req.Kind)})
+	}
+	milestone := models.Milestone{
+		ProjectID:   req.ProjectID,
+		Title:       req.Title,
+		Description: req.Description,
+		Kind:        req.Kind,
+	}
+	if req.DueDate != "" {
+		due, err := parseDueDate(req.DueDate)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		milestone.DueDate = due
+	}
+	if _, err := h.Access.AuthorizeProject(user, req.ProjectID); err != nil {
+		return projectAccessError(c, err)
+	}
+
+	if err := h.Service.Create(&milestone); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, milestone)
+}
+
+// UpdateMilestone edits the fields present in the request body. An empty
+// due_date clears the due date.
+func (h *MilestoneHandler) UpdateMilestone(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid milestone ID"})
+	}
+	var req struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		Kind        *string `json:"kind"`
+		DueDate     *string `json:"due_date"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	milestone, err := h.authorizeMilestone(user, uint(milestoneID))
+	if err != nil {
+		return milestoneError(c, err)
+	}
+
+	updates := map[string]interface{}{}
+	if req.Title != nil {
+		if *req.Title == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Title cannot be empty"})
+		}
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Kind != nil {
+		if !models.IsValidMilestoneKind(*req.Kind) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid kind '%s', expected milestone or epic", *req.Kind)})
+		}
+		updates["kind"] = *req.Kind
+	}
+	if req.DueDate != nil {
+		if *req.DueDate == "" {
+			updates["due_date"] = nil
+		} else {
+			due, err := parseDueDate(*req.DueDate)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			updates["due_date"] = *due
+		}
+	}
+	if len(updates) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "No fields to update"})
+	}
+
+	if err := h.Service.Update(milestone, updates); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	milestone, err = h.Service.Get(milestone.ID)
+	if err != nil {
+		return milestoneError(c, err)
+	}
+	return c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) DeleteMilestone(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid milestone ID"})
+	}
+	milestone, err := h.authorizeMilestone(user, uint(milestoneID))
+	if err != nil {
+		return milestoneError(c, err)
+	}
+	if err := h.Service.Delete(milestone.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Milestone '%s' deleted; its %d tasks are no longer assigned to a milestone", milestone.Title, milestone.Progress.TotalTasks)})
+}
+
+// AssignTask moves a task into a milestone of its project. A null
+// milestone_id takes the task out of its milestone.
+func (h *MilestoneHandler) AssignTask(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req struct {
+		MilestoneID *uint `json:"milestone_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	task, err := h.Access.AuthorizeTask(user, uint(taskID))
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+	if err := h.Service.AssignTask(task, req.MilestoneID); err != nil {
+		return milestoneError(c, err)
+	}
+	return c.JSON(http.StatusOK, task)
+}
+
+// FundMilestoneBounty splits a bounty across the milestone's unfunded tasks.
+// Like task bounties, any signed-in user may fund one.
+func (h *MilestoneHandler) FundMilestoneBounty(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid milestone ID"})
+	}
+	var req struct {
+		Amount   float64 `json:"amount"`
+		Currency string  `json:"currency"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "A positive amount is required"})
+	}
+	if req.Currency == "" {
+		req.Currency = "USD"
+	}
+
+	tasks, err := h.Service.FundBounty(uint(milestoneID), user.ID, req.Amount, req.Currency)
+	if err != nil {
+		if errors.Is(err, services.ErrMilestoneNotFound) || errors.Is(err, services.ErrNoFundableTasks) {
+			return milestoneError(c, err)
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to fund milestone bounty: %v", err)})
+	}
+	return c.JSON(http.StatusOK, tasks)
+}
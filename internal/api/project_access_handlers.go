@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"github.com/labstack/echo/v4"
+)
+
+// projectAccessError turns an error from ProjectAccessService into the matching
+// HTTP response.
+func projectAccessError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotProjectMaintainer):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "Only the project owner or a co-maintainer can perform this action"})
+	case errors.Is(err, services.ErrProjectNotFound),
+		errors.Is(err, services.ErrTaskNotFound),
+		errors.Is(err, services.ErrContributionNotFound),
+		errors.Is(err, services.ErrClaimNotFound),
+		errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrMaintainerNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyMaintainer):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to check project access: %v", err)})
+}
+
+type ProjectMaintainerHandler struct {
+	Access *services.ProjectAccessService
+}
+
+func (h *ProjectMaintainerHandler) ListMaintainers(c echo.Context) error {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	maintainers, err := h.Access.ListMaintainers(uint(projectID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, maintainers)
+}
+
+func (h *ProjectMaintainerHandler) AddMaintainer(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	var req struct {
+		UserID uint `json:"user_id"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.UserID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "User ID is required"})
+	}
+	maintainer, err := h.Access.AddMaintainer(user, uint(projectID), req.UserID)
+	if err != nil {
+		return projectAccessError(c, err)
+	}
+	return c.JSON(http.StatusCreated, maintainer)
+}
+
+func (h *ProjectMaintainerHandler) RemoveMaintainer(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+	if err := h.Access.RemoveMaintainer(user, uint(projectID), uint(userID)); err != nil {
+		return projectAccessError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("User %d is no longer a co-maintainer of project %d", userID, projectID)})
+}
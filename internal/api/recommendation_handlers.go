@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"github.com/labstack/echo/v4"
+)
+
+type RecommendationHandler struct {
+	Service *services.RecommendationService
+}
+
+// RecommendedTasks ranks claimable tasks for the signed-in user. The optional
+// limit query parameter caps the number of results.
+func (h *RecommendationHandler) RecommendedTasks(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	var limit int
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+		}
+	}
+	recommendations, err := h.Service.Recommend(user, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, recommendations)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	Service *services.RoleService
+}
+
+func (h *RoleHandler) GrantRole(c echo.Context) error {
+	return h.changeRole(c, models.RoleActionGrant)
+}
+
+func (h *RoleHandler) RevokeRole(c echo.Context) error {
+	return h.changeRole(c, models.RoleActionRevoke)
+}
+
+func (h *RoleHandler) changeRole(c echo.Context, action string) error {
+	actor, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || actor == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+	var req struct {
+		Role   string `json:"role"`
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	if req.Role == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Role is required"})
+	}
+
+	if action == models.RoleActionGrant {
+		err = h.Service.Grant(&actor.ID, uint(userID), req.Role, req.Reason)
+	} else {
+		err = h.Service.Revoke(&actor.ID, uint(userID), req.Role, req.Reason)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": fmt.Sprintf("User with ID %d not found", userID)})
+		case errors.Is(err, services.ErrRoleUnchanged), errors.Is(err, services.ErrLastAdmin):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to %s role: %v", action, err)})
+	}
+
+	verb := "granted to"
+	if action == models.RoleActionRevoke {
+		verb = "revoked from"
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Role '%s' %s user %d", req.Role, verb, userID)})
+}
+
+func (h *RoleHandler) ListRoleChanges(c echo.Context) error {
+	var userID uint64
+	if userIDStr := c.QueryParam("user_id"); userIDStr != "" {
+		var err error
+		userID, err = strconv.ParseUint(userIDStr, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user_id"})
+		}
+	}
+	entries, err := h.Service.ListChanges(uint(userID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": fmt.Sprintf("Failed to list role changes: %v", err)})
+	}
+	return c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"ossyne/internal/config"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Server is the HTTP API together with the claim service, whose expiry loop
+// the caller starts alongside it.
+type Server struct {
+	Echo   *echo.Echo
+	Claims *services.ClaimService
+}
+
+// NewServer builds the services and handlers and registers every route.
+// The /dev routes are only mounted when cfg.DevRoutesEnabled().
+func NewServer(cfg config.Config) *Server {
+	e := echo.New()
+	e.Use(middleware.Recover())
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	paymentService := services.NewPaymentService()
+	taskStates := services.NewTaskStateService()
+	contributionService := services.NewContributionService(paymentService, taskStates)
+	contributionService.PaymentService = paymentService
+	sessionService := services.NewSessionService()
+	apiTokenService := services.NewAPITokenService()
+	roleService := services.NewRoleService()
+	projectAccess := services.NewProjectAccessService()
+	claimService := services.NewClaimService(taskStates)
+	taskService := services.NewTaskService(paymentService, taskStates)
+	taskDependencies := services.NewTaskDependencyService()
+	milestoneService := services.NewMilestoneService(paymentService)
+	commentService := services.NewCommentService(projectAccess)
+	mentorService := services.NewMentorService(projectAccess)
+	recommendationService := services.NewRecommendationService(taskDependencies)
+	issueImportService := services.NewIssueImportService(projectAccess, cfg.GitHubAPIURL)
+	manifestService := services.NewManifestService(projectAccess, taskService)
+	exportService := services.NewExportService(projectAccess)
+	timeTrackingService := services.NewTimeTrackingService(projectAccess)
+	authService := services.NewAuthService(cfg, sessionService, roleService)
+
+	userHandler := &UserHandler{}
+	projectHandler := &ProjectHandler{Access: projectAccess}
+	taskHandler := &TaskHandler{Access: projectAccess, States: taskStates, Tasks: taskService, Dependencies: taskDependencies, Milestones: milestoneService, Claims: claimService}
+	claimHandler := &ClaimHandler{Service: claimService, Access: projectAccess}
+	contributionHandler := &ContributionHandler{Service: contributionService, Access: projectAccess}
+	mentorHandler := &MentorHandler{Service: mentorService}
+	skillHandler := &SkillHandler{}
+	userSkillHandler := &UserSkillHandler{}
+	paymentHandler := NewPaymentHandler(projectAccess)
+	sessionHandler := &SessionHandler{Service: sessionService}
+	apiTokenHandler := &APITokenHandler{Service: apiTokenService}
+	deviceAuthHandler := &DeviceAuthHandler{Service: authService.Devices}
+	roleHandler := &RoleHandler{Service: roleService}
+	maintainerHandler := &ProjectMaintainerHandler{Access: projectAccess}
+	milestoneHandler := &MilestoneHandler{Service: milestoneService, Access: projectAccess}
+	commentHandler := &CommentHandler{Service: commentService}
+	recommendationHandler := &RecommendationHandler{Service: recommendationService}
+	issueImportHandler := &IssueImportHandler{Service: issueImportService}
+	manifestHandler := &ManifestHandler{Service: manifestService}
+	exportHandler := &ExportHandler{Service: exportService, Access: projectAccess}
+	timeTrackingHandler := &TimeTrackingHandler{Service: timeTrackingService}
+
+	e.GET("/auth/github", echo.WrapHandler(http.HandlerFunc(authService.HandleGitHubLogin)))
+	e.GET("/auth/github/callback", echo.WrapHandler(http.HandlerFunc(authService.HandleGitHubCallback)))
+	e.POST("/auth/refresh", sessionHandler.RefreshSession)
+	e.GET("/auth/device", echo.WrapHandler(http.HandlerFunc(authService.HandleDeviceVerification)))
+	e.POST("/auth/device/confirm", echo.WrapHandler(http.HandlerFunc(authService.HandleDeviceConfirm)))
+	e.POST("/auth/device/code", deviceAuthHandler.RequestDeviceCode)
+	e.POST("/auth/device/token", deviceAuthHandler.PollDeviceToken)
+	e.GET("/tasks", taskHandler.ListTasks)//keeping this public for browsing
+	e.GET("/tasks/:id/history", taskHandler.GetTaskHistory)
+	e.GET("/tasks/:id/dependencies", taskHandler.GetTaskDependencies)
+	e.GET("/tasks/:id/comments", commentHandler.ListTaskComments)
+	e.GET("/tasks/:id/mentors", mentorHandler.ListVolunteers)
+	e.GET("/tasks/:id/time", timeTrackingHandler.GetTaskTime)
+	e.GET("/projects", projectHandler.ListProjects)
+	e.GET("/milestones", milestoneHandler.ListMilestones)
+	e.GET("/milestones/:id", milestoneHandler.GetMilestone)
+	//Authenticated Routes
+	apiGroup := e.Group("/api")
+	apiGroup.Use(AuthMiddleware(sessionService, apiTokenService))
+	apiGroup.GET("/auth/sessions", sessionHandler.ListSessions)
+	apiGroup.DELETE("/auth/sessions/:id", sessionHandler.RevokeSession)
+	apiGroup.POST("/auth/logout", sessionHandler.Logout)
+	apiGroup.GET("/auth/tokens", apiTokenHandler.ListTokens)
+	apiGroup.POST("/auth/tokens", apiTokenHandler.CreateToken)
+	apiGroup.DELETE("/auth/tokens/:id", apiTokenHandler.RevokeToken)
+	apiGroup.POST("/projects", projectHandler.CreateProject)
+	apiGroup.PATCH("/projects/:id", projectHandler.UpdateProject)
+	apiGroup.DELETE("/projects/:id", projectHandler.DeleteProject)
+	apiGroup.POST("/projects/:id/restore", projectHandler.RestoreProject)
+	apiGroup.POST("/projects/:id/maintainers", maintainerHandler.AddMaintainer)
+	apiGroup.DELETE("/projects/:id/maintainers/:user_id", maintainerHandler.RemoveMaintainer)
+	apiGroup.POST("/projects/:id/import-issues", issueImportHandler.ImportIssues)
+	apiGroup.POST("/projects/:id/manifest/plan", manifestHandler.PlanManifest)
+	apiGroup.POST("/projects/:id/manifest/apply", manifestHandler.ApplyManifest)
+	apiGroup.GET("/projects/:id/export", exportHandler.ExportProject)
+	apiGroup.POST("/projects/:id/import", exportHandler.ImportProject)
+	apiGroup.GET("/projects/:id/time-report", timeTrackingHandler.ProjectTimeReport)
+	apiGroup.POST("/tasks", taskHandler.CreateTask)
+	apiGroup.PATCH("/tasks/:id", taskHandler.UpdateTask)
+	apiGroup.PUT("/tasks/:id/archive", taskHandler.ArchiveTask)
+	apiGroup.PUT("/tasks/:id/status", taskHandler.UpdateTaskStatus)
+	apiGroup.POST("/tasks/:id/dependencies", taskHandler.AddTaskDependency)
+	apiGroup.DELETE("/tasks/:id/dependencies/:depends_on_id", taskHandler.RemoveTaskDependency)
+	apiGroup.PUT("/tasks/:id/milestone", milestoneHandler.AssignTask)
+	apiGroup.POST("/tasks/:id/unclaim", claimHandler.Unclaim)
+	apiGroup.POST("/tasks/:id/comments", commentHandler.CreateTaskComment)
+	apiGroup.POST("/tasks/:id/mentors", mentorHandler.Volunteer)
+	apiGroup.DELETE("/tasks/:id/mentors", mentorHandler.WithdrawVolunteer)
+	apiGroup.POST("/tasks/:id/time", timeTrackingHandler.LogTime)
+	apiGroup.DELETE("/time-entries/:id", timeTrackingHandler.DeleteTimeEntry)
+	apiGroup.GET("/contributions/:id/comments", commentHandler.ListContributionComments)
+	apiGroup.POST("/contributions/:id/comments", commentHandler.CreateContributionComment)
+	apiGroup.PATCH("/comments/:id", commentHandler.UpdateComment)
+	apiGroup.DELETE("/comments/:id", commentHandler.DeleteComment)
+	apiGroup.POST("/milestones", milestoneHandler.CreateMilestone)
+	apiGroup.PATCH("/milestones/:id", milestoneHandler.UpdateMilestone)
+	apiGroup.DELETE("/milestones/:id", milestoneHandler.DeleteMilestone)
+	apiGroup.POST("/milestones/:id/fund", milestoneHandler.FundMilestoneBounty)
+	apiGroup.POST("/claims", claimHandler.CreateClaim)
+	apiGroup.GET("/claims/pending", claimHandler.ListPendingClaims)
+	apiGroup.PUT("/claims/:id/accept", claimHandler.AcceptClaim)
+	apiGroup.PUT("/claims/:id/reject", claimHandler.RejectClaim)
+	apiGroup.PUT("/claims/:id/mentor", mentorHandler.AssignClaimMentor)
+	apiGroup.POST("/contributions", contributionHandler.CreateContribution)
+	apiGroup.PUT("/contributions/:id/accept", contributionHandler.AcceptContribution)
+	apiGroup.PUT("/contributions/:id/reject", contributionHandler.RejectContribution)
+	apiGroup.POST("/mentor/endorse", mentorHandler.EndorseUser)
+	apiGroup.POST("/bounties/fund", paymentHandler.FundTaskBounty)
+	apiGroup.PUT("/bounties/refund/:id", paymentHandler.RefundTaskBounty)
+	apiGroup.GET("/users/me", userHandler.GetMe)
+	apiGroup.GET("/users/me/payments", paymentHandler.GetMyPayments)
+	apiGroup.GET("/users/me/recommended-tasks", recommendationHandler.RecommendedTasks)
+	apiGroup.GET("/users/:user_id/payments", paymentHandler.GetUserPayments)
+	adminGroup := apiGroup.Group("/admin")
+	adminOnly := RequireRole(models.RoleAdmin)
+	adminGroup.POST("/skills", skillHandler.CreateSkill, RequireRole(models.RoleAdmin, models.RoleMaintainer))
+	adminGroup.POST("/users/skills", userSkillHandler.AddUserSkill, adminOnly)
+	adminGroup.POST("/users/:id/roles/grant", roleHandler.GrantRole, adminOnly)
+	adminGroup.POST("/users/:id/roles/revoke", roleHandler.RevokeRole, adminOnly)
+	adminGroup.GET("/role-changes", roleHandler.ListRoleChanges, adminOnly)
+
+	//Public routes
+	e.GET("/users/:id", userHandler.GetUser)
+	e.GET("/users/:id/projects", projectHandler.ListUserProjects)
+	e.GET("/users/:id/time", timeTrackingHandler.GetUserTime)
+	e.GET("/projects/:id/maintainers", maintainerHandler.ListMaintainers)
+	e.GET("/skills", skillHandler.ListSkills)
+	e.GET("/users/:user_id/skills", userSkillHandler.ListUserSkills)
+	e.GET("/claims", claimHandler.ListClaims)
+	e.GET("/contributions", contributionHandler.ListContributions)
+
+	//Development-only routes
+	if cfg.DevRoutesEnabled() {
+		fmt.Println("[WARNING]: Development routes under /dev are enabled; they skip authentication")
+		devGroup := e.Group("/dev")
+		devGroup.POST("/users/create", userHandler.CreateUser)
+		devGroup.POST("/claims", claimHandler.CreateClaimDev)
+		devGroup.POST("/contributions", contributionHandler.CreateContributionDev)
+		devGroup.GET("/users/:user_id/payments", paymentHandler.GetUserPayments)
+	}
+
+	return &Server{Echo: e, Claims: claimService}
+}
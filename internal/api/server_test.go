@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"ossyne/internal/config"
+	"ossyne/internal/db"
+	"ossyne/internal/db/dbtest"
+	"ossyne/internal/models"
+	"strings"
+	"testing"
+	"github.com/labstack/echo/v4"
+)
+
+// serve sends a JSON request through the server's router and returns the
+// recorded response. A non-empty token is sent as a bearer token.
+func serve(t *testing.T, server *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.Echo.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDevRoutesMounted(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		wantMounted bool
+	}{
+		{name: "development with dev routes", cfg: config.Config{AppEnv: config.EnvDevelopment, EnableDevRoutes: true}, wantMounted: true},
+		{name: "development without dev routes", cfg: config.Config{AppEnv: config.EnvDevelopment}},
+		{name: "staging with dev routes", cfg: config.Config{AppEnv: config.EnvStaging, EnableDevRoutes: true}},
+		{name: "production with dev routes", cfg: config.Config{AppEnv: config.EnvProduction, EnableDevRoutes: true}},
+		{name: "production without dev routes", cfg: config.Config{AppEnv: config.EnvProduction}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(tt.cfg)
+			// An empty body is rejected before the database is touched, so a
+			// mounted route answers 400 and a missing one 404.
+			for _, path := range []string{"/dev/claims", "/dev/contributions"} {
+				rec := serve(t, server, http.MethodPost, path, "", "{}")
+				mounted := rec.Code != http.StatusNotFound
+				if mounted != tt.wantMounted {
+					t.Errorf("POST %s = %d, mounted %v, want %v", path, rec.Code, mounted, tt.wantMounted)
+				}
+			}
+		})
+	}
+}
+
+func TestDevClaimAndContribution(t *testing.T) {
+	dbtest.Use(t)
+	owner := dbtest.CreateUser(t, "owner")
+	contributor := dbtest.CreateUser(t, "contributor")
+	stranger := dbtest.CreateUser(t, "stranger")
+	project := models.Project{OwnerID: owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	dbtest.Create(t, &project)
+	task := models.Task{ProjectID: project.ID, Title: "Task", Status: models.TaskStatusOpen, ClaimPolicy: models.ClaimPolicyExclusive}
+	dbtest.Create(t, &task)
+
+	server := NewServer(config.Config{AppEnv: config.EnvDevelopment, EnableDevRoutes: true})
+	claimBody := func(userID uint) string {
+		body, _ := json.Marshal(map[string]uint{"task_id": task.ID, "user_id": userID})
+		return string(body)
+	}
+	contributionBody := func(userID uint) string {
+		body, _ := json.Marshal(map[string]interface{}{"task_id": task.ID, "user_id": userID, "pr_url": "https://github.com/ossyne/project/pull/1"})
+		return string(body)
+	}
+
+	steps := []struct {
+		name       string
+		path, body string
+		wantCode   int
+		wantTask   string
+	}{
+		{name: "contribution to an open task", path: "/dev/contributions", body: contributionBody(contributor.ID), wantCode: http.StatusConflict, wantTask: models.TaskStatusOpen},
+		{name: "claim is accepted straight away", path: "/dev/claims", body: claimBody(contributor.ID), wantCode: http.StatusCreated, wantTask: models.TaskStatusClaimed},
+		{name: "second claim on an exclusive task", path: "/dev/claims", body: claimBody(stranger.ID), wantCode: http.StatusConflict, wantTask: models.TaskStatusClaimed},
+		{name: "contribution from a user without a claim", path: "/dev/contributions", body: contributionBody(stranger.ID), wantCode: http.StatusForbidden, wantTask: models.TaskStatusClaimed},
+		{name: "contribution submits the task", path: "/dev/contributions", body: contributionBody(contributor.ID), wantCode: http.StatusCreated, wantTask: models.TaskStatusSubmitted},
+		{name: "contribution submitted twice", path: "/dev/contributions", body: contributionBody(contributor.ID), wantCode: http.StatusConflict, wantTask: models.TaskStatusSubmitted},
+	}
+	// The steps build on each other, so they run in order and stop at the
+	// first failure.
+	for _, step := range steps {
+		if !t.Run(step.name, func(t *testing.T) {
+			rec := serve(t, server, http.MethodPost, step.path, "", step.body)
+			if rec.Code != step.wantCode {
+				t.Fatalf("POST %s = %d %s, want %d", step.path, rec.Code, rec.Body, step.wantCode)
+			}
+			var got models.Task
+			if err := db.DB.First(&got, task.ID).Error; err != nil {
+				t.Fatalf("failed to reload task: %v", err)
+			}
+			if got.Status != step.wantTask {
+				t.Fatalf("task status = %s, want %s", got.Status, step.wantTask)
+			}
+		}) {
+			return
+		}
+	}
+}
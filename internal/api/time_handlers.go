@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"ossyne/internal/models"
+	"ossyne/internal/services"
+	"strconv"
+	"time"
+	"github.com/labstack/echo/v4"
+)
+
+type TimeTrackingHandler struct {
+	Service *services.TimeTrackingService
+}
+
+// logTimeRequest takes the duration in time.ParseDuration form, such as
+// "2h30m" or "45m". SpentOn is a YYYY-MM-DD date and defaults to today.
+type logTimeRequest struct {
+	Duration string `json:"duration"`
+	SpentOn  string `json:"spent_on"`
+	Note     string `json:"note"`
+}
+
+// timeError turns an error from TimeTrackingService into the matching HTTP
+// response.
+func timeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTimeEntry):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrTimeEntryNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrNoAcceptedClaim),
+		errors.Is(err, services.ErrNotTimeEntryAuthor):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	return projectAccessError(c, err)
+}
+
+func (h *TimeTrackingHandler) LogTime(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	var req logTimeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request payload"})
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid duration, expected a value such as 2h30m or 45m"})
+	}
+	spentOn := time.Now()
+	if req.SpentOn != "" {
+		spentOn, err = time.ParseInLocation("2006-01-02", req.SpentOn, time.Local)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid spent_on date, expected YYYY-MM-DD"})
+		}
+	}
+	entry, err := h.Service.LogTime(user, uint(taskID), int(duration.Round(time.Minute)/time.Minute), spentOn, req.Note)
+	if err != nil {
+		return timeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *TimeTrackingHandler) DeleteTimeEntry(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid time entry ID"})
+	}
+	if err := h.Service.DeleteEntry(user, uint(entryID)); err != nil {
+		return timeError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": fmt.Sprintf("Time entry %d deleted", entryID)})
+}
+
+func (h *TimeTrackingHandler) GetTaskTime(c echo.Context) error {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid task ID"})
+	}
+	summary, err := h.Service.ForTask(uint(taskID))
+	if err != nil {
+		return timeError(c, err)
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+func (h *TimeTrackingHandler) GetUserTime(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid user ID"})
+	}
+	summary, err := h.Service.ForUser(uint(userID))
+	if err != nil {
+		return timeError(c, err)
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
+// ProjectTimeReport compares estimated and logged hours across the project's
+// tasks. Only maintainers may see it.
+func (h *TimeTrackingHandler) ProjectTimeReport(c echo.Context) error {
+	user, ok := c.Request().Context().Value(userContextKey).(*models.User)
+	if !ok || user == nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not authenticated"})
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid project ID"})
+	}
+	report, err := h.Service.ProjectReport(user, uint(projectID))
+	if err != nil {
+		return timeError(c, err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"ossyne/internal/services"
+	"strings"
 )
 
 type APIClient struct {
@@ -17,24 +18,47 @@ type APIClient struct {
 
 func NewAPIClient() *APIClient {
 	return &APIClient{
-		BaseURL: "http://localhost:8080/api",
+		BaseURL: ServerURL() + "/api",
 		Client:  &http.Client{},
-		Keyring: services.KeyringService{},
+		Keyring: newKeyring(),
 	}
 }
 
+// ServerURL returns the server root that BaseURL's API group is mounted on.
+func (c *APIClient) ServerURL() string {
+	return strings.TrimSuffix(c.BaseURL, "/api")
+}
+
 func (c *APIClient) DoAuthenticatedRequest(method, endpoint string, payload interface{}) (*http.Response, error) {
 	token, err := c.Keyring.GetToken()
 	if err != nil {
 		return nil, err
 	}
-	var body io.Reader
+	var jsonPayload []byte
 	if payload != nil {
-		jsonPayload, err := json.Marshal(payload)
+		jsonPayload, err = json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonPayload)
+	}
+	resp, err := c.doWithToken(method, endpoint, jsonPayload, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The session token may simply have expired; try to rotate it once.
+	token, refreshErr := services.RefreshStoredSession(c.Client, c.ServerURL(), &c.Keyring)
+	if refreshErr != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return c.doWithToken(method, endpoint, jsonPayload, token)
+}
+
+func (c *APIClient) doWithToken(method, endpoint string, jsonPayload []byte, token string) (*http.Response, error) {
+	var body io.Reader
+	if jsonPayload != nil {
+		body = bytes.NewReader(jsonPayload)
 	}
 	req, err := http.NewRequest(method, c.BaseURL+endpoint, body)
 	if err != nil {
@@ -44,4 +68,4 @@ func (c *APIClient) DoAuthenticatedRequest(method, endpoint string, payload inte
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	return c.Client.Do(req)
-}
\ No newline at end of file
+}
@@ -1,16 +1,33 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"time"
+	"ossyne/internal/models"
 	"ossyne/internal/services"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 )
 
+// cliCallbackURL is where 'osm auth login' listens for the session token. The
+// server only redirects there if it is on its CLI_CALLBACK_URLS allowlist.
+const (
+	cliCallbackAddr = "127.0.0.1:9999"
+	cliCallbackURL  = "http://" + cliCallbackAddr + "/auth/cli/callback"
+)
+
 func NewAuthCmd() *cobra.Command {
 	authCmd := &cobra.Command{
 		Use:   "auth",
@@ -18,48 +35,77 @@ func NewAuthCmd() *cobra.Command {
 		Long:  `The auth command provides tools to log in and log out of OSSYNE.`,
 	}
 
-	keyring := services.KeyringService{}
 	loginCmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log in to OSSYNE using a provider",
-		Long:  `Launches a web browser to authenticate with an external provider like GitHub.`,
+		Long: `Launches a web browser to authenticate with an external provider like GitHub.
+
+Use --device on machines without a local browser (SSH sessions, containers):
+the CLI prints a short code to enter on any other device and waits for approval.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			keyring := newKeyring()
 			provider, _ := cmd.Flags().GetString("provider")
 			if provider != "github" {
 				fmt.Println("Error: Currently, only 'github' is a supported provider.")
 				return
 			}
-			tokenChan := make(chan string)
-			errChan := make(chan error)			
-			server := &http.Server{Addr: ":9999"}			
-			http.HandleFunc("/auth/cli/callback", func(w http.ResponseWriter, r *http.Request) {
+			if device, _ := cmd.Flags().GetBool("device"); device {
+				runDeviceLogin(&keyring)
+				return
+			}
+			state, err := newLoginState()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error starting login: %v\n", err)
+				return
+			}
+			tokenChan := make(chan [2]string, 1)
+			errChan := make(chan error, 1)
+			mux := http.NewServeMux()
+			server := &http.Server{Addr: cliCallbackAddr, Handler: mux}
+			mux.HandleFunc("/auth/cli/callback", func(w http.ResponseWriter, r *http.Request) {
+				// Anything on this machine can reach the callback, so only
+				// the redirect carrying this login's state is accepted.
+				got := r.URL.Query().Get("state")
+				if subtle.ConstantTimeCompare([]byte(got), []byte(state)) != 1 {
+					http.Error(w, "Login state does not match. Start again with 'osm auth login'.", http.StatusBadRequest)
+					return
+				}
 				token := r.URL.Query().Get("token")
 				if token == "" {
-					errChan <- fmt.Errorf("did not receive token in callback")
+					http.Error(w, "Did not receive a token.", http.StatusBadRequest)
+					select {
+					case errChan <- fmt.Errorf("did not receive token in callback"):
+					default:
+					}
 					return
 				}
-				tokenChan <- token
+				select {
+				case tokenChan <- [2]string{token, r.URL.Query().Get("refresh_token")}:
+				default:
+				}
 				fmt.Fprintln(w, "Authentication successful! You can close this browser window now.")
 			})
-			
+
 			go func() {
 				if err := server.ListenAndServe(); err != http.ErrServerClosed {
 					errChan <- fmt.Errorf("failed to start local callback server: %w", err)
 				}
 			}()
 
-			const remoteAuthURL = "http://localhost:8080/auth/github"
+			params := url.Values{}
+			params.Set("redirect_uri", cliCallbackURL)
+			params.Set("state", state)
+			remoteAuthURL := ServerURL() + "/auth/github?" + params.Encode()
 			fmt.Println("Your browser should open for authentication.")
 			fmt.Printf("If it doesn't, please navigate to this URL: %s\n", remoteAuthURL)
-			err := browser.OpenURL(remoteAuthURL)
-			if err != nil {
+			if err := browser.OpenURL(remoteAuthURL); err != nil {
 				fmt.Fprintf(os.Stderr, "Error opening browser: %v\n", err)
 			}
-			
+
 			select {
-			case token := <-tokenChan:
-				fmt.Println("Successfully received access token.")
-				if err := keyring.SetToken(token); err != nil {
+			case tokens := <-tokenChan:
+				fmt.Println("Successfully received session token.")
+				if err := keyring.SetSession(tokens[0], tokens[1]); err != nil {
 					fmt.Fprintf(os.Stderr, "Error storing token: %v\n", err)
 				} else {
 					fmt.Println("Authentication successful. You are now logged in.")
@@ -75,13 +121,18 @@ func NewAuthCmd() *cobra.Command {
 		},
 	}
 	loginCmd.Flags().StringP("provider", "p", "github", "The authentication provider to use (e.g., 'github')")
+	loginCmd.Flags().Bool("device", false, "Log in with a one-time code instead of a local browser redirect")
 	authCmd.AddCommand(loginCmd)
 
 	logoutCmd := &cobra.Command{
 		Use:   "logout",
 		Short: "Log out of OSSYNE",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := keyring.ClearToken(); err != nil {
+			apiClient := NewAPIClient()
+			if resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/auth/logout", nil); err == nil {
+				resp.Body.Close()
+			}
+			if err := apiClient.Keyring.ClearToken(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error logging out: %v\n", err)
 			} else {
 				fmt.Println("You have been logged out.")
@@ -89,6 +140,405 @@ func NewAuthCmd() *cobra.Command {
 		},
 	}
 	authCmd.AddCommand(logoutCmd)
+	authCmd.AddCommand(newStatusCmd())
+	authCmd.AddCommand(newSessionsCmd())
+	authCmd.AddCommand(newTokenCmd())
 
 	return authCmd
-}
\ No newline at end of file
+}
+
+// newLoginState returns the random value a browser login must hand back to
+// the local callback.
+func newLoginState() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate login state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func runDeviceLogin(keyring *services.KeyringService) {
+	apiClient := NewAPIClient()
+	serverURL := apiClient.ServerURL()
+
+	resp, err := apiClient.Client.Post(serverURL+"/auth/device/code", "application/json", nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not connect to the OSM server at %s. Is it running?\n", serverURL)
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading response: %v\n", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Error starting device login: %s\n", string(body))
+		return
+	}
+	var code services.DeviceCodeResponse
+	if err := json.Unmarshal(body, &code); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing device code response: %v\n", err)
+		return
+	}
+
+	fmt.Printf("To log in, open %s on any device\n", code.VerificationURI)
+	fmt.Printf("and enter the code: %s\n\n", code.UserCode)
+	fmt.Printf("Or open this link directly: %s\n", code.VerificationURIComplete)
+	fmt.Println("Waiting for approval...")
+
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	payload, _ := json.Marshal(map[string]string{"device_code": code.DeviceCode})
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		resp, err := apiClient.Client.Post(serverURL+"/auth/device/token", "application/json", bytes.NewReader(payload))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error polling for approval: %v\n", err)
+			return
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading response: %v\n", err)
+			return
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			var tokens services.SessionTokens
+			if err := json.Unmarshal(body, &tokens); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing session tokens: %v\n", err)
+				return
+			}
+			if err := keyring.SetSession(tokens.AccessToken, tokens.RefreshToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Error storing token: %v\n", err)
+				return
+			}
+			fmt.Println("Authentication successful. You are now logged in.")
+			return
+		}
+
+		var pollErr map[string]string
+		json.Unmarshal(body, &pollErr)
+		switch pollErr["error"] {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			fmt.Fprintln(os.Stderr, "Authentication failed: the login request was denied.")
+			return
+		case "expired_token":
+			fmt.Fprintln(os.Stderr, "Authentication failed: the code expired. Please run 'osm auth login --device' again.")
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Authentication failed: %s\n", string(body))
+			return
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Authentication timed out.")
+}
+
+func newSessionsCmd() *cobra.Command {
+	sessionsCmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage your active login sessions",
+		Long:  `List and revoke the sessions the OSSYNE server has issued for your account.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List active sessions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, "/auth/sessions", nil)
+			if err != nil {
+				fmt.Printf("Error listing sessions: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error listing sessions: %s\n", string(body))
+				return
+			}
+
+			var sessions []models.Session
+			if err := json.Unmarshal(body, &sessions); err != nil {
+				fmt.Printf("Error parsing sessions: %v\n", err)
+				return
+			}
+			if len(sessions) == 0 {
+				fmt.Println("No active sessions.")
+				return
+			}
+
+			fmt.Println("--- Sessions ---")
+			for _, s := range sessions {
+				current := ""
+				if s.Current {
+					current = " (current)"
+				}
+				lastUsed := "never"
+				if s.LastUsedAt != nil {
+					lastUsed = s.LastUsedAt.Format("2006-01-02 15:04")
+				}
+				fmt.Printf("ID: %d%s, Created: %s, Last Used: %s, Client: %s\n",
+					s.ID, current, s.CreatedAt.Format("2006-01-02 15:04"), lastUsed, s.UserAgent)
+			}
+		},
+	}
+	sessionsCmd.AddCommand(listCmd)
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <session-id>",
+		Short: "Revoke a session",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sessionID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid session ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/auth/sessions/%d", sessionID), nil)
+			if err != nil {
+				fmt.Printf("Error revoking session: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error revoking session: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Session %d revoked.\n", sessionID)
+		},
+	}
+	sessionsCmd.AddCommand(revokeCmd)
+
+	return sessionsCmd
+}
+
+func newTokenCmd() *cobra.Command {
+	tokenCmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage personal access tokens for automation",
+		Long: `Personal access tokens let CI pipelines and scripts call the OSSYNE API
+without a browser login. Each token is limited to the scopes it was created with:
+
+  tasks:write          create and edit tasks, import issues
+  projects:write       archive and reopen tasks, accept and reject contributions,
+                       apply .ossyne.yml, import projects
+  contributions:read   read contribution review comments
+  contributions:write  submit contributions
+  payments:read        read payment history
+  projects:export      export project data for reports
+
+Send the token as "Authorization: Bearer <token>".`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a personal access token",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			name, _ := cmd.Flags().GetString("name")
+			scopes, _ := cmd.Flags().GetStringSlice("scope")
+			expiresIn, _ := cmd.Flags().GetInt("expires-in")
+
+			apiClient := NewAPIClient()
+			payloadMap := map[string]interface{}{
+				"name":            name,
+				"scopes":          scopes,
+				"expires_in_days": expiresIn,
+			}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/auth/tokens", payloadMap)
+			if err != nil {
+				fmt.Printf("Error creating token: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error creating token: %s\n", string(body))
+				return
+			}
+
+			var created services.CreatedAPIToken
+			if err := json.Unmarshal(body, &created); err != nil {
+				fmt.Printf("Error parsing token: %v\n", err)
+				return
+			}
+			fmt.Printf("Token %d (%s) created with scopes %v.\n", created.ID, created.Name, []string(created.Scopes))
+			fmt.Println("Copy it now, it will not be shown again:")
+			fmt.Println(created.Token)
+		},
+	}
+	createCmd.Flags().StringP("name", "n", "", "Name to recognise the token by, e.g. 'github-actions'")
+	createCmd.Flags().StringSliceP("scope", "s", nil, "Scope to grant (repeatable): tasks:write, projects:write, contributions:read, contributions:write, payments:read, projects:export")
+	createCmd.Flags().Int("expires-in", 90, "Days until the token expires (0 for no expiry)")
+	createCmd.MarkFlagRequired("name")
+	createCmd.MarkFlagRequired("scope")
+	tokenCmd.AddCommand(createCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List personal access tokens",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, "/auth/tokens", nil)
+			if err != nil {
+				fmt.Printf("Error listing tokens: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error listing tokens: %s\n", string(body))
+				return
+			}
+
+			var tokens []models.APIToken
+			if err := json.Unmarshal(body, &tokens); err != nil {
+				fmt.Printf("Error parsing tokens: %v\n", err)
+				return
+			}
+			if len(tokens) == 0 {
+				fmt.Println("No personal access tokens.")
+				return
+			}
+
+			fmt.Println("--- Tokens ---")
+			for _, t := range tokens {
+				expires := "never"
+				if t.ExpiresAt != nil {
+					expires = t.ExpiresAt.Format("2006-01-02")
+					if time.Now().After(*t.ExpiresAt) {
+						expires += " (expired)"
+					}
+				}
+				lastUsed := "never"
+				if t.LastUsedAt != nil {
+					lastUsed = t.LastUsedAt.Format("2006-01-02 15:04")
+				}
+				fmt.Printf("ID: %d, Name: %s, Scopes: %v, Expires: %s, Last Used: %s\n",
+					t.ID, t.Name, []string(t.Scopes), expires, lastUsed)
+			}
+		},
+	}
+	tokenCmd.AddCommand(listCmd)
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke <token-id>",
+		Short: "Revoke a personal access token",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			tokenID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid token ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/auth/tokens/%d", tokenID), nil)
+			if err != nil {
+				fmt.Printf("Error revoking token: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error revoking token: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Token %d revoked.\n", tokenID)
+		},
+	}
+	tokenCmd.AddCommand(revokeCmd)
+
+	return tokenCmd
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show login state and where credentials are stored",
+		Long: fmt.Sprintf(`Reports the active profile and server, which credential store holds your
+tokens, and whether you are logged in.
+
+Tokens live in the OS keyring when one is available. Otherwise they go to an
+encrypted file keyed by $%s or $%s.
+Set $%s to 'keyring' or 'file' to force a backend.`,
+			services.CredentialPassphraseEnvVar, services.CredentialKeyEnvVar, services.CredentialStoreEnvVar),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			info := services.DefaultCredentialStore()
+			fmt.Printf("Profile:          %s\n", ActiveProfile())
+			fmt.Printf("Server:           %s\n", ServerURL())
+			fmt.Printf("Credential store: %s\n", info.Store.Name())
+			if info.FallbackReason != "" {
+				fmt.Printf("                  (%s)\n", info.FallbackReason)
+			}
+			if file, ok := info.Store.(*services.EncryptedFileStore); ok {
+				fmt.Printf("Credential file:  %s\n", file.Path)
+			}
+
+			apiClient := NewAPIClient()
+			if _, err := apiClient.Keyring.GetToken(); err != nil {
+				if errors.Is(err, services.ErrNotLoggedIn) {
+					fmt.Println("Status:           logged out")
+				} else {
+					fmt.Printf("Status:           unknown (%v)\n", err)
+				}
+				return
+			}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, "/users/me", nil)
+			if err != nil {
+				fmt.Printf("Status:           token stored, server unreachable (%v)\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				fmt.Println("Status:           session expired, run 'osm auth login'")
+				return
+			}
+			var user models.User
+			if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+				fmt.Printf("Status:           logged in (could not read profile: %v)\n", err)
+				return
+			}
+			fmt.Printf("Status:           logged in as %s (ID %d)\n", user.Username, user.ID)
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"ossyne/internal/models"
+	"strconv"
+	"github.com/spf13/cobra"
+)
+
+func NewClaimCmd() *cobra.Command {
+	claimCmd := &cobra.Command{
+		Use:   "claim",
+		Short: "Review claims on your projects' tasks",
+		Long: `Claims start out pending. A task only locks for a contributor once a project
+maintainer accepts their claim; accepting one claim rejects the others on that task.`,
+	}
+
+	pendingCmd := &cobra.Command{
+		Use:   "pending",
+		Short: "List claims waiting for your approval",
+		Run: func(cmd *cobra.Command, args []string) {
+			taskIDStr, _ := cmd.Flags().GetString("task-id")
+
+			endpoint := "/claims/pending"
+			if taskIDStr != "" {
+				if _, err := strconv.ParseUint(taskIDStr, 10, 64); err != nil {
+					fmt.Printf("Error: Invalid task-id: %v\n", err)
+					return
+				}
+				endpoint += "?task_id=" + taskIDStr
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, endpoint, nil)
+			if err != nil {
+				fmt.Printf("Error listing pending claims: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error listing pending claims: %s\n", string(body))
+				return
+			}
+
+			var claims []models.Claim
+			if err := json.Unmarshal(body, &claims); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if len(claims) == 0 {
+				fmt.Println("No pending claims.")
+				return
+			}
+
+			fmt.Println("--- Pending Claims ---")
+			for _, cl := range claims {
+				mentor := ""
+				if cl.MentorID != nil {
+					mentor = ", Mentor: " + cl.Mentor.Username
+				}
+				fmt.Printf("Claim ID: %d, Task: %d (%s), User: %s, Claimed: %s%s\n",
+					cl.ID, cl.TaskID, cl.Task.Title, cl.User.Username, cl.ClaimDate.Format("2006-01-02 15:04"), mentor)
+			}
+		},
+	}
+	pendingCmd.Flags().StringP("task-id", "t", "", "Only show claims on this task (optional)")
+	claimCmd.AddCommand(pendingCmd)
+
+	claimCmd.AddCommand(newClaimReviewCmd("accept"))
+	claimCmd.AddCommand(newClaimReviewCmd("reject"))
+
+	mentorCmd := &cobra.Command{
+		Use:   "mentor [claim-id] [user-id|none]",
+		Short: "Assign or change the mentor of a claim",
+		Long: `Assign a mentor to a pending or accepted claim, replacing any previous mentor.
+Pass 'none' to remove the mentor. The mentor must be one of the users who
+volunteered for the task (see 'osm task mentors') and may not maintain the project.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			claimID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid claim ID: %v\n", err)
+				return
+			}
+			payload := map[string]interface{}{"mentor_id": nil}
+			if args[1] != "none" {
+				mentorID, err := strconv.ParseUint(args[1], 10, 64)
+				if err != nil {
+					fmt.Printf("Error: Invalid user ID: %v\n", err)
+					return
+				}
+				payload["mentor_id"] = uint(mentorID)
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/claims/%d/mentor", claimID), payload)
+			if err != nil {
+				fmt.Printf("Error assigning mentor: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error assigning mentor: %s\n", string(body))
+				return
+			}
+
+			var claim models.Claim
+			if err := json.Unmarshal(body, &claim); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if claim.MentorID == nil {
+				fmt.Printf("Claim %d no longer has a mentor.\n", claim.ID)
+				return
+			}
+			fmt.Printf("%s now mentors claim %d.\n", claim.Mentor.Username, claim.ID)
+		},
+	}
+	claimCmd.AddCommand(mentorCmd)
+
+	return claimCmd
+}
+
+// newClaimReviewCmd builds 'osm claim accept' and 'osm claim reject'.
+func newClaimReviewCmd(action string) *cobra.Command {
+	short := "Accept a pending claim and lock the task for its contributor"
+	done := "accepted"
+	if action == "reject" {
+		short = "Reject a pending claim"
+		done = "rejected"
+	}
+
+	reviewCmd := &cobra.Command{
+		Use:   action + " [claim-id]",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			claimID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid claim ID: %v\n", err)
+				return
+			}
+			note, _ := cmd.Flags().GetString("note")
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"note": note}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/claims/%d/%s", claimID, action), payload)
+			if err != nil {
+				fmt.Printf("Error updating claim: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error updating claim: %s\n", string(body))
+				return
+			}
+
+			var claim models.Claim
+			if err := json.Unmarshal(body, &claim); err != nil {
+				fmt.Printf("Claim %d %s.\n", claimID, done)
+				return
+			}
+			fmt.Printf("Claim %d by user %d on task %d %s.\n", claim.ID, claim.UserID, claim.TaskID, done)
+		},
+	}
+	reviewCmd.Flags().String("note", "", "Note for the contributor (optional)")
+	return reviewCmd
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"ossyne/internal/models"
+	"strconv"
+	"strings"
+	"github.com/spf13/cobra"
+)
+
+func NewCommentCmd() *cobra.Command {
+	commentCmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Manage comments on tasks and contributions",
+		Long: `Edit and delete your comments, and discuss contributions with their reviewers.
+Use 'osm task comment' and 'osm task comments' for task threads.`,
+	}
+
+	editCmd := &cobra.Command{
+		Use:   "edit [comment-id] [body]",
+		Short: "Edit one of your comments",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			commentID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid comment ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"body": args[1]}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPatch, fmt.Sprintf("/comments/%d", commentID), payload)
+			if err != nil {
+				fmt.Printf("Error editing comment: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error editing comment: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Comment %d updated\n", commentID)
+		},
+	}
+	commentCmd.AddCommand(editCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [comment-id]",
+		Short: "Delete a comment",
+		Long: `Delete a comment. You can delete your own comments; project maintainers can
+delete any comment in their project's threads.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			commentID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid comment ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/comments/%d", commentID), nil)
+			if err != nil {
+				fmt.Printf("Error deleting comment: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error deleting comment: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Comment %d deleted\n", commentID)
+		},
+	}
+	commentCmd.AddCommand(deleteCmd)
+
+	contributionCmd := &cobra.Command{
+		Use:   "contribution [contribution-id] [body]",
+		Short: "Comment on a contribution",
+		Long: `Post review feedback on a contribution, or reply to it. Only the contributor
+and the project's maintainers can read and post in a contribution's thread.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			contributionID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid contribution ID: %v\n", err)
+				return
+			}
+			replyTo, _ := cmd.Flags().GetUint("reply-to")
+			postComment(fmt.Sprintf("/contributions/%d/comments", contributionID), args[1], replyTo)
+		},
+	}
+	contributionCmd.Flags().Uint("reply-to", 0, "ID of the comment to reply to")
+	commentCmd.AddCommand(contributionCmd)
+
+	contributionListCmd := &cobra.Command{
+		Use:   "contribution-thread [contribution-id]",
+		Short: "Show the discussion on a contribution",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			contributionID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid contribution ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, fmt.Sprintf("/contributions/%d/comments", contributionID), nil)
+			if err != nil {
+				fmt.Printf("Error fetching comments: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error fetching comments: %s\n", string(body))
+				return
+			}
+			var comments []models.Comment
+			if err := json.Unmarshal(body, &comments); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if len(comments) == 0 {
+				fmt.Printf("No comments on contribution %d yet.\n", contributionID)
+				return
+			}
+			printCommentThread(comments)
+		},
+	}
+	commentCmd.AddCommand(contributionListCmd)
+
+	return commentCmd
+}
+
+// postComment posts body to a thread's comments endpoint. A replyTo of 0
+// starts a new top-level comment.
+func postComment(path, text string, replyTo uint) {
+	payload := map[string]interface{}{"body": text}
+	if replyTo != 0 {
+		payload["parent_id"] = replyTo
+	}
+
+	apiClient := NewAPIClient()
+	resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, path, payload)
+	if err != nil {
+		fmt.Printf("Error posting comment: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading response: %v\n", err)
+		return
+	}
+	if resp.StatusCode != http.StatusCreated {
+		fmt.Printf("Error posting comment: %s\n", string(body))
+		return
+	}
+	var comment models.Comment
+	if err := json.Unmarshal(body, &comment); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return
+	}
+	fmt.Printf("Comment %d posted\n", comment.ID)
+	if len(comment.Mentions) > 0 {
+		names := make([]string, len(comment.Mentions))
+		for i, user := range comment.Mentions {
+			names[i] = "@" + user.Username
+		}
+		fmt.Printf("Mentioned: %s\n", strings.Join(names, ", "))
+	}
+}
+
+// printCommentThread prints comments with replies indented under the comment
+// they answer. Replies whose parent was deleted are shown at the top level.
+func printCommentThread(comments []models.Comment) {
+	present := map[uint]bool{}
+	for _, comment := range comments {
+		present[comment.ID] = true
+	}
+	replies := map[uint][]models.Comment{}
+	var roots []models.Comment
+	for _, comment := range comments {
+		if comment.ParentID != nil && present[*comment.ParentID] {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var printComment func(comment models.Comment, indent string)
+	printComment = func(comment models.Comment, indent string) {
+		edited := ""
+		if comment.EditedAt != nil {
+			edited = " (edited)"
+		}
+		fmt.Printf("%s#%d @%s, %s%s\n", indent, comment.ID, comment.Author.Username, comment.CreatedAt.Format("2006-01-02 15:04"), edited)
+		for _, line := range strings.Split(comment.Body, "\n") {
+			fmt.Printf("%s  %s\n", indent, line)
+		}
+		for _, reply := range replies[comment.ID] {
+			printComment(reply, indent+"    ")
+		}
+	}
+	for i, comment := range roots {
+		if i > 0 {
+			fmt.Println()
+		}
+		printComment(comment, "")
+	}
+}
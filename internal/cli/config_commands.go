@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"ossyne/internal/config"
+	"ossyne/internal/services"
+	"sync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serverFlag  string
+	profileFlag string
+
+	// The profile and server are resolved once per invocation.
+	resolvedProfile string
+	resolvedServer  string
+	resolveErr      error
+	resolveOnce     sync.Once
+)
+
+// AddGlobalFlags registers the flags every osm command understands and checks
+// the profile and server they select before any command runs.
+func AddGlobalFlags(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().StringVar(&serverFlag, "server", "", fmt.Sprintf("OSSYNE server URL (overrides $%s and the profile's server)", config.ServerEnvVar))
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", fmt.Sprintf("Profile to use (overrides $%s and the current profile)", config.ProfileEnvVar))
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := resolveTarget(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	}
+}
+
+func resolveTarget() error {
+	resolveOnce.Do(func() {
+		resolvedProfile, resolveErr = config.ResolveProfile(profileFlag)
+		if resolveErr != nil {
+			return
+		}
+		resolvedServer, resolveErr = config.ResolveServerURL(serverFlag, resolvedProfile)
+	})
+	return resolveErr
+}
+
+// ActiveProfile is the name of the profile this invocation runs as. It is
+// empty if the profile could not be resolved; commands other than 'osm
+// profile' never run in that case.
+func ActiveProfile() string {
+	resolveTarget()
+	return resolvedProfile
+}
+
+// ServerURL is the root URL of the server osm talks to, e.g.
+// http://localhost:8080.
+func ServerURL() string {
+	resolveTarget()
+	return resolvedServer
+}
+
+func newKeyring() services.KeyringService {
+	return newKeyringFor(ActiveProfile())
+}
+
+func newKeyringFor(profile string) services.KeyringService {
+	return services.KeyringService{Profile: profile}
+}
+
+func NewConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage osm client configuration",
+		Long: fmt.Sprintf(`Read and change the osm client configuration file.
+
+The server is chosen in this order: the --server flag, the active profile's
+server, and finally %s. The %s environment variable replaces the
+default profile's server; with any other profile it must match that profile's
+server.`, config.DefaultServerURL, config.ServerEnvVar),
+	}
+
+	setServerCmd := &cobra.Command{
+		Use:   "set-server <url>",
+		Short: "Set the server of the active profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.LoadClientConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				return
+			}
+			profile := ActiveProfile()
+			if _, err := cfg.Profile(profile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v. Create it with 'osm profile add'.\n", err)
+				return
+			}
+			server := config.NormalizeServerURL(args[0])
+			cfg.SetProfile(profile, config.Profile{Server: server})
+			if err := config.SaveClientConfig(cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+				return
+			}
+			fmt.Printf("Server of profile '%s' set to %s\n", profile, server)
+		},
+	}
+	configCmd.AddCommand(setServerCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the active configuration",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := config.ClientConfigPath()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			fmt.Printf("Config file: %s\n", path)
+			fmt.Printf("Profile:     %s\n", ActiveProfile())
+			fmt.Printf("Server:      %s\n", ServerURL())
+		},
+	}
+	configCmd.AddCommand(showCmd)
+
+	return configCmd
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"ossyne/internal/models"
+	"path/filepath"
+	"strconv"
+	"github.com/spf13/cobra"
+)
+
+func newProjectExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export [project-id]",
+		Short: "Export a project's data as JSON or CSV",
+		Long: `Export the project's tasks, claims, contributions, payouts and reputation events.
+
+The JSON form holds everything in one document and can be restored with
+'osm project import'. The CSV form writes one file per table into the --output
+directory, or just the table named by --table.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+			format, _ := cmd.Flags().GetString("format")
+			table, _ := cmd.Flags().GetString("table")
+			output, _ := cmd.Flags().GetString("output")
+
+			switch format {
+			case "json":
+				if output == "" {
+					output = fmt.Sprintf("project-%d-export.json", projectID)
+				}
+				if err := downloadExport(projectID, url.Values{"format": {"json"}}, output); err != nil {
+					fmt.Printf("Error exporting project: %v\n", err)
+					return
+				}
+				fmt.Printf("Project %d exported to %s\n", projectID, output)
+			case "csv":
+				if table != "" {
+					if output == "" {
+						output = fmt.Sprintf("project-%d-%s.csv", projectID, table)
+					}
+					if err := downloadExport(projectID, url.Values{"format": {"csv"}, "table": {table}}, output); err != nil {
+						fmt.Printf("Error exporting project: %v\n", err)
+						return
+					}
+					fmt.Printf("Project %d %s exported to %s\n", projectID, table, output)
+					return
+				}
+				if output == "" {
+					output = fmt.Sprintf("project-%d-export", projectID)
+				}
+				if err := os.MkdirAll(output, 0o755); err != nil {
+					fmt.Printf("Error creating %s: %v\n", output, err)
+					return
+				}
+				for _, table := range models.ExportTables {
+					path := filepath.Join(output, table+".csv")
+					if err := downloadExport(projectID, url.Values{"format": {"csv"}, "table": {table}}, path); err != nil {
+						fmt.Printf("Error exporting %s: %v\n", table, err)
+						return
+					}
+				}
+				fmt.Printf("Project %d exported to %s/\n", projectID, output)
+			default:
+				fmt.Println("Error: --format must be json or csv")
+			}
+		},
+	}
+	exportCmd.Flags().String("format", "json", "json or csv")
+	exportCmd.Flags().String("table", "", "With --format csv, export only this table: tasks, claims, contributions, payouts or reputation_events")
+	exportCmd.Flags().StringP("output", "o", "", "File to write, or directory for all CSV tables (default project-<id>-export)")
+	return exportCmd
+}
+
+// downloadExport streams an export straight to path, so large projects are
+// never held in memory.
+func downloadExport(projectID uint64, params url.Values, path string) error {
+	apiClient := NewAPIClient()
+	resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, fmt.Sprintf("/projects/%d/export?%s", projectID, params.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s", string(body))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("download of %s interrupted: %w", path, err)
+	}
+	return file.Close()
+}
+
+func newProjectImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [project-id] [export-file]",
+		Short: "Restore a JSON export into a project",
+		Long: `Restore a file written by 'osm project export' into a project that has no tasks yet,
+such as one just made with 'osm project create'. Tasks, claims and contributions are
+restored. Bounties are imported as zero since nothing is escrowed for them here; fund
+them again with 'osm wallet fund'. Payouts and reputation events stay as they were.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				fmt.Printf("Error reading export: %v\n", err)
+				return
+			}
+			if !json.Valid(data) {
+				fmt.Printf("Error: %s is not a JSON export\n", args[1])
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/projects/%d/import", projectID), json.RawMessage(data))
+			if err != nil {
+				fmt.Printf("Error importing project: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error importing project: %s\n", string(body))
+				return
+			}
+
+			var result models.ProjectImportResult
+			if err := json.Unmarshal(body, &result); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			fmt.Printf("Imported into project %d: %d tasks, %d claims, %d contributions\n",
+				projectID, result.Tasks, result.Claims, result.Contributions)
+			if result.SkippedClaims > 0 || result.SkippedContributions > 0 {
+				fmt.Printf("Skipped %d claims and %d contributions of users unknown to this server\n",
+					result.SkippedClaims, result.SkippedContributions)
+			}
+			if result.Payouts > 0 || result.ReputationEvents > 0 {
+				fmt.Printf("Not replayed: %d payouts and %d reputation events\n", result.Payouts, result.ReputationEvents)
+			}
+			if result.BountiesNotFunded > 0 {
+				fmt.Printf("%d tasks had bounties, imported without one; fund them again with 'osm wallet fund'\n", result.BountiesNotFunded)
+			}
+		},
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"ossyne/internal/models"
+	"strings"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const manifestLong = `Tasks are matched to the file by their key. Tasks the file no longer declares are
+archived; tasks created without a key are left alone. Add 'id' to a task in the file to
+adopt a task created before the project used a manifest.
+
+Example ` + models.ManifestFileName + `:
+
+  project:
+    id: 3
+    title: Ossyne
+    tags: [go, cli]
+  tasks:
+    - key: parser-refactor
+      title: Refactor the config parser
+      difficulty: medium
+      estimated_hours: 6
+      skills: [go]
+      bounty: 50
+    - key: docs-install
+      title: Document the install steps
+      tags: [docs]`
+
+func newManifestPlanCmd() *cobra.Command {
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show how the server differs from " + models.ManifestFileName,
+		Long:  "Compare the project's tasks on the server with " + models.ManifestFileName + " and list the changes 'osm project apply' would make.\n\n" + manifestLong,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runManifest(cmd, "plan")
+		},
+	}
+	addManifestFlags(planCmd)
+	return planCmd
+}
+
+func newManifestApplyCmd() *cobra.Command {
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create, update and archive tasks to match " + models.ManifestFileName,
+		Long:  "Make the project's tasks on the server match " + models.ManifestFileName + ". Nothing is changed if any task is in conflict; run 'osm project plan' first to review the changes.\n\n" + manifestLong,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runManifest(cmd, "apply")
+		},
+	}
+	addManifestFlags(applyCmd)
+	return applyCmd
+}
+
+func addManifestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", models.ManifestFileName, "Manifest file to read")
+	cmd.Flags().Uint("project-id", 0, "Project to compare against (default: project.id in the file)")
+}
+
+// runManifest sends the manifest to the plan or apply endpoint and prints
+// the resulting plan.
+func runManifest(cmd *cobra.Command, action string) {
+	path, _ := cmd.Flags().GetString("file")
+	manifest, err := readManifest(path)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	projectID := manifest.Project.ID
+	if cmd.Flags().Changed("project-id") {
+		projectID, _ = cmd.Flags().GetUint("project-id")
+	}
+	if projectID == 0 {
+		fmt.Printf("Error: set project.id in %s or pass --project-id\n", path)
+		return
+	}
+
+	apiClient := NewAPIClient()
+	resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/projects/%d/manifest/%s", projectID, action), manifest)
+	if err != nil {
+		fmt.Printf("Error: could not %s manifest: %v\n", action, err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading response: %v\n", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error: could not %s manifest: %s\n", action, string(body))
+		return
+	}
+
+	var plan models.ManifestPlan
+	if err := json.Unmarshal(body, &plan); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return
+	}
+	printManifestPlan(&plan, action == "apply")
+}
+
+// readManifest loads a manifest file. Unknown fields are rejected so typos do
+// not silently reset a task to its defaults.
+func readManifest(path string) (*models.ProjectManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+	manifest := new(models.ProjectManifest)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+func printManifestPlan(plan *models.ManifestPlan, applied bool) {
+	if plan.Empty() {
+		fmt.Printf("Project %d matches the manifest, nothing to do.\n", plan.ProjectID)
+		return
+	}
+	if len(plan.Project) > 0 {
+		fmt.Printf("~ project %d\n", plan.ProjectID)
+		printFieldChanges(plan.Project)
+	}
+	counts := map[string]int{}
+	for _, change := range plan.Tasks {
+		counts[change.Action]++
+		symbol := map[string]string{
+			models.ManifestActionCreate:  "+",
+			models.ManifestActionUpdate:  "~",
+			models.ManifestActionReopen:  "^",
+			models.ManifestActionArchive: "-",
+		}[change.Action]
+		id := ""
+		if change.TaskID != 0 {
+			id = fmt.Sprintf(" (task %d)", change.TaskID)
+		}
+		fmt.Printf("%s %s %s%s: %s\n", symbol, change.Action, change.Key, id, change.Title)
+		printFieldChanges(change.Fields)
+		if change.Conflict != "" {
+			fmt.Printf("    ! conflict: %s\n", change.Conflict)
+		}
+	}
+
+	summary := "\nPlan: %d to create, %d to update, %d to reopen, %d to archive."
+	if applied {
+		summary = "\nApplied: %d created, %d updated, %d reopened, %d archived."
+	}
+	fmt.Printf(summary, counts[models.ManifestActionCreate], counts[models.ManifestActionUpdate],
+		counts[models.ManifestActionReopen], counts[models.ManifestActionArchive])
+	if plan.Unmanaged > 0 {
+		fmt.Printf(" %d task(s) without a key are not managed.", plan.Unmanaged)
+	}
+	fmt.Println()
+	if conflicts := plan.Conflicts(); len(conflicts) > 0 && !applied {
+		fmt.Printf("%d task(s) are in conflict; 'osm project apply' will refuse to run until they are resolved.\n", len(conflicts))
+	}
+}
+
+func printFieldChanges(changes []models.FieldChange) {
+	for _, change := range changes {
+		fmt.Printf("    %s: %q -> %q\n", change.Field, planValue(change.From), planValue(change.To))
+	}
+}
+
+// planValue shortens long or multi-line values, such as descriptions, to one
+// line.
+func planValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > 60 {
+		return string(runes[:57]) + "..."
+	}
+	return value
+}
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"ossyne/internal/models"
+	"strconv"
+	"strings"
+	"time"
+	"github.com/spf13/cobra"
+)
+
+func NewMilestoneCmd() *cobra.Command {
+	milestoneCmd := &cobra.Command{
+		Use:   "milestone",
+		Short: "Group a project's tasks into milestones and epics",
+		Long: `Milestones group tasks of one project, optionally with a due date. Progress is
+worked out from the status of each task: claimed counts a quarter done, in progress
+half, submitted three quarters and completed fully. Archived tasks are left out.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List milestones and their progress",
+		Run: func(cmd *cobra.Command, args []string) {
+			projectIDStr, _ := cmd.Flags().GetString("project-id")
+			url := ServerURL() + "/milestones"
+			if projectIDStr != "" {
+				if _, err := strconv.ParseUint(projectIDStr, 10, 64); err != nil {
+					fmt.Printf("Error: Invalid project-id: %v\n", err)
+					return
+				}
+				url += "?project_id=" + projectIDStr
+			}
+
+			resp, err := http.Get(url)
+			if err != nil {
+				fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading server response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error listing milestones: %s\n", string(body))
+				return
+			}
+
+			var milestones []models.Milestone
+			if err := json.Unmarshal(body, &milestones); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if len(milestones) == 0 {
+				fmt.Println("No milestones found.")
+				return
+			}
+
+			fmt.Println("--- Milestones ---")
+			for _, m := range milestones {
+				printMilestoneSummary(m)
+			}
+		},
+	}
+	listCmd.Flags().StringP("project-id", "p", "", "Only show milestones of this project (optional)")
+	milestoneCmd.AddCommand(listCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show [milestone-id]",
+		Short: "Show a milestone and its tasks",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			milestoneID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid milestone ID: %v\n", err)
+				return
+			}
+			resp, err := http.Get(fmt.Sprintf("%s/milestones/%d", ServerURL(), milestoneID))
+			if err != nil {
+				fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading server response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error fetching milestone: %s\n", string(body))
+				return
+			}
+
+			var result struct {
+				Milestone models.Milestone `json:"milestone"`
+				Tasks     []models.Task    `json:"tasks"`
+			}
+			if err := json.Unmarshal(body, &result); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			printMilestoneSummary(result.Milestone)
+			if result.Milestone.Description != "" {
+				fmt.Printf("  %s\n", result.Milestone.Description)
+			}
+			if len(result.Tasks) == 0 {
+				fmt.Println("  No tasks assigned yet.")
+				return
+			}
+			for _, t := range result.Tasks {
+				fmt.Printf("  - Task %d: %s (%s), Bounty: %.2f\n", t.ID, t.Title, t.Status, t.BountyAmount)
+			}
+		},
+	}
+	milestoneCmd.AddCommand(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a milestone or epic in a project",
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, _ := cmd.Flags().GetUint("project-id")
+			title, _ := cmd.Flags().GetString("title")
+			description, _ := cmd.Flags().GetString("description")
+			kind, _ := cmd.Flags().GetString("kind")
+			due, _ := cmd.Flags().GetString("due")
+
+			payload := map[string]interface{}{
+				"project_id":  projectID,
+				"title":       title,
+				"description": description,
+				"kind":        kind,
+				"due_date":    due,
+			}
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/milestones", payload)
+			if err != nil {
+				fmt.Printf("Error creating milestone: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error creating milestone: %s\n", string(body))
+				return
+			}
+			var milestone models.Milestone
+			if err := json.Unmarshal(body, &milestone); err != nil {
+				fmt.Println("Milestone created successfully!")
+				return
+			}
+			fmt.Printf("Created %s %d: %s\n", milestone.Kind, milestone.ID, milestone.Title)
+		},
+	}
+	createCmd.Flags().Uint("project-id", 0, "ID of the project the milestone belongs to")
+	createCmd.Flags().StringP("title", "t", "", "Title of the milestone")
+	createCmd.Flags().StringP("description", "d", "", "Description (optional)")
+	createCmd.Flags().String("kind", models.MilestoneKindMilestone, "milestone or epic")
+	createCmd.Flags().String("due", "", "Due date, e.g. 2025-12-31 (optional)")
+	createCmd.MarkFlagRequired("project-id")
+	createCmd.MarkFlagRequired("title")
+	milestoneCmd.AddCommand(createCmd)
+
+	editCmd := &cobra.Command{
+		Use:   "edit [milestone-id]",
+		Short: "Edit a milestone",
+		Long:  `Change a milestone's details. Only the flags you pass are updated; --due "" clears the due date.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			milestoneID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid milestone ID: %v\n", err)
+				return
+			}
+			payload := map[string]interface{}{}
+			for flag, field := range map[string]string{"title": "title", "description": "description", "kind": "kind", "due": "due_date"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetString(flag)
+					payload[field] = value
+				}
+			}
+			if len(payload) == 0 {
+				fmt.Println("Nothing to change. Pass at least one flag, see 'osm milestone edit --help'.")
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPatch, fmt.Sprintf("/milestones/%d", milestoneID), payload)
+			if err != nil {
+				fmt.Printf("Error updating milestone: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error updating milestone: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Milestone %d updated\n", milestoneID)
+		},
+	}
+	editCmd.Flags().StringP("title", "t", "", "New title")
+	editCmd.Flags().StringP("description", "d", "", "New description")
+	editCmd.Flags().String("kind", "", "milestone or epic")
+	editCmd.Flags().String("due", "", "New due date, e.g. 2025-12-31")
+	milestoneCmd.AddCommand(editCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [milestone-id]",
+		Short: "Delete a milestone",
+		Long:  `Delete a milestone. Its tasks stay in the project without a milestone.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			milestoneID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid milestone ID: %v\n", err)
+				return
+			}
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/milestones/%d", milestoneID), nil)
+			if err != nil {
+				fmt.Printf("Error deleting milestone: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error deleting milestone: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Milestone %d deleted\n", milestoneID)
+		},
+	}
+	milestoneCmd.AddCommand(deleteCmd)
+
+	assignCmd := &cobra.Command{
+		Use:   "assign [task-id] [milestone-id]",
+		Short: "Add a task to a milestone",
+		Long:  `Add a task to a milestone of the same project. Pass "none" as the milestone to take the task out of its milestone.`,
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			payload := map[string]interface{}{"milestone_id": nil}
+			if args[1] != "none" {
+				milestoneID, err := strconv.ParseUint(args[1], 10, 64)
+				if err != nil {
+					fmt.Printf("Error: Invalid milestone ID: %v\n", err)
+					return
+				}
+				payload["milestone_id"] = uint(milestoneID)
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/tasks/%d/milestone", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error assigning task: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error assigning task: %s\n", string(body))
+				return
+			}
+			if args[1] == "none" {
+				fmt.Printf("Task %d is no longer part of a milestone\n", taskID)
+				return
+			}
+			fmt.Printf("Task %d added to milestone %s\n", taskID, args[1])
+		},
+	}
+	milestoneCmd.AddCommand(assignCmd)
+
+	fundCmd := &cobra.Command{
+		Use:   "fund [milestone-id]",
+		Short: "Fund a bounty split across a milestone's tasks",
+		Long: `Split a bounty evenly across the milestone's unfinished tasks that have no bounty
+yet. Each share is escrowed as that task's own bounty.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			milestoneID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid milestone ID: %v\n", err)
+				return
+			}
+			amount, _ := cmd.Flags().GetFloat64("amount")
+			currency, _ := cmd.Flags().GetString("currency")
+
+			apiClient := NewAPIClient()
+			payload := map[string]interface{}{"amount": amount, "currency": currency}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/milestones/%d/fund", milestoneID), payload)
+			if err != nil {
+				fmt.Printf("Error funding milestone: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error funding milestone: %s\n", string(body))
+				return
+			}
+			var tasks []models.Task
+			if err := json.Unmarshal(body, &tasks); err != nil {
+				fmt.Printf("Funded milestone %d with %.2f %s\n", milestoneID, amount, currency)
+				return
+			}
+			fmt.Printf("Funded milestone %d with %.2f %s across %d tasks:\n", milestoneID, amount, currency, len(tasks))
+			for _, t := range tasks {
+				fmt.Printf("  - Task %d: %s, Bounty: %.2f\n", t.ID, t.Title, t.BountyAmount)
+			}
+		},
+	}
+	fundCmd.Flags().Float64("amount", 0, "Total bounty to split across the tasks")
+	fundCmd.Flags().String("currency", "USD", "Currency of the bounty")
+	fundCmd.MarkFlagRequired("amount")
+	milestoneCmd.AddCommand(fundCmd)
+
+	return milestoneCmd
+}
+
+func printMilestoneSummary(m models.Milestone) {
+	due := "no due date"
+	if m.DueDate != nil {
+		due = "due " + m.DueDate.Format("2006-01-02")
+		if m.IsOverdue(time.Now()) {
+			due += " (OVERDUE)"
+		}
+	}
+	fmt.Printf("[%s] %d: %s (Project %d, %s)\n", m.Kind, m.ID, m.Title, m.ProjectID, due)
+	fmt.Printf("  %s %.0f%% (%d/%d tasks completed), Bounty: %.2f\n",
+		progressBar(m.Progress.Percent, 20), m.Progress.Percent, m.Progress.CompletedTasks, m.Progress.TotalTasks, m.Progress.BountyTotal)
+}
+
+func progressBar(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
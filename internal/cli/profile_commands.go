@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"ossyne/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func NewProfileCmd() *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage server and account profiles",
+		Long: `Profiles let you keep logins for several OSSYNE servers or accounts side by side.
+Each profile has its own server URL and its own session in the OS keyring.
+
+Pick a profile for a single command with --profile, or switch the current one with 'osm profile use'.`,
+		// Profile commands must work while the selected profile is missing or
+		// misconfigured, since they are how it gets fixed.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			server, _ := cmd.Flags().GetString("server-url")
+			use, _ := cmd.Flags().GetBool("use")
+			if err := config.ValidateProfileName(name); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+
+			cfg, err := config.LoadClientConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				return
+			}
+			if _, err := cfg.Profile(name); err == nil && name != config.DefaultProfile {
+				fmt.Fprintf(os.Stderr, "Error: profile '%s' already exists. Change its server with 'osm --profile %s config set-server'.\n", name, name)
+				return
+			}
+			cfg.SetProfile(name, config.Profile{Server: config.NormalizeServerURL(server)})
+			if use {
+				cfg.CurrentProfile = name
+			}
+			if err := config.SaveClientConfig(cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Profile '%s' added for %s.\n", name, config.NormalizeServerURL(server))
+			if use {
+				fmt.Printf("Now using profile '%s'. Run 'osm auth login' to sign in.\n", name)
+			} else {
+				fmt.Printf("Run 'osm --profile %s auth login' to sign in.\n", name)
+			}
+		},
+	}
+	addCmd.Flags().String("server-url", "", "Server URL for this profile, e.g. https://ossyne.example.com")
+	addCmd.Flags().Bool("use", false, "Switch to the new profile")
+	addCmd.MarkFlagRequired("server-url")
+	profileCmd.AddCommand(addCmd)
+
+	useCmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the current profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			cfg, err := config.LoadClientConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				return
+			}
+			if _, err := cfg.Profile(name); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			cfg.CurrentProfile = name
+			if err := config.SaveClientConfig(cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+				return
+			}
+			fmt.Printf("Now using profile '%s'.\n", name)
+		},
+	}
+	profileCmd.AddCommand(useCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.LoadClientConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				return
+			}
+			active := ActiveProfile()
+			fmt.Println("--- Profiles ---")
+			for _, name := range cfg.ProfileNames() {
+				p, _ := cfg.Profile(name)
+				server := config.DefaultServerURL
+				if p.Server != "" {
+					server = config.NormalizeServerURL(p.Server)
+				}
+				marker := "  "
+				if name == active {
+					marker = "* "
+				}
+				keyring := newKeyringFor(name)
+				status := "logged out"
+				if _, err := keyring.GetToken(); err == nil {
+					status = "logged in"
+				}
+				fmt.Printf("%s%s  %s  (%s)\n", marker, name, server, status)
+			}
+		},
+	}
+	profileCmd.AddCommand(listCmd)
+
+	return profileCmd
+}
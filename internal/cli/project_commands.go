@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"ossyne/internal/models"
+	"strconv"
 	"github.com/spf13/cobra"
 )
 
@@ -88,11 +89,253 @@ func NewProjectCmd() *cobra.Command {
 	}
 	projectCmd.AddCommand(listCmd)
 
+	addMaintainerCmd := &cobra.Command{
+		Use:   "add-maintainer [project-id] [user-id]",
+		Short: "Add a co-maintainer to a project",
+		Long:  `Co-maintainers can create tasks, review contributions and refund bounties on the project, just like its owner.`,
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+			userID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid user ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			payloadMap := map[string]interface{}{
+				"user_id": uint(userID),
+			}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/projects/%d/maintainers", projectID), payloadMap)
+			if err != nil {
+				fmt.Printf("Error adding maintainer: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error adding maintainer: %s\n", string(body))
+				return
+			}
+
+			fmt.Printf("User %d is now a co-maintainer of project %d\n", userID, projectID)
+		},
+	}
+	projectCmd.AddCommand(addMaintainerCmd)
+
+	removeMaintainerCmd := &cobra.Command{
+		Use:   "remove-maintainer [project-id] [user-id]",
+		Short: "Remove a co-maintainer from a project",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+			userID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid user ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/projects/%d/maintainers/%d", projectID, userID), nil)
+			if err != nil {
+				fmt.Printf("Error removing maintainer: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error removing maintainer: %s\n", string(body))
+				return
+			}
+
+			fmt.Printf("User %d is no longer a co-maintainer of project %d\n", userID, projectID)
+		},
+	}
+	projectCmd.AddCommand(removeMaintainerCmd)
+
+	editCmd := &cobra.Command{
+		Use:   "edit [project-id]",
+		Short: "Edit a project",
+		Long:  `Change a project's details. Only the flags you pass are updated.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+
+			payloadMap := map[string]interface{}{}
+			for flag, field := range map[string]string{"title": "title", "short-desc": "short_desc", "repo-url": "repo_url", "visibility": "visibility"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetString(flag)
+					payloadMap[field] = value
+				}
+			}
+			if cmd.Flags().Changed("tags") {
+				tagsStr, _ := cmd.Flags().GetString("tags")
+				var tags []string
+				if err := json.Unmarshal([]byte(tagsStr), &tags); err != nil {
+					fmt.Printf("Error: Invalid tags JSON: %v. Please use format `[\"tag1\",\"tag2\"]`\n", err)
+					return
+				}
+				payloadMap["tags"] = tags
+			}
+			if len(payloadMap) == 0 {
+				fmt.Println("Nothing to change. Pass at least one flag, see 'osm project edit --help'.")
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPatch, fmt.Sprintf("/projects/%d", projectID), payloadMap)
+			if err != nil {
+				fmt.Printf("Error updating project: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error updating project: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Project %d updated\n", projectID)
+		},
+	}
+	editCmd.Flags().StringP("title", "t", "", "New title")
+	editCmd.Flags().StringP("repo-url", "r", "", "New repository URL")
+	editCmd.Flags().StringP("short-desc", "d", "", "New short description")
+	editCmd.Flags().String("tags", "", "JSON array replacing the tags, e.g., '[\"go\",\"cli\"]'")
+	editCmd.Flags().String("visibility", "", "public or private")
+	projectCmd.AddCommand(editCmd)
+
+	importIssuesCmd := &cobra.Command{
+		Use:   "import-issues [project-id]",
+		Short: "Create tasks from the project's GitHub issues",
+		Long: `Create a task for each open issue of the project's GitHub repository, or update
+the task imported from it before. Issue labels become task tags, and labels such as
+"good first issue", "medium" or "difficulty: hard" set the difficulty.
+Repeat --label to import only issues carrying all the given labels.
+Only public repositories can be imported.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+			labels, _ := cmd.Flags().GetStringSlice("label")
+
+			apiClient := NewAPIClient()
+			payloadMap := map[string]interface{}{
+				"labels": labels,
+			}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/projects/%d/import-issues", projectID), payloadMap)
+			if err != nil {
+				fmt.Printf("Error importing issues: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error importing issues: %s\n", string(body))
+				return
+			}
+
+			var result models.IssueImportResult
+			if err := json.Unmarshal(body, &result); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			for _, task := range result.Created {
+				fmt.Printf("  + Task %d from issue #%d: %s\n", task.ID, *task.GitHubIssueNumber, task.Title)
+			}
+			for _, task := range result.Updated {
+				fmt.Printf("  ~ Task %d from issue #%d: %s\n", task.ID, *task.GitHubIssueNumber, task.Title)
+			}
+			fmt.Printf("Imported issues into project %d: %d created, %d updated, %d unchanged, %d skipped\n",
+				projectID, len(result.Created), len(result.Updated), result.Unchanged, result.Skipped)
+		},
+	}
+	importIssuesCmd.Flags().StringSlice("label", nil, "Only import issues with this label (repeatable)")
+	projectCmd.AddCommand(importIssuesCmd)
+	projectCmd.AddCommand(newManifestPlanCmd())
+	projectCmd.AddCommand(newManifestApplyCmd())
+	projectCmd.AddCommand(newProjectExportCmd())
+	projectCmd.AddCommand(newProjectImportCmd())
+	projectCmd.AddCommand(newProjectTimeReportCmd())
+
+	projectCmd.AddCommand(newProjectLifecycleCmd("delete", http.MethodDelete, "", "Delete a project",
+		`Soft-delete a project. Its tasks are hidden until it is restored. Tasks with a bounty in escrow must be archived first.`))
+	projectCmd.AddCommand(newProjectLifecycleCmd("restore", http.MethodPost, "/restore", "Restore a deleted project", ""))
+
 	return projectCmd
 }
 
+// newProjectLifecycleCmd builds 'osm project delete' and 'osm project restore'.
+func newProjectLifecycleCmd(action, method, suffix, short, long string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " [project-id]",
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(method, fmt.Sprintf("/projects/%d%s", projectID, suffix), nil)
+			if err != nil {
+				fmt.Printf("Error: could not %s project: %v\n", action, err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error: could not %s project: %s\n", action, string(body))
+				return
+			}
+			fmt.Printf("Project %d %sd\n", projectID, action)
+		},
+	}
+}
+
 func listProjects() {
-	const serverURL = "http://localhost:8080/projects"
+	serverURL := ServerURL() + "/projects"
 
 	resp, err := http.Get(serverURL)
 	if err != nil {
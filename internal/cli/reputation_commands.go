@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"ossyne/internal/models"
 	"strconv"
 	"github.com/spf13/cobra"
 )
@@ -16,35 +18,23 @@ func NewRatingsCmd() *cobra.Command {
 	}
 
 	endorseCmd := &cobra.Command{
-		Use:   "endorse",
-		Short: "Endorse a user as a mentor",
-		Long:  `A mentor can endorse a user for their work on a specific task/claim, boosting their ratings.`,
+		Use:   "endorse [claim-id]",
+		Short: "Endorse the contributor you mentor on a claim",
+		Long: `The mentor assigned to an accepted claim can endorse its contributor once,
+boosting their ratings. Maintainers assign mentors with 'osm claim mentor'.`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			userIDStr, _ := cmd.Flags().GetString("user-id")
-			relatedIDStr, _ := cmd.Flags().GetString("related-id")
-			notes, _ := cmd.Flags().GetString("notes")
-
-			if userIDStr == "" || relatedIDStr == "" {
-				fmt.Println("Error: --user-id and --related-id are required.")
-				return
-			}
-
-			userID, err := strconv.ParseUint(userIDStr, 10, 64)
+			claimID, err := strconv.ParseUint(args[0], 10, 64)
 			if err != nil {
-				fmt.Printf("Error: Invalid user-id: %v\n", err)
-				return
-			}
-			relatedID, err := strconv.ParseUint(relatedIDStr, 10, 64)
-			if err != nil {
-				fmt.Printf("Error: Invalid related-id: %v\n", err)
+				fmt.Printf("Error: Invalid claim ID: %v\n", err)
 				return
 			}
+			notes, _ := cmd.Flags().GetString("notes")
 
 			apiClient := NewAPIClient()
 			payloadMap := map[string]interface{}{
-				"user_id":    uint(userID),
-				"related_id": uint(relatedID),
-				"notes":      notes,
+				"claim_id": uint(claimID),
+				"notes":    notes,
 			}
 
 			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/mentor/endorse", payloadMap)
@@ -60,19 +50,20 @@ func NewRatingsCmd() *cobra.Command {
 				return
 			}
 
-			if resp.StatusCode != http.StatusCreated {
+			if resp.StatusCode != http.StatusOK {
 				fmt.Printf("Error endorsing user: %s\n", string(body))
 				return
 			}
 
-			fmt.Printf("Successfully endorsed user %d\n", userID)
+			var claim models.Claim
+			if err := json.Unmarshal(body, &claim); err != nil {
+				fmt.Printf("Successfully endorsed the contributor on claim %d\n", claimID)
+				return
+			}
+			fmt.Printf("Successfully endorsed user %d for claim %d on task %d\n", claim.UserID, claim.ID, claim.TaskID)
 		},
 	}
-	endorseCmd.Flags().StringP("user-id", "u", "", "ID of the user being endorsed")
-	endorseCmd.Flags().StringP("related-id", "r", "", "ID of the related task or claim (e.g., Task ID)")
 	endorseCmd.Flags().StringP("notes", "n", "", "Optional notes for the endorsement")
-	endorseCmd.MarkFlagRequired("user-id")
-	endorseCmd.MarkFlagRequired("related-id")
 	ratingsCmd.AddCommand(endorseCmd)
 
 	return ratingsCmd
@@ -175,7 +166,7 @@ func NewAdminCmd() *cobra.Command {
 	createSkillCmd := &cobra.Command{
 		Use:   "create-skill",
 		Short: "Add a new skill to the marketplace",
-		Long:  `Allows platform admins to add new skills that can be associated with users and tasks.`,
+		Long:  `Allows platform admins and users with the maintainer role to add new skills that can be associated with users and tasks.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			name, _ := cmd.Flags().GetString("name")
 			description, _ := cmd.Flags().GetString("description")
@@ -305,5 +296,119 @@ func NewAdminCmd() *cobra.Command {
 	}
 	adminCmd.AddCommand(listSkillsCmd)
 
+	adminCmd.AddCommand(newRoleChangeCmd("grant"))
+	adminCmd.AddCommand(newRoleChangeCmd("revoke"))
+
+	roleLogCmd := &cobra.Command{
+		Use:   "role-log",
+		Short: "Show the audit log of role changes",
+		Long:  `Lists every role grant and revocation, newest first. Use --user-id to only show changes for one user.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			userID, _ := cmd.Flags().GetUint("user-id")
+			endpoint := "/admin/role-changes"
+			if userID != 0 {
+				endpoint = fmt.Sprintf("%s?user_id=%d", endpoint, userID)
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, endpoint, nil)
+			if err != nil {
+				fmt.Printf("Error fetching role changes: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error fetching role changes: %s\n", string(body))
+				return
+			}
+
+			var entries []models.RoleChangeLog
+			if err := json.Unmarshal(body, &entries); err != nil {
+				fmt.Printf("Error parsing role changes: %v\n", err)
+				return
+			}
+			if len(entries) == 0 {
+				fmt.Println("No role changes recorded.")
+				return
+			}
+			for _, entry := range entries {
+				actor := "system"
+				if entry.Actor != nil {
+					actor = entry.Actor.Username
+				}
+				target := fmt.Sprintf("user %d", entry.TargetUserID)
+				if entry.TargetUser != nil {
+					target = entry.TargetUser.Username
+				}
+				fmt.Printf("%s  %-6s %-11s %-20s by %s", entry.CreatedAt.Format("2006-01-02 15:04"), entry.Action, entry.Role, target, actor)
+				if entry.Reason != "" {
+					fmt.Printf(" (%s)", entry.Reason)
+				}
+				fmt.Println()
+			}
+		},
+	}
+	roleLogCmd.Flags().Uint("user-id", 0, "Only show role changes for this user")
+	adminCmd.AddCommand(roleLogCmd)
+
 	return adminCmd
-}
\ No newline at end of file
+}
+
+// newRoleChangeCmd builds the grant-role and revoke-role commands, which only
+// differ in the action they send to the server.
+func newRoleChangeCmd(action string) *cobra.Command {
+	verb, past, preposition := "Grant", "granted", "to"
+	if action == "revoke" {
+		verb, past, preposition = "Revoke", "revoked", "from"
+	}
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s-role [user-id] [role]", action),
+		Short: fmt.Sprintf("%s a platform role %s a user", verb, preposition),
+		Long: fmt.Sprintf(`%ss one of the platform roles (admin, maintainer, contributor) %s a user.
+Every change is recorded in the role audit log, see 'osm admin role-log'.`, verb, preposition),
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			userID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid user ID: %v\n", err)
+				return
+			}
+			reason, _ := cmd.Flags().GetString("reason")
+
+			apiClient := NewAPIClient()
+			payloadMap := map[string]interface{}{
+				"role":   args[1],
+				"reason": reason,
+			}
+
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/admin/users/%d/roles/%s", userID, action), payloadMap)
+			if err != nil {
+				fmt.Printf("Error changing role: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error changing role: %s\n", string(body))
+				return
+			}
+
+			fmt.Printf("Successfully %s role '%s' %s user %d\n", past, args[1], preposition, userID)
+		},
+	}
+	cmd.Flags().StringP("reason", "r", "", "Reason for the role change, recorded in the audit log")
+	return cmd
+}
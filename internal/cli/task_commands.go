@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"ossyne/internal/models"
 	"strconv"
 	"strings"
@@ -31,6 +32,8 @@ func NewTaskCmd() *cobra.Command {
 			tagsStr, _ := cmd.Flags().GetString("tags")
 			skillsStr, _ := cmd.Flags().GetString("skills-required")
 			bountyAmountStr, _ := cmd.Flags().GetString("bounty-amount")
+			claimPolicy, _ := cmd.Flags().GetString("claim-policy")
+			maxClaimants, _ := cmd.Flags().GetInt("max-claimants")
 
 			if projectIDStr == "" || title == "" {
 				fmt.Println("Error: --project-id and --title flags are required.")
@@ -80,7 +83,8 @@ func NewTaskCmd() *cobra.Command {
 				"description":      description,
 				"difficulty_level": difficulty,
 				"estimated_hours":  estimatedHours,
-				"bounty_amount":    bountyAmount,
+				"claim_policy":     claimPolicy,
+				"max_claimants":    maxClaimants,
 			}
 			if len(tags) > 0 {
 				payloadMap["tags"] = tags
@@ -88,6 +92,9 @@ func NewTaskCmd() *cobra.Command {
 			if len(skillsRequired) > 0 {
 				payloadMap["skills_required"] = skillsRequired
 			}
+			if milestoneID, _ := cmd.Flags().GetUint("milestone-id"); milestoneID != 0 {
+				payloadMap["milestone_id"] = milestoneID
+			}
 
 			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/tasks", payloadMap)
 			if err != nil {
@@ -107,7 +114,29 @@ func NewTaskCmd() *cobra.Command {
 				return
 			}
 
-			fmt.Println("Task created successfully!")
+			var task models.Task
+			if err := json.Unmarshal(body, &task); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			fmt.Printf("Task %d created successfully!\n", task.ID)
+			if bountyAmount <= 0 {
+				return
+			}
+			// Bounties only count once escrowed, so fund it like 'osm wallet fund'.
+			fundResp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, "/bounties/fund",
+				map[string]interface{}{"task_id": task.ID, "amount": bountyAmount})
+			if err != nil {
+				fmt.Printf("Error funding bounty: %v\n", err)
+				return
+			}
+			defer fundResp.Body.Close()
+			fundBody, _ := io.ReadAll(fundResp.Body)
+			if fundResp.StatusCode != http.StatusOK {
+				fmt.Printf("Error funding bounty: %s\nThe task has no bounty; fund it with 'osm wallet fund'.\n", string(fundBody))
+				return
+			}
+			fmt.Printf("Bounty of %.2f escrowed for task %d\n", bountyAmount, task.ID)
 		},
 	}
 	createCmd.Flags().StringP("project-id", "p", "", "ID of the project this task belongs to")
@@ -117,29 +146,121 @@ func NewTaskCmd() *cobra.Command {
 	createCmd.Flags().String("estimated-hours", "0", "Estimated hours to complete (optional)")
 	createCmd.Flags().String("tags", "", "JSON array of tags, e.g., '[\"bug\",\"feature\"]' (optional)")
 	createCmd.Flags().String("skills-required", "", "JSON array of required skills, e.g., '[\"go\",\"testing\"]' (optional)")
-	createCmd.Flags().String("bounty-amount", "0.00", "Monetary bounty for completing this task (optional)")
+	createCmd.Flags().String("bounty-amount", "0.00", "Monetary bounty for completing this task, escrowed right after creation (optional)")
+	createCmd.Flags().String("claim-policy", "exclusive", "Who may work on the task: exclusive (one claimant), competitive (first accepted contribution wins) or limited")
+	createCmd.Flags().Int("max-claimants", 0, "Number of claims to accept under the limited claim policy")
+	createCmd.Flags().Uint("milestone-id", 0, "Milestone of the same project to add the task to (optional)")
 	createCmd.MarkFlagRequired("project-id")
 	createCmd.MarkFlagRequired("title")
 	taskCmd.AddCommand(createCmd)
 
 	listCmd := &cobra.Command{
 		Use:   "list",
-		Short: "List tasks",
-		Long:  `List tasks, optionally filtered by project or status.`,
+		Short: "List and search tasks",
+		Long: `List tasks, filtered by text, project, status, tags, skills, difficulty, bounty
+and estimated hours. Results come in pages; pass the printed cursor with
+--cursor to see the next page, or use --all to fetch every page.
+
+Sort keys are created_at, bounty_amount, estimated_hours and title; prefix one
+with '-' to sort descending. The default is -created_at (newest first).`,
 		Run: func(cmd *cobra.Command, args []string) {
-			projectIDStr, _ := cmd.Flags().GetString("project-id")
-			status, _ := cmd.Flags().GetString("status")
-			listTasks(projectIDStr, status)
+			params := url.Values{}
+			for flag, param := range map[string]string{"project-id": "project_id", "milestone-id": "milestone_id", "status": "status", "query": "q", "sort": "sort", "cursor": "cursor"} {
+				if value, _ := cmd.Flags().GetString(flag); value != "" {
+					params.Set(param, value)
+				}
+			}
+			for flag, param := range map[string]string{"tag": "tags", "skill": "skills", "difficulty": "difficulty"} {
+				if values, _ := cmd.Flags().GetStringSlice(flag); len(values) > 0 {
+					params.Set(param, strings.Join(values, ","))
+				}
+			}
+			for flag, param := range map[string]string{"min-bounty": "min_bounty", "max-bounty": "max_bounty"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetFloat64(flag)
+					params.Set(param, strconv.FormatFloat(value, 'f', -1, 64))
+				}
+			}
+			for flag, param := range map[string]string{"min-hours": "min_hours", "max-hours": "max_hours", "limit": "limit"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetInt(flag)
+					params.Set(param, strconv.Itoa(value))
+				}
+			}
+			all, _ := cmd.Flags().GetBool("all")
+			listTasks(params, all)
 		},
 	}
 	listCmd.Flags().StringP("project-id", "p", "", "Filter tasks by Project ID (optional)")
+	listCmd.Flags().String("milestone-id", "", "Filter tasks by Milestone ID (optional)")
 	listCmd.Flags().StringP("status", "s", "", "Filter tasks by status (open, claimed, in_progress, submitted, completed, archived) (optional)")
+	listCmd.Flags().StringP("query", "q", "", "Search text in titles and descriptions")
+	listCmd.Flags().StringSlice("tag", nil, "Only tasks with this tag; repeat or comma-separate to require several")
+	listCmd.Flags().StringSlice("skill", nil, "Only tasks requiring this skill; repeat or comma-separate to require several")
+	listCmd.Flags().StringSlice("difficulty", nil, "Only tasks of these difficulties (easy, medium, hard)")
+	listCmd.Flags().Float64("min-bounty", 0, "Minimum bounty amount")
+	listCmd.Flags().Float64("max-bounty", 0, "Maximum bounty amount")
+	listCmd.Flags().Int("min-hours", 0, "Minimum estimated hours")
+	listCmd.Flags().Int("max-hours", 0, "Maximum estimated hours")
+	listCmd.Flags().String("sort", "", "Sort key, e.g. -bounty_amount or estimated_hours (default -created_at)")
+	listCmd.Flags().Int("limit", 0, "Tasks per page (default 50, max 200)")
+	listCmd.Flags().String("cursor", "", "Cursor of the page to fetch, as printed after the previous page")
+	listCmd.Flags().Bool("all", false, "Fetch every page")
 	taskCmd.AddCommand(listCmd)
 
+	suggestCmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest tasks that fit your skills",
+		Long: `List claimable tasks ranked by how well they fit you: your skills and skill
+levels against each task's required skills and difficulty, the projects and
+skills of your accepted contributions, and the bounty. Tasks of projects you
+own or maintain and tasks you claimed before are not suggested.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, fmt.Sprintf("/users/me/recommended-tasks?limit=%d", limit), nil)
+			if err != nil {
+				fmt.Printf("Error fetching suggestions: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error fetching suggestions: %s\n", string(body))
+				return
+			}
+
+			var recommendations []models.TaskRecommendation
+			if err := json.Unmarshal(body, &recommendations); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if len(recommendations) == 0 {
+				fmt.Println("No open tasks to suggest right now.")
+				return
+			}
+			fmt.Println("--- Suggested Tasks ---")
+			for _, rec := range recommendations {
+				fmt.Printf("[%3.0f] ID: %d, Title: %s, Project ID: %d, Difficulty: %s, Bounty: %.2f\n",
+					rec.Score, rec.Task.ID, rec.Task.Title, rec.Task.ProjectID, rec.Task.DifficultyLevel, rec.Task.BountyAmount)
+				for _, reason := range rec.Reasons {
+					fmt.Printf("      - %s\n", reason)
+				}
+			}
+		},
+	}
+	suggestCmd.Flags().Int("limit", 10, "Number of suggestions (max 50)")
+	taskCmd.AddCommand(suggestCmd)
+
 	claimCmd := &cobra.Command{
 		Use:   "claim [task-id]",
 		Short: "Claim an open task",
-		Long:  `Claim an open task to work on.`,
+		Long:  `Ask to work on an open task. The claim stays pending until a project maintainer accepts it.`,
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			taskIDStr := args[0]
@@ -164,7 +285,7 @@ func NewTaskCmd() *cobra.Command {
 
 				// Use direct HTTP request for development
 				jsonData, _ := json.Marshal(payload)
-				resp, err := http.Post("http://localhost:8080/dev/claims", "application/json", strings.NewReader(string(jsonData)))
+				resp, err := http.Post(ServerURL()+"/dev/claims", "application/json", strings.NewReader(string(jsonData)))
 				if err != nil {
 					fmt.Printf("Error claiming task: %v\n", err)
 					return
@@ -202,7 +323,7 @@ func NewTaskCmd() *cobra.Command {
 				fmt.Printf("Error claiming task: %s\n", string(body))
 				return
 			}
-			fmt.Printf("Successfully claimed task %d\n", taskID)
+			fmt.Printf("Claim on task %d submitted; it is pending until a project maintainer accepts it\n", taskID)
 		},
 	}
 	claimCmd.Flags().StringP("dev-user-id", "", "", "Development only: specify user ID to claim task as (bypasses auth)")
@@ -245,7 +366,7 @@ func NewTaskCmd() *cobra.Command {
 
 				// Use direct HTTP request for development
 				jsonData, _ := json.Marshal(payload)
-				resp, err := http.Post("http://localhost:8080/dev/contributions", "application/json", strings.NewReader(string(jsonData)))
+				resp, err := http.Post(ServerURL()+"/dev/contributions", "application/json", strings.NewReader(string(jsonData)))
 				if err != nil {
 					fmt.Printf("Error submitting contribution: %v\n", err)
 					return
@@ -290,47 +411,534 @@ func NewTaskCmd() *cobra.Command {
 	submitCmd.MarkFlagRequired("pr-url")
 	taskCmd.AddCommand(submitCmd)
 
-	return taskCmd
-}
+	unclaimCmd := &cobra.Command{
+		Use:   "unclaim [task-id]",
+		Short: "Give up your claim on a task",
+		Long:  `Withdraw your pending or accepted claim on a task. If the claim was accepted the task is reopened for others.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			reason, _ := cmd.Flags().GetString("reason")
 
-func listTasks(projectIDStr, status string) {
-	baseURL := "http://localhost:8080/tasks"
-	params := []string{}
-	if projectIDStr != "" {
-		params = append(params, "project_id="+projectIDStr)
+			apiClient := NewAPIClient()
+			payload := map[string]string{"reason": reason}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/unclaim", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error withdrawing claim: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error withdrawing claim: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Your claim on task %d has been withdrawn\n", taskID)
+		},
 	}
-	if status != "" {
-		params = append(params, "status="+status)
+	unclaimCmd.Flags().String("reason", "", "Why you are giving up the task (optional)")
+	taskCmd.AddCommand(unclaimCmd)
+
+	setStatusCmd := &cobra.Command{
+		Use:   "set-status [task-id] [status]",
+		Short: "Move a task to a new status",
+		Long: `Move a task along its lifecycle. The claimant may mark the task in_progress; project
+maintainers may also:
+
+  claimed    send a submitted task back for more work
+  open       release a claimed or in-progress task from its claimants, or
+             bring back an archived one
+  archived   archive the task, refunding any escrowed bounty
+
+Tasks become claimed, submitted and completed through claims and contributions.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			status := args[1]
+			reason, _ := cmd.Flags().GetString("reason")
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"status": status, "reason": reason}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/tasks/%d/status", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error updating task status: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error updating task status: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Task %d is now %s\n", taskID, status)
+		},
 	}
+	setStatusCmd.Flags().String("reason", "", "Why the status is changing (optional)")
+	taskCmd.AddCommand(setStatusCmd)
 
-	url := baseURL
-	if len(params) > 0 {
-		url += "?" + strings.Join(params, "&")
+	historyCmd := &cobra.Command{
+		Use:   "history [task-id]",
+		Short: "Show a task's status history",
+		Long:  `Show every status change of a task, oldest first.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			showTaskHistory(uint(taskID))
+		},
 	}
+	taskCmd.AddCommand(historyCmd)
 
+	editCmd := &cobra.Command{
+		Use:   "edit [task-id]",
+		Short: "Edit a task",
+		Long: `Change a task's details. Only the flags you pass are updated.
+The claim policy can only change while the task is open.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+
+			payloadMap := map[string]interface{}{}
+			for flag, field := range map[string]string{"title": "title", "description": "description", "difficulty": "difficulty_level", "claim-policy": "claim_policy"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetString(flag)
+					payloadMap[field] = value
+				}
+			}
+			for flag, field := range map[string]string{"estimated-hours": "estimated_hours", "max-claimants": "max_claimants"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetInt(flag)
+					payloadMap[field] = value
+				}
+			}
+			for flag, field := range map[string]string{"tags": "tags", "skills-required": "skills_required"} {
+				if cmd.Flags().Changed(flag) {
+					value, _ := cmd.Flags().GetString(flag)
+					var list []string
+					if err := json.Unmarshal([]byte(value), &list); err != nil {
+						fmt.Printf("Error: Invalid %s JSON: %v. Please use format `[\"a\",\"b\"]`\n", flag, err)
+						return
+					}
+					payloadMap[field] = list
+				}
+			}
+			if len(payloadMap) == 0 {
+				fmt.Println("Nothing to change. Pass at least one flag, see 'osm task edit --help'.")
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPatch, fmt.Sprintf("/tasks/%d", taskID), payloadMap)
+			if err != nil {
+				fmt.Printf("Error updating task: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error updating task: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Task %d updated\n", taskID)
+		},
+	}
+	editCmd.Flags().StringP("title", "t", "", "New title")
+	editCmd.Flags().StringP("description", "d", "", "New description")
+	editCmd.Flags().String("difficulty", "", "New difficulty level (easy, medium, hard)")
+	editCmd.Flags().Int("estimated-hours", 0, "New estimated hours")
+	editCmd.Flags().String("tags", "", "JSON array replacing the tags, e.g., '[\"bug\"]'")
+	editCmd.Flags().String("skills-required", "", "JSON array replacing the required skills, e.g., '[\"go\"]'")
+	editCmd.Flags().String("claim-policy", "", "New claim policy: exclusive, competitive or limited")
+	editCmd.Flags().Int("max-claimants", 0, "Number of claims to accept under the limited claim policy")
+	taskCmd.AddCommand(editCmd)
+
+	archiveCmd := &cobra.Command{
+		Use:   "archive [task-id]",
+		Short: "Archive a task",
+		Long:  `Take a task off the board. A bounty still held in escrow is refunded to you and pending claims are rejected.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			reason, _ := cmd.Flags().GetString("reason")
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"reason": reason}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/tasks/%d/archive", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error archiving task: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error archiving task: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Task %d archived\n", taskID)
+		},
+	}
+	archiveCmd.Flags().String("reason", "", "Why the task is being archived (optional)")
+	taskCmd.AddCommand(archiveCmd)
+
+	depsCmd := &cobra.Command{
+		Use:   "deps [task-id]",
+		Short: "Show or change a task's dependencies",
+		Long: `Print the tree of tasks a task depends on. A task is blocked, and cannot be
+claimed, until every task it depends on is completed.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			showTaskDependencies(uint(taskID))
+		},
+	}
+
+	depsAddCmd := &cobra.Command{
+		Use:   "add [task-id] [depends-on-task-id]",
+		Short: "Make a task depend on another task",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			dependsOnID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid prerequisite task ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			payload := map[string]uint{"depends_on_id": uint(dependsOnID)}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/dependencies", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error adding dependency: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error adding dependency: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Task %d now depends on task %d\n", taskID, dependsOnID)
+		},
+	}
+	depsCmd.AddCommand(depsAddCmd)
+
+	depsRemoveCmd := &cobra.Command{
+		Use:   "remove [task-id] [depends-on-task-id]",
+		Short: "Remove a dependency between two tasks",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			dependsOnID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid prerequisite task ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/tasks/%d/dependencies/%d", taskID, dependsOnID), nil)
+			if err != nil {
+				fmt.Printf("Error removing dependency: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error removing dependency: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Task %d no longer depends on task %d\n", taskID, dependsOnID)
+		},
+	}
+	depsCmd.AddCommand(depsRemoveCmd)
+	taskCmd.AddCommand(depsCmd)
+
+	commentCmd := &cobra.Command{
+		Use:   "comment [task-id] [body]",
+		Short: "Comment on a task",
+		Long: `Post a comment on a task's discussion thread. The body is markdown, and
+@username mentions are linked to the mentioned users.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			replyTo, _ := cmd.Flags().GetUint("reply-to")
+			postComment(fmt.Sprintf("/tasks/%d/comments", taskID), args[1], replyTo)
+		},
+	}
+	commentCmd.Flags().Uint("reply-to", 0, "ID of the comment to reply to")
+	taskCmd.AddCommand(commentCmd)
+
+	commentsCmd := &cobra.Command{
+		Use:   "comments [task-id]",
+		Short: "Show a task's discussion thread",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			showTaskComments(uint(taskID))
+		},
+	}
+	taskCmd.AddCommand(commentsCmd)
+
+	mentorsCmd := &cobra.Command{
+		Use:   "mentors [task-id]",
+		Short: "List users who volunteered to mentor a task",
+		Long: `List users who volunteered to mentor a task. Maintainers can assign one of
+them to a claim with 'osm claim mentor'.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			showMentorVolunteers(uint(taskID))
+		},
+	}
+
+	volunteerCmd := &cobra.Command{
+		Use:   "volunteer [task-id]",
+		Short: "Volunteer to mentor a task",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			note, _ := cmd.Flags().GetString("note")
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"note": note}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/mentors", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error volunteering: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error volunteering: %s\n", string(body))
+				return
+			}
+			fmt.Printf("You volunteered to mentor task %d\n", taskID)
+		},
+	}
+	volunteerCmd.Flags().String("note", "", "What you can help with (optional)")
+	mentorsCmd.AddCommand(volunteerCmd)
+
+	withdrawMentorCmd := &cobra.Command{
+		Use:   "withdraw [task-id]",
+		Short: "Stop volunteering to mentor a task",
+		Long: `Stop volunteering to mentor a task. Claims you were already assigned to
+keep you as their mentor until a maintainer changes it.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/tasks/%d/mentors", taskID), nil)
+			if err != nil {
+				fmt.Printf("Error withdrawing: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error withdrawing: %s\n", string(body))
+				return
+			}
+			fmt.Printf("You no longer volunteer to mentor task %d\n", taskID)
+		},
+	}
+	mentorsCmd.AddCommand(withdrawMentorCmd)
+	taskCmd.AddCommand(mentorsCmd)
+	taskCmd.AddCommand(newTaskLogTimeCmd())
+	taskCmd.AddCommand(newTaskTimeCmd())
+
+	return taskCmd
+}
+
+func showTaskDependencies(taskID uint) {
+	url := fmt.Sprintf("%s/tasks/%d/dependencies", ServerURL(), taskID)
 	resp, err := http.Get(url)
 	if err != nil {
-		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", baseURL)
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
 		return
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading server response: %v\n", err)
+		return
+	}
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error: Failed to list tasks (Status: %s)\n", resp.Status)
+		fmt.Printf("Error fetching dependencies: %s\n", string(body))
 		return
 	}
 
+	var tree models.DependencyNode
+	if err := json.Unmarshal(body, &tree); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return
+	}
+	if len(tree.DependsOn) == 0 {
+		fmt.Printf("Task %d (%s) has no dependencies.\n", tree.Task.ID, tree.Task.Title)
+		return
+	}
+	printDependencyNode(tree, "", "")
+}
+
+// printDependencyNode prints node and its prerequisites as an indented tree.
+func printDependencyNode(node models.DependencyNode, prefix, childPrefix string) {
+	blocked := ""
+	if node.Task.IsBlocked() {
+		blocked = " [blocked]"
+	}
+	fmt.Printf("%s#%d %s (%s)%s\n", prefix, node.Task.ID, node.Task.Title, node.Task.Status, blocked)
+	for i, child := range node.DependsOn {
+		if i == len(node.DependsOn)-1 {
+			printDependencyNode(child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			printDependencyNode(child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func showTaskHistory(taskID uint) {
+	url := fmt.Sprintf("%s/tasks/%d/history", ServerURL(), taskID)
+	resp, err := http.Get(url)
+	if err != nil {
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+		return
+	}
+	defer resp.Body.Close()
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		fmt.Printf("Error reading server response: %v\n", err)
 		return
 	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error: Failed to fetch task history: %s\n", string(body))
+		return
+	}
 
-	var tasks []models.Task
-	if err := json.Unmarshal(body, &tasks); err != nil {
+	var entries []models.TaskStatusHistory
+	if err := json.Unmarshal(body, &entries); err != nil {
 		fmt.Printf("Error parsing server response: %v\n", err)
 		return
 	}
+	if len(entries) == 0 {
+		fmt.Println("No status changes recorded.")
+		return
+	}
+
+	fmt.Printf("--- History of task %d ---\n", taskID)
+	for _, e := range entries {
+		actor := "system"
+		if e.Actor != nil {
+			actor = e.Actor.Username
+		}
+		fmt.Printf("%s  %s -> %s  by %s", e.CreatedAt.Format("2006-01-02 15:04"), e.FromStatus, e.ToStatus, actor)
+		if e.Reason != "" {
+			fmt.Printf("  (%s)", e.Reason)
+		}
+		fmt.Println()
+	}
+}
+
+// listTasks prints the tasks matching params. With all set it follows the
+// cursors until the last page.
+func listTasks(params url.Values, all bool) {
+	var tasks []models.Task
+	next := ""
+	for {
+		page, cursor, ok := fetchTaskPage(params)
+		if !ok {
+			return
+		}
+		tasks = append(tasks, page...)
+		next = cursor
+		if next == "" || !all {
+			break
+		}
+		params.Set("cursor", next)
+	}
 
 	if len(tasks) == 0 {
 		fmt.Println("No tasks found.")
@@ -339,7 +947,124 @@ func listTasks(projectIDStr, status string) {
 
 	fmt.Println("--- Tasks ---")
 	for _, t := range tasks {
-		fmt.Printf("ID: %d, Title: %s, Project ID: %d, Status: %s, Bounty: %.2f\n",
-			t.ID, t.Title, t.ProjectID, t.Status, t.BountyAmount)
+		policy := t.ClaimPolicy
+		if t.ClaimPolicy == models.ClaimPolicyLimited {
+			policy = fmt.Sprintf("%s (%d)", t.ClaimPolicy, t.MaxClaimants)
+		}
+		blocked := ""
+		if t.IsBlocked() {
+			blocked = fmt.Sprintf(", Blocked by: %v", t.BlockedBy)
+		}
+		fmt.Printf("ID: %d, Title: %s, Project ID: %d, Status: %s, Claims: %s, Bounty: %.2f%s\n",
+			t.ID, t.Title, t.ProjectID, t.Status, policy, t.BountyAmount, blocked)
+	}
+	if next != "" {
+		fmt.Printf("\nMore tasks match. Next page: --cursor %s\n", next)
+	}
+}
+
+// fetchTaskPage fetches one page of GET /tasks and returns it along with the
+// cursor of the next page.
+func fetchTaskPage(params url.Values) ([]models.Task, string, bool) {
+	baseURL := ServerURL() + "/tasks"
+	endpoint := baseURL
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := http.Get(endpoint)
+	if err != nil {
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", baseURL)
+		return nil, "", false
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading server response: %v\n", err)
+		return nil, "", false
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error: Failed to list tasks: %s\n", string(body))
+		return nil, "", false
+	}
+
+	var tasks []models.Task
+	if err := json.Unmarshal(body, &tasks); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return nil, "", false
+	}
+	return tasks, resp.Header.Get("X-Next-Cursor"), true
+}
+func showTaskComments(taskID uint) {
+	url := fmt.Sprintf("%s/tasks/%d/comments", ServerURL(), taskID)
+	resp, err := http.Get(url)
+	if err != nil {
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading server response: %v\n", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error fetching comments: %s\n", string(body))
+		return
+	}
+
+	var comments []models.Comment
+	if err := json.Unmarshal(body, &comments); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return
 	}
-}
\ No newline at end of file
+	if len(comments) == 0 {
+		fmt.Printf("No comments on task %d yet.\n", taskID)
+		return
+	}
+	printCommentThread(comments)
+}
+
+func showMentorVolunteers(taskID uint) {
+	url := fmt.Sprintf("%s/tasks/%d/mentors", ServerURL(), taskID)
+	resp, err := http.Get(url)
+	if err != nil {
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading server response: %v\n", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error fetching mentors: %s\n", string(body))
+		return
+	}
+
+	var volunteers []models.MentorVolunteer
+	if err := json.Unmarshal(body, &volunteers); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return
+	}
+	if len(volunteers) == 0 {
+		fmt.Printf("Nobody has volunteered to mentor task %d yet.\n", taskID)
+		return
+	}
+	fmt.Printf("--- Mentor volunteers for task %d ---\n", taskID)
+	for _, volunteer := range volunteers {
+		name := fmt.Sprintf("user %d", volunteer.UserID)
+		if volunteer.User != nil {
+			name = volunteer.User.Username
+		}
+		fmt.Printf("User ID: %d (%s), since %s", volunteer.UserID, name, volunteer.CreatedAt.Format("2006-01-02"))
+		if volunteer.Note != "" {
+			fmt.Printf(": %s", volunteer.Note)
+		}
+		fmt.Println()
+	}
+}
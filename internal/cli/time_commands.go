@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"ossyne/internal/models"
+	"strconv"
+	"time"
+	"github.com/spf13/cobra"
+)
+
+func newTaskLogTimeCmd() *cobra.Command {
+	logTimeCmd := &cobra.Command{
+		Use:   "log-time [task-id] [duration] [note]",
+		Short: "Log time spent on a task you have claimed",
+		Long: `Log time spent on a task against your accepted claim, for example:
+
+  osm task log-time 42 2h30m "refactor parser"
+
+The duration is written like 45m, 1h or 2h30m. Time is logged for today unless
+--date names an earlier day.`,
+		Args: cobra.RangeArgs(2, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			duration, err := time.ParseDuration(args[1])
+			if err != nil {
+				fmt.Printf("Error: Invalid duration %q, expected a value such as 2h30m or 45m\n", args[1])
+				return
+			}
+			note := ""
+			if len(args) == 3 {
+				note = args[2]
+			}
+			date, _ := cmd.Flags().GetString("date")
+
+			apiClient := NewAPIClient()
+			payload := map[string]string{"duration": args[1], "note": note, "spent_on": date}
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodPost, fmt.Sprintf("/tasks/%d/time", taskID), payload)
+			if err != nil {
+				fmt.Printf("Error logging time: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusCreated {
+				fmt.Printf("Error logging time: %s\n", string(body))
+				return
+			}
+			var entry models.TimeEntry
+			if err := json.Unmarshal(body, &entry); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			fmt.Printf("Logged %s on task %d for %s (entry %d)\n",
+				formatMinutes(int(duration.Round(time.Minute)/time.Minute)), taskID, entry.SpentOn.Format("2006-01-02"), entry.ID)
+		},
+	}
+	logTimeCmd.Flags().String("date", "", "Day the work was done, as YYYY-MM-DD (default today)")
+	return logTimeCmd
+}
+
+func newTaskTimeCmd() *cobra.Command {
+	timeCmd := &cobra.Command{
+		Use:   "time [task-id]",
+		Short: "Show the time logged on a task",
+		Long:  `Show the time logged on a task in total, per user and entry by entry.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid task ID: %v\n", err)
+				return
+			}
+			var summary models.TaskTime
+			if !getPublicJSON(fmt.Sprintf("/tasks/%d/time", taskID), "time", &summary) {
+				return
+			}
+			if summary.Minutes == 0 {
+				fmt.Printf("No time logged on task %d yet.\n", taskID)
+				return
+			}
+			fmt.Printf("Task %d: %s logged", taskID, formatMinutes(summary.Minutes))
+			if summary.EstimatedHours > 0 {
+				fmt.Printf(" of %dh estimated", summary.EstimatedHours)
+			}
+			fmt.Println()
+			for _, user := range summary.Users {
+				fmt.Printf("  %-20s %s\n", user.Username, formatMinutes(user.Minutes))
+			}
+			fmt.Println("\nEntries:")
+			for _, entry := range summary.Entries {
+				username := "unknown"
+				if entry.User != nil {
+					username = entry.User.Username
+				}
+				fmt.Printf("  #%-5d %s  %-8s %-20s %s\n", entry.ID, entry.SpentOn.Format("2006-01-02"), formatMinutes(entry.Minutes), username, entry.Note)
+			}
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [entry-id]",
+		Short: "Delete a time entry you logged",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			entryID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid time entry ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodDelete, fmt.Sprintf("/time-entries/%d", entryID), nil)
+			if err != nil {
+				fmt.Printf("Error deleting time entry: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error deleting time entry: %s\n", string(body))
+				return
+			}
+			fmt.Printf("Time entry %d deleted\n", entryID)
+		},
+	}
+	timeCmd.AddCommand(deleteCmd)
+	return timeCmd
+}
+
+func newUserTimeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "time [user-id]",
+		Short: "Show the time a user logged, per task",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			userID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid user ID: %v\n", err)
+				return
+			}
+			var summary models.UserTimeSummary
+			if !getPublicJSON(fmt.Sprintf("/users/%d/time", userID), "time", &summary) {
+				return
+			}
+			if summary.Minutes == 0 {
+				fmt.Printf("User %d has not logged any time yet.\n", userID)
+				return
+			}
+			fmt.Printf("User %d: %s logged\n", userID, formatMinutes(summary.Minutes))
+			for _, task := range summary.Tasks {
+				fmt.Printf("  #%-5d %-8s %s\n", task.TaskID, formatMinutes(task.Minutes), task.Title)
+			}
+		},
+	}
+}
+
+func newProjectTimeReportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "time-report [project-id]",
+		Short: "Compare estimated and logged hours on a project's tasks",
+		Long: `Compare each task's estimate with the time logged on it. A ratio above 1
+means the task took longer than estimated. The project totals only count tasks
+that have both an estimate and logged time. Only maintainers can see the report.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: Invalid project ID: %v\n", err)
+				return
+			}
+
+			apiClient := NewAPIClient()
+			resp, err := apiClient.DoAuthenticatedRequest(http.MethodGet, fmt.Sprintf("/projects/%d/time-report", projectID), nil)
+			if err != nil {
+				fmt.Printf("Error fetching time report: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				fmt.Printf("Error reading response: %v\n", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error fetching time report: %s\n", string(body))
+				return
+			}
+			var report models.EstimateReport
+			if err := json.Unmarshal(body, &report); err != nil {
+				fmt.Printf("Error parsing server response: %v\n", err)
+				return
+			}
+			if len(report.Tasks) == 0 {
+				fmt.Printf("No task in project %d has an estimate or logged time yet.\n", projectID)
+				return
+			}
+
+			fmt.Printf("%-6s %-12s %9s %9s %6s  %s\n", "TASK", "STATUS", "ESTIMATE", "ACTUAL", "RATIO", "TITLE")
+			for _, row := range report.Tasks {
+				estimate, ratio := "-", "-"
+				if row.EstimatedHours > 0 {
+					estimate = fmt.Sprintf("%dh", row.EstimatedHours)
+				}
+				if row.Ratio != nil {
+					ratio = fmt.Sprintf("%.2f", *row.Ratio)
+				}
+				fmt.Printf("#%-5d %-12s %9s %8.2fh %6s  %s\n", row.TaskID, row.Status, estimate, row.ActualHours, ratio, row.Title)
+			}
+			fmt.Printf("\nEstimated %dh, actual %.2fh", report.EstimatedHours, report.ActualHours)
+			if report.Ratio != nil {
+				fmt.Printf(" (ratio %.2f)", *report.Ratio)
+			}
+			fmt.Println()
+			if report.Unestimated > 0 {
+				fmt.Printf("%d tasks with logged time have no estimate and are not in the totals.\n", report.Unestimated)
+			}
+		},
+	}
+}
+
+// getPublicJSON fetches an unauthenticated endpoint into out, printing any
+// error. what names the resource in error messages.
+func getPublicJSON(endpoint, what string, out interface{}) bool {
+	resp, err := http.Get(ServerURL() + endpoint)
+	if err != nil {
+		fmt.Printf("Error: Could not connect to the OSM server at %s. Is it running?\n", ServerURL())
+		return false
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading server response: %v\n", err)
+		return false
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Error fetching %s: %s\n", what, string(body))
+		return false
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		fmt.Printf("Error parsing server response: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// formatMinutes renders minutes the way log-time accepts them, such as 2h30m.
+func formatMinutes(minutes int) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
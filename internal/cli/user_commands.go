@@ -63,12 +63,13 @@ func NewUserCmd() *cobra.Command {
 		},
 	}
 	userCmd.AddCommand(viewCmd)
+	userCmd.AddCommand(newUserTimeCmd())
 
 	return userCmd
 }
 
 func viewUser(userID uint) {
-	userURL := fmt.Sprintf("http://localhost:8080/users/%d", userID)
+	userURL := fmt.Sprintf("%s/users/%d", ServerURL(), userID)
 	resp, err := http.Get(userURL)
 	if err != nil {
 		fmt.Printf("Error: Could not connect to the OSM server to fetch user %d. Is it running?\n", userID)
@@ -99,7 +100,7 @@ func viewUser(userID uint) {
 	fmt.Printf("Ratings: %d\n", user.Ratings)
 	fmt.Printf("Roles: %s\n", strings.Join(user.Roles, ", "))
 
-	userSkillsURL := fmt.Sprintf("http://localhost:8080/users/%d/skills", userID)
+	userSkillsURL := fmt.Sprintf("%s/users/%d/skills", ServerURL(), userID)
 	respSkills, err := http.Get(userSkillsURL)
 	if err != nil {
 		fmt.Printf("Error: Could not connect to the OSM server to fetch user skills for %d. Is it running?\n", userID)
@@ -135,7 +136,7 @@ func viewUser(userID uint) {
 }
 
 func createTestUser(username, email, githubID string) {
-	const serverURL = "http://localhost:8080/dev/users/create"
+	serverURL := ServerURL() + "/dev/users/create"
 
 	payload := map[string]interface{}{
 		"username": username,
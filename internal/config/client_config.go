@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"github.com/spf13/viper"
+)
+
+const (
+	DefaultServerURL = "http://localhost:8080"
+	DefaultProfile   = "default"
+	ServerEnvVar     = "OSSYNE_SERVER"
+	ProfileEnvVar    = "OSSYNE_PROFILE"
+)
+
+var (
+	ErrProfileNotFound    = errors.New("profile not found")
+	ErrInvalidProfileName = errors.New("profile names may only contain lowercase letters, digits, '-' and '_'")
+	ErrServerMismatch     = errors.New("server does not match the profile's server")
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidProfileName, name)
+	}
+	return nil
+}
+
+// Profile is one server/account pair the CLI can switch between. Its tokens
+// live in the keyring under the profile's name.
+type Profile struct {
+	Server string `mapstructure:"server"`
+}
+
+// ClientConfig is the per-user configuration of the osm CLI and TUI, stored
+// as YAML in the user's config directory.
+type ClientConfig struct {
+	// Server is the default profile's server, kept at the top level for
+	// config files written before profiles existed.
+	Server         string             `mapstructure:"server"`
+	CurrentProfile string             `mapstructure:"current_profile"`
+	Profiles       map[string]Profile `mapstructure:"profiles"`
+}
+
+func ClientConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "ossyne", "config.yaml"), nil
+}
+
+// LoadClientConfig reads the CLI config file. A missing file is not an error
+// and yields an empty config.
+func LoadClientConfig() (ClientConfig, error) {
+	var cfg ClientConfig
+	path, err := ClientConfigPath()
+	if err != nil {
+		return cfg, err
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := v.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func SaveClientConfig(cfg ClientConfig) error {
+	path, err := ClientConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	v := viper.New()
+	if cfg.Server != "" {
+		v.Set("server", cfg.Server)
+	}
+	if cfg.CurrentProfile != "" {
+		v.Set("current_profile", cfg.CurrentProfile)
+	}
+	if len(cfg.Profiles) > 0 {
+		profiles := make(map[string]interface{}, len(cfg.Profiles))
+		for name, p := range cfg.Profiles {
+			profiles[name] = map[string]interface{}{"server": p.Server}
+		}
+		v.Set("profiles", profiles)
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Profile returns the named profile. The default profile always exists.
+func (c *ClientConfig) Profile(name string) (Profile, error) {
+	if name == DefaultProfile {
+		return Profile{Server: c.Server}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	return p, nil
+}
+
+// SetProfile creates or updates the named profile.
+func (c *ClientConfig) SetProfile(name string, p Profile) {
+	if name == DefaultProfile {
+		c.Server = p.Server
+		return
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	c.Profiles[name] = p
+}
+
+// ProfileNames lists every profile, the default one first.
+func (c *ClientConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// ResolveProfile picks the active profile: an explicit flag value, then the
+// OSSYNE_PROFILE environment variable, then the config file's current
+// profile and finally the default profile. Profiles other than the default
+// must exist, so a typo cannot quietly switch servers and keyring entries.
+func ResolveProfile(flagValue string) (string, error) {
+	name, source := DefaultProfile, ""
+	switch {
+	case flagValue != "":
+		name, source = flagValue, "--profile"
+	case os.Getenv(ProfileEnvVar) != "":
+		name, source = os.Getenv(ProfileEnvVar), "$"+ProfileEnvVar
+	}
+	cfg, err := LoadClientConfig()
+	if err != nil {
+		if name == DefaultProfile {
+			return name, nil
+		}
+		return "", err
+	}
+	if source == "" && cfg.CurrentProfile != "" {
+		name, source = cfg.CurrentProfile, "current_profile"
+	}
+	if _, err := cfg.Profile(name); err != nil {
+		return "", fmt.Errorf("%w (set by %s); run 'osm profile list' to see the profiles", err, source)
+	}
+	return name, nil
+}
+
+// ResolveServerURL picks the server the CLI talks to. An explicit flag value
+// wins, then the given profile's server and finally DefaultServerURL. The
+// OSSYNE_SERVER environment variable replaces the default profile's server;
+// for any other profile it must match the profile's server, since the
+// profile's tokens would otherwise be sent to another server.
+func ResolveServerURL(flagValue, profile string) (string, error) {
+	if flagValue != "" {
+		return NormalizeServerURL(flagValue), nil
+	}
+	server := ""
+	if cfg, err := LoadClientConfig(); err == nil {
+		if p, err := cfg.Profile(profile); err == nil && p.Server != "" {
+			server = NormalizeServerURL(p.Server)
+		}
+	}
+	if env := os.Getenv(ServerEnvVar); env != "" {
+		env = NormalizeServerURL(env)
+		if profile != DefaultProfile && server != "" && env != server {
+			return "", fmt.Errorf("%w: $%s is %s but profile '%s' uses %s; unset %s or pass --server explicitly",
+				ErrServerMismatch, ServerEnvVar, env, profile, server, ServerEnvVar)
+		}
+		return env, nil
+	}
+	if server != "" {
+		return server, nil
+	}
+	return DefaultServerURL, nil
+}
+
+func NormalizeServerURL(raw string) string {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	return raw
+}
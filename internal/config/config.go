@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 	"github.com/spf13/viper"
 )
 
@@ -15,6 +17,30 @@ type Config struct {
 	GitHubClientID    string `mapstructure:"GITHUB_CLIENT_ID"`
 	GitHubClientSecret string `mapstructure:"GITHUB_CLIENT_SECRET"`
 	GitHubRedirectURL  string `mapstructure:"GITHUB_REDIRECT_URL"`
+	// GitHubAPIURL overrides the GitHub REST API root used to log users in
+	// and import issues, for GitHub Enterprise. Empty means api.github.com.
+	GitHubAPIURL       string `mapstructure:"GITHUB_API_URL"`
+	AdminUsernames     string `mapstructure:"ADMIN_USERNAMES"`
+	CLICallbackURLs    string `mapstructure:"CLI_CALLBACK_URLS"`
+	AppEnv             string `mapstructure:"APP_ENV"`
+	EnableDevRoutes    bool   `mapstructure:"ENABLE_DEV_ROUTES"`
+	// ClaimDeadline is how long an accepted claim may go without activity
+	// before it expires and its task reopens. Zero disables expiry.
+	ClaimDeadline       time.Duration `mapstructure:"CLAIM_DEADLINE"`
+	ClaimExpiryInterval time.Duration `mapstructure:"CLAIM_EXPIRY_INTERVAL"`
+}
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// DefaultCLICallbackURLs are the login callbacks allowed when CLI_CALLBACK_URLS
+// is not set: the local listener started by 'osm auth login'.
+var DefaultCLICallbackURLs = []string{
+	"http://localhost:9999/auth/cli/callback",
+	"http://127.0.0.1:9999/auth/cli/callback",
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -22,14 +48,75 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
 	viper.AutomaticEnv()
+	viper.SetDefault("APP_ENV", EnvProduction)
+	viper.SetDefault("ENABLE_DEV_ROUTES", false)
+	viper.SetDefault("CLAIM_DEADLINE", "336h")
+	viper.SetDefault("CLAIM_EXPIRY_INTERVAL", "1h")
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
 	}
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+	err = config.Validate()
 	return
 }
 
+// Validate rejects settings the server must not start with. Unauthenticated
+// dev routes are only allowed when APP_ENV is development.
+func (c *Config) Validate() error {
+	switch c.AppEnv {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		return fmt.Errorf("APP_ENV must be one of %s, %s or %s, got %q", EnvDevelopment, EnvStaging, EnvProduction, c.AppEnv)
+	}
+	if c.EnableDevRoutes && c.AppEnv != EnvDevelopment {
+		return fmt.Errorf("ENABLE_DEV_ROUTES is set but APP_ENV is %s; dev routes allow unauthenticated writes and are only available in %s", c.AppEnv, EnvDevelopment)
+	}
+	if c.ClaimDeadline < 0 {
+		return fmt.Errorf("CLAIM_DEADLINE must not be negative, got %s", c.ClaimDeadline)
+	}
+	if c.ClaimDeadline > 0 && c.ClaimExpiryInterval <= 0 {
+		return fmt.Errorf("CLAIM_EXPIRY_INTERVAL must be positive when CLAIM_DEADLINE is set, got %s", c.ClaimExpiryInterval)
+	}
+	return nil
+}
+
+// DevRoutesEnabled reports whether the unauthenticated /dev route group
+// should be mounted.
+func (c *Config) DevRoutesEnabled() bool {
+	return c.EnableDevRoutes && c.AppEnv == EnvDevelopment
+}
+
+// BootstrapAdmins returns the GitHub usernames listed in ADMIN_USERNAMES. They
+// are granted the admin role the next time they log in.
+func (c *Config) BootstrapAdmins() []string {
+	var admins []string
+	for _, name := range strings.Split(c.AdminUsernames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			admins = append(admins, name)
+		}
+	}
+	return admins
+}
+
+// CallbackAllowlist returns the URLs the server may redirect a freshly minted
+// session to after a browser login, from the comma separated CLI_CALLBACK_URLS.
+func (c *Config) CallbackAllowlist() []string {
+	var urls []string
+	for _, u := range strings.Split(c.CLICallbackURLs, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	if len(urls) == 0 {
+		return DefaultCLICallbackURLs
+	}
+	return urls
+}
+
 func (c *Config) DSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName)
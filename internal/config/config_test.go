@@ -0,0 +1,40 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDevRoutesGating(t *testing.T) {
+	tests := []struct {
+		name        string
+		appEnv      string
+		devRoutes   bool
+		wantErr     string
+		wantMounted bool
+	}{
+		{name: "development with dev routes", appEnv: EnvDevelopment, devRoutes: true, wantMounted: true},
+		{name: "development without dev routes", appEnv: EnvDevelopment},
+		{name: "staging with dev routes", appEnv: EnvStaging, devRoutes: true, wantErr: "ENABLE_DEV_ROUTES"},
+		{name: "staging without dev routes", appEnv: EnvStaging},
+		{name: "production with dev routes", appEnv: EnvProduction, devRoutes: true, wantErr: "ENABLE_DEV_ROUTES"},
+		{name: "production without dev routes", appEnv: EnvProduction},
+		{name: "unknown environment", appEnv: "dev", devRoutes: true, wantErr: "APP_ENV"},
+		{name: "empty environment", appEnv: "", wantErr: "APP_ENV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{AppEnv: tt.appEnv, EnableDevRoutes: tt.devRoutes}
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() error = %v, want one mentioning %s", err, tt.wantErr)
+			}
+			if got := cfg.DevRoutesEnabled(); got != tt.wantMounted {
+				t.Errorf("DevRoutesEnabled() = %v, want %v", got, tt.wantMounted)
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ func Init(c config.Config) error {
 	var err error
 	dsn := c.DSN()
 
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -0,0 +1,153 @@
+// Package dbtest points db.DB at a throwaway MySQL database for the
+// database-backed tests of other packages.
+package dbtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// DSNEnv names the MySQL database the database-backed tests run against,
+// e.g. "ossyne:secret@tcp(127.0.0.1:3306)/ossyne_test". Those tests are skipped
+// when it is unset. Every table in that database is dropped and migrated from
+// scratch, so its name must end in _test.
+const DSNEnv = "OSSYNE_TEST_DSN"
+
+var (
+	openOnce sync.Once
+	openErr  error
+)
+
+// Use points db.DB at the migrated test database and empties every table,
+// or skips the test when no test database is configured.
+func Use(t testing.TB) {
+	t.Helper()
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNEnv)
+	}
+	openOnce.Do(func() {
+		openErr = open(dsn)
+	})
+	if openErr != nil {
+		t.Fatalf("test database: %v", openErr)
+	}
+	if err := forEachTable("TRUNCATE TABLE `%s`"); err != nil {
+		t.Fatalf("failed to empty test database: %v", err)
+	}
+}
+
+// CreateUser inserts a user with the given roles.
+func CreateUser(t testing.TB, username string, roles ...string) *models.User {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", Roles: models.JSONStringSlice(roles)}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user %s: %v", username, err)
+	}
+	return &user
+}
+
+// Create inserts value without touching its associations.
+func Create(t testing.TB, value interface{}) {
+	t.Helper()
+	if err := db.DB.Omit(clause.Associations).Create(value).Error; err != nil {
+		t.Fatalf("failed to create %T: %v", value, err)
+	}
+}
+
+func open(dsn string) error {
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	dsn += sep + "charset=utf8mb4&parseTime=True&loc=Local&multiStatements=true"
+
+	conn, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true, Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	var name string
+	if err := conn.Raw("SELECT DATABASE()").Scan(&name).Error; err != nil {
+		return fmt.Errorf("failed to read the database name: %w", err)
+	}
+	if !strings.HasSuffix(name, "_test") {
+		return fmt.Errorf("refusing to reset database %q, its name must end in _test", name)
+	}
+	db.DB = conn
+
+	if err := forEachTable("DROP TABLE `%s`"); err != nil {
+		return fmt.Errorf("failed to drop old tables: %w", err)
+	}
+	dir, err := migrationsDir()
+	if err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := db.DB.Exec(string(migration)).Error; err != nil {
+			return fmt.Errorf("migration %s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// migrationsDir finds the migrations directory next to go.mod, walking up
+// from the directory of the package under test.
+func migrationsDir() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "migrations"), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod not found above the test directory")
+		}
+		dir = parent
+	}
+}
+
+// forEachTable runs the statement, formatted with each table name, on a
+// single connection with foreign key checks off.
+func forEachTable(statement string) error {
+	return db.DB.Connection(func(conn *gorm.DB) error {
+		var tables []string
+		if err := conn.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'").
+			Scan(&tables).Error; err != nil {
+			return err
+		}
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+		for _, table := range tables {
+			if err := conn.Exec(fmt.Sprintf(statement, table)).Error; err != nil {
+				return fmt.Errorf("%s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,28 @@
+package models
+
+const (
+	ScopeTasksWrite         = "tasks:write"
+	ScopeProjectsWrite      = "projects:write"
+	ScopeContributionsRead  = "contributions:read"
+	ScopeContributionsWrite = "contributions:write"
+	ScopePaymentsRead       = "payments:read"
+	ScopeProjectsExport     = "projects:export"
+)
+
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeTasksWrite, ScopeProjectsWrite, ScopeContributionsRead, ScopeContributionsWrite,
+		ScopePaymentsRead, ScopeProjectsExport:
+		return true
+	}
+	return false
+}
+
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
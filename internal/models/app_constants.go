@@ -14,4 +14,12 @@ const (
 	VerificationStatusAutoVerified = "auto_verified"
 	VerificationStatusManualVerified = "manual_verified"
 	VerificationStatusRejected   = "rejected"
-)
\ No newline at end of file
+)
+
+const (
+	ClaimStatusPending   = "pending"
+	ClaimStatusAccepted  = "accepted"
+	ClaimStatusRejected  = "rejected"
+	ClaimStatusWithdrawn = "withdrawn"
+	ClaimStatusExpired   = "expired"
+)
@@ -0,0 +1,68 @@
+package models
+
+// Claim policies decide how many contributors may hold a task at once.
+const (
+	// ClaimPolicyExclusive locks the task to the first accepted claimant.
+	ClaimPolicyExclusive = "exclusive"
+	// ClaimPolicyCompetitive lets any number of claimants work in parallel;
+	// the first accepted contribution wins the bounty.
+	ClaimPolicyCompetitive = "competitive"
+	// ClaimPolicyLimited works like competitive but caps accepted claims at
+	// the task's MaxClaimants.
+	ClaimPolicyLimited = "limited"
+)
+
+func IsValidClaimPolicy(policy string) bool {
+	switch policy {
+	case ClaimPolicyExclusive, ClaimPolicyCompetitive, ClaimPolicyLimited:
+		return true
+	}
+	return false
+}
+
+// ClaimLimit is the most claims the task may have accepted at once, or 0 for
+// no limit.
+func (t *Task) ClaimLimit() int {
+	switch t.ClaimPolicy {
+	case ClaimPolicyCompetitive:
+		return 0
+	case ClaimPolicyLimited:
+		return t.MaxClaimants
+	}
+	return 1
+}
+
+// AllowsParallelWork reports whether several claimants may work on the task
+// at the same time.
+func (t *Task) AllowsParallelWork() bool {
+	return t.ClaimLimit() != 1
+}
+
+// AcceptsClaims reports whether new claims may be requested in the task's
+// current status. Exclusive tasks only take claims while open; parallel tasks
+// keep taking them until the work is completed. Blocked tasks take none.
+func (t *Task) AcceptsClaims() bool {
+	if t.IsBlocked() {
+		return false
+	}
+	switch t.Status {
+	case TaskStatusOpen:
+		return true
+	case TaskStatusClaimed, TaskStatusInProgress, TaskStatusSubmitted:
+		return t.AllowsParallelWork()
+	}
+	return false
+}
+
+// AcceptsContributions reports whether a claimant may submit work in the
+// task's current status. Parallel tasks keep taking submissions after the
+// first one arrives.
+func (t *Task) AcceptsContributions() bool {
+	switch t.Status {
+	case TaskStatusClaimed, TaskStatusInProgress:
+		return true
+	case TaskStatusSubmitted:
+		return t.AllowsParallelWork()
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+package models
+
+const (
+	DeviceAuthStatusPending  = "pending"
+	DeviceAuthStatusApproved = "approved"
+	DeviceAuthStatusDenied   = "denied"
+	DeviceAuthStatusConsumed = "consumed"
+)
\ No newline at end of file
@@ -0,0 +1,297 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormatVersion is bumped whenever the layout of ProjectExport changes
+// in a way older imports cannot read.
+const ExportFormatVersion = 1
+
+// Tables of a project export. The CSV form exports one of them at a time.
+const (
+	ExportTableTasks            = "tasks"
+	ExportTableClaims           = "claims"
+	ExportTableContributions    = "contributions"
+	ExportTablePayouts          = "payouts"
+	ExportTableReputationEvents = "reputation_events"
+)
+
+var ExportTables = []string{ExportTableTasks, ExportTableClaims, ExportTableContributions, ExportTablePayouts, ExportTableReputationEvents}
+
+// ProjectExport is the JSON form of a project export. The server writes it
+// one row at a time, in the field order below.
+type ProjectExport struct {
+	Version          int                     `json:"version"`
+	ExportedAt       time.Time               `json:"exported_at"`
+	Project          ExportProject           `json:"project"`
+	Tasks            []ExportTask            `json:"tasks"`
+	Claims           []ExportClaim           `json:"claims"`
+	Contributions    []ExportContribution    `json:"contributions"`
+	Payouts          []ExportPayout          `json:"payouts"`
+	ReputationEvents []ExportReputationEvent `json:"reputation_events"`
+}
+
+// ProjectImportResult counts what an import restored. Payouts and reputation
+// events are records of money and score that were already handed out, so
+// they are counted but never replayed.
+type ProjectImportResult struct {
+	Tasks                int `json:"tasks"`
+	Claims               int `json:"claims"`
+	Contributions        int `json:"contributions"`
+	SkippedClaims        int `json:"skipped_claims"`
+	SkippedContributions int `json:"skipped_contributions"`
+	Payouts              int `json:"payouts_not_imported"`
+	ReputationEvents     int `json:"reputation_events_not_imported"`
+	// BountiesNotFunded counts imported tasks whose bounty was left at zero
+	// because nothing is escrowed for it on this server.
+	BountiesNotFunded int `json:"bounties_not_funded"`
+}
+
+type ExportProject struct {
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	ShortDesc  string    `json:"short_desc"`
+	RepoURL    string    `json:"repo_url"`
+	Tags       []string  `json:"tags"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewExportProject(p *Project) ExportProject {
+	return ExportProject{ID: p.ID, Title: p.Title, ShortDesc: p.ShortDesc, RepoURL: p.RepoURL, Tags: p.Tags, Visibility: p.Visibility, CreatedAt: p.CreatedAt}
+}
+
+type ExportTask struct {
+	ID                uint      `json:"id"`
+	Title             string    `json:"title"`
+	Description       string    `json:"description"`
+	DifficultyLevel   string    `json:"difficulty_level"`
+	EstimatedHours    int       `json:"estimated_hours"`
+	Tags              []string  `json:"tags"`
+	SkillsRequired    []string  `json:"skills_required"`
+	BountyAmount      float64   `json:"bounty_amount"`
+	Status            string    `json:"status"`
+	ClaimPolicy       string    `json:"claim_policy"`
+	MaxClaimants      int       `json:"max_claimants"`
+	MilestoneID       *uint     `json:"milestone_id,omitempty"`
+	GitHubIssueNumber *int      `json:"github_issue_number,omitempty"`
+	GitHubIssueURL    string    `json:"github_issue_url,omitempty"`
+	ManifestKey       *string   `json:"manifest_key,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+var ExportTaskColumns = []string{"id", "title", "description", "difficulty_level", "estimated_hours", "tags", "skills_required", "bounty_amount", "status", "claim_policy", "max_claimants", "milestone_id", "github_issue_number", "github_issue_url", "manifest_key", "created_at", "updated_at"}
+
+func NewExportTask(t *Task) ExportTask {
+	return ExportTask{
+		ID:                t.ID,
+		Title:             t.Title,
+		Description:       t.Description,
+		DifficultyLevel:   t.DifficultyLevel,
+		EstimatedHours:    t.EstimatedHours,
+		Tags:              t.Tags,
+		SkillsRequired:    t.SkillsRequired,
+		BountyAmount:      t.BountyAmount,
+		Status:            t.Status,
+		ClaimPolicy:       t.ClaimPolicy,
+		MaxClaimants:      t.MaxClaimants,
+		MilestoneID:       t.MilestoneID,
+		GitHubIssueNumber: t.GitHubIssueNumber,
+		GitHubIssueURL:    t.GitHubIssueURL,
+		ManifestKey:       t.ManifestKey,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+	}
+}
+
+func (t *ExportTask) CSVRow() []string {
+	return []string{csvUint(t.ID), t.Title, t.Description, t.DifficultyLevel, strconv.Itoa(t.EstimatedHours),
+		csvList(t.Tags), csvList(t.SkillsRequired), csvMoney(t.BountyAmount), t.Status, t.ClaimPolicy,
+		strconv.Itoa(t.MaxClaimants), csvUintPtr(t.MilestoneID), csvIntPtr(t.GitHubIssueNumber), t.GitHubIssueURL,
+		csvStringPtr(t.ManifestKey), csvTime(&t.CreatedAt), csvTime(&t.UpdatedAt)}
+}
+
+type ExportClaim struct {
+	ID            uint       `json:"id"`
+	TaskID        uint       `json:"task_id"`
+	UserID        uint       `json:"user_id"`
+	Status        string     `json:"status"`
+	ClaimDate     time.Time  `json:"claim_date"`
+	MentorID      *uint      `json:"mentor_id,omitempty"`
+	Notes         string     `json:"notes"`
+	ReviewedByID  *uint      `json:"reviewed_by_id,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote    string     `json:"review_note,omitempty"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	ReleaseReason string     `json:"release_reason,omitempty"`
+	EndorsedAt    *time.Time `json:"endorsed_at,omitempty"`
+}
+
+var ExportClaimColumns = []string{"id", "task_id", "user_id", "status", "claim_date", "mentor_id", "notes", "reviewed_by_id", "reviewed_at", "review_note", "released_at", "release_reason", "endorsed_at"}
+
+func NewExportClaim(c *Claim) ExportClaim {
+	return ExportClaim{
+		ID:            c.ID,
+		TaskID:        c.TaskID,
+		UserID:        c.UserID,
+		Status:        c.Status,
+		ClaimDate:     c.ClaimDate,
+		MentorID:      c.MentorID,
+		Notes:         c.Notes,
+		ReviewedByID:  c.ReviewedByID,
+		ReviewedAt:    c.ReviewedAt,
+		ReviewNote:    c.ReviewNote,
+		ReleasedAt:    c.ReleasedAt,
+		ReleaseReason: c.ReleaseReason,
+		EndorsedAt:    c.EndorsedAt,
+	}
+}
+
+func (c *ExportClaim) CSVRow() []string {
+	return []string{csvUint(c.ID), csvUint(c.TaskID), csvUint(c.UserID), c.Status, csvTime(&c.ClaimDate),
+		csvUintPtr(c.MentorID), c.Notes, csvUintPtr(c.ReviewedByID), csvTime(c.ReviewedAt), c.ReviewNote,
+		csvTime(c.ReleasedAt), c.ReleaseReason, csvTime(c.EndorsedAt)}
+}
+
+type ExportContribution struct {
+	ID                 uint       `json:"id"`
+	TaskID             uint       `json:"task_id"`
+	UserID             uint       `json:"user_id"`
+	PRURL              string     `json:"pr_url"`
+	PRCommitHashes     []string   `json:"pr_commit_hashes"`
+	SubmittedAt        time.Time  `json:"submitted_at"`
+	VerificationStatus string     `json:"verification_status"`
+	AcceptedAt         *time.Time `json:"accepted_at,omitempty"`
+	PayoutAmount       float64    `json:"payout_amount"`
+	PaymentID          *uint      `json:"payment_id,omitempty"`
+}
+
+var ExportContributionColumns = []string{"id", "task_id", "user_id", "pr_url", "pr_commit_hashes", "submitted_at", "verification_status", "accepted_at", "payout_amount", "payment_id"}
+
+func NewExportContribution(c *Contribution) ExportContribution {
+	return ExportContribution{
+		ID:                 c.ID,
+		TaskID:             c.TaskID,
+		UserID:             c.UserID,
+		PRURL:              c.PRURL,
+		PRCommitHashes:     c.PRCommitHashes,
+		SubmittedAt:        c.SubmittedAt,
+		VerificationStatus: c.VerificationStatus,
+		AcceptedAt:         c.AcceptedAt,
+		PayoutAmount:       c.PayoutAmount,
+		PaymentID:          c.PaymentID,
+	}
+}
+
+func (c *ExportContribution) CSVRow() []string {
+	return []string{csvUint(c.ID), csvUint(c.TaskID), csvUint(c.UserID), c.PRURL, csvList(c.PRCommitHashes),
+		csvTime(&c.SubmittedAt), c.VerificationStatus, csvTime(c.AcceptedAt), csvMoney(c.PayoutAmount), csvUintPtr(c.PaymentID)}
+}
+
+type ExportPayout struct {
+	ID             uint      `json:"id"`
+	ContributionID *uint     `json:"contribution_id,omitempty"`
+	UserID         uint      `json:"user_id"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	Type           string    `json:"type"`
+	TransactionID  string    `json:"transaction_id"`
+	PaymentDate    time.Time `json:"payment_date"`
+}
+
+var ExportPayoutColumns = []string{"id", "contribution_id", "user_id", "amount", "currency", "status", "type", "transaction_id", "payment_date"}
+
+func NewExportPayout(p *Payment) ExportPayout {
+	return ExportPayout{
+		ID:             p.ID,
+		ContributionID: p.ContributionID,
+		UserID:         p.UserID,
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		Status:         p.Status,
+		Type:           p.Type,
+		TransactionID:  p.TransactionID,
+		PaymentDate:    p.PaymentDate,
+	}
+}
+
+func (p *ExportPayout) CSVRow() []string {
+	return []string{csvUint(p.ID), csvUintPtr(p.ContributionID), csvUint(p.UserID), csvMoney(p.Amount), p.Currency,
+		p.Status, p.Type, p.TransactionID, csvTime(&p.PaymentDate)}
+}
+
+type ExportReputationEvent struct {
+	ID          uint      `json:"id"`
+	UserID      uint      `json:"user_id"`
+	EventType   string    `json:"event_type"`
+	ScoreChange int       `json:"score_change"`
+	RelatedID   *uint     `json:"related_id,omitempty"`
+	Notes       string    `json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+var ExportReputationEventColumns = []string{"id", "user_id", "event_type", "score_change", "related_id", "notes", "created_at"}
+
+func NewExportReputationEvent(e *ReputationEventLog) ExportReputationEvent {
+	return ExportReputationEvent{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		EventType:   e.EventType,
+		ScoreChange: e.ScoreChange,
+		RelatedID:   e.RelatedID,
+		Notes:       e.Notes,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+func (e *ExportReputationEvent) CSVRow() []string {
+	return []string{csvUint(e.ID), csvUint(e.UserID), e.EventType, strconv.Itoa(e.ScoreChange), csvUintPtr(e.RelatedID),
+		e.Notes, csvTime(&e.CreatedAt)}
+}
+
+// CSV cells: empty for nil, RFC 3339 times, and "; " between list items.
+
+func csvUint(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func csvUintPtr(v *uint) string {
+	if v == nil {
+		return ""
+	}
+	return csvUint(*v)
+}
+
+func csvIntPtr(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func csvStringPtr(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func csvMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func csvList(items []string) string {
+	return strings.Join(items, "; ")
+}
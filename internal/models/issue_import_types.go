@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// IssueImportResult reports what an import of GitHub issues changed.
+// Unchanged counts issues whose task was already up to date; Skipped counts
+// pull requests and issues whose task has been deleted.
+type IssueImportResult struct {
+	Created   []Task `json:"created"`
+	Updated   []Task `json:"updated"`
+	Unchanged int    `json:"unchanged"`
+	Skipped   int    `json:"skipped"`
+}
+
+// difficultyLabels maps common issue labels to task difficulties. Labels are
+// compared lower-cased, after stripping a "difficulty:" style prefix.
+var difficultyLabels = map[string]string{
+	"good first issue": "easy",
+	"beginner":         "easy",
+	"starter":          "easy",
+	"easy":             "easy",
+	"intermediate":     "medium",
+	"medium":           "medium",
+	"advanced":         "hard",
+	"expert":           "hard",
+	"hard":             "hard",
+}
+
+// DifficultyFromLabels picks the task difficulty the issue labels point to,
+// the hardest one if they disagree. It returns "" when no label names a
+// difficulty.
+func DifficultyFromLabels(labels []string) string {
+	difficulty := ""
+	for _, label := range labels {
+		name := strings.ToLower(strings.TrimSpace(label))
+		for _, prefix := range []string{"difficulty", "level"} {
+			if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" && strings.ContainsRune(":/-_ ", rune(rest[0])) {
+				name = strings.TrimSpace(rest[1:])
+				break
+			}
+		}
+		if d, ok := difficultyLabels[name]; ok && difficultyRanks[d] > difficultyRanks[difficulty] {
+			difficulty = d
+		}
+	}
+	return difficulty
+}
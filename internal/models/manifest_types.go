@@ -0,0 +1,226 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ManifestFileName is the file, at the root of a project's repository, that
+// declares its tasks.
+const ManifestFileName = ".ossyne.yml"
+
+// Actions of a manifest plan.
+const (
+	ManifestActionCreate  = "create"
+	ManifestActionUpdate  = "update"
+	ManifestActionReopen  = "reopen"
+	ManifestActionArchive = "archive"
+)
+
+// manifestKeyPattern keeps task keys readable and safe in URLs and diffs.
+var manifestKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// ProjectManifest is the content of a project's .ossyne.yml: the project's
+// metadata and the full list of tasks it should have. Tasks are matched to
+// the server's by their key.
+type ProjectManifest struct {
+	Project ManifestProject `json:"project" yaml:"project"`
+	Tasks   []ManifestTask  `json:"tasks" yaml:"tasks"`
+}
+
+// ManifestProject holds the project metadata. ID names the project the file
+// belongs to; the other fields are only managed when present.
+type ManifestProject struct {
+	ID         uint      `json:"id,omitempty" yaml:"id,omitempty"`
+	Title      *string   `json:"title,omitempty" yaml:"title,omitempty"`
+	ShortDesc  *string   `json:"short_desc,omitempty" yaml:"short_desc,omitempty"`
+	RepoURL    *string   `json:"repo_url,omitempty" yaml:"repo_url,omitempty"`
+	Tags       *[]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Visibility *string   `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+}
+
+// ManifestTask declares one task. Unlike the project fields, every field is
+// managed: leaving one out resets it to its default. ID adopts an existing
+// task that was created before the project used a manifest.
+type ManifestTask struct {
+	Key            string   `json:"key" yaml:"key"`
+	ID             uint     `json:"id,omitempty" yaml:"id,omitempty"`
+	Title          string   `json:"title" yaml:"title"`
+	Description    string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Difficulty     string   `json:"difficulty,omitempty" yaml:"difficulty,omitempty"`
+	EstimatedHours int      `json:"estimated_hours,omitempty" yaml:"estimated_hours,omitempty"`
+	Tags           []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Skills         []string `json:"skills,omitempty" yaml:"skills,omitempty"`
+	Bounty         float64  `json:"bounty,omitempty" yaml:"bounty,omitempty"`
+	ClaimPolicy    string   `json:"claim_policy,omitempty" yaml:"claim_policy,omitempty"`
+	MaxClaimants   int      `json:"max_claimants,omitempty" yaml:"max_claimants,omitempty"`
+}
+
+// Normalize fills in the defaults of the fields left out.
+func (t *ManifestTask) Normalize() {
+	if t.Difficulty == "" {
+		t.Difficulty = "easy"
+	}
+	if t.ClaimPolicy == "" {
+		t.ClaimPolicy = ClaimPolicyExclusive
+	}
+	if t.ClaimPolicy != ClaimPolicyLimited {
+		t.MaxClaimants = 0
+	}
+	if t.Tags == nil {
+		t.Tags = []string{}
+	}
+	if t.Skills == nil {
+		t.Skills = []string{}
+	}
+}
+
+// Validate normalizes the manifest and reports the first problem in it.
+func (m *ProjectManifest) Validate() error {
+	if p := m.Project; p.Title != nil && *p.Title == "" {
+		return fmt.Errorf("project title cannot be empty")
+	}
+	if v := m.Project.Visibility; v != nil && *v != "public" && *v != "private" {
+		return fmt.Errorf("project visibility must be public or private, got %q", *v)
+	}
+	keys := map[string]bool{}
+	ids := map[uint]bool{}
+	for i := range m.Tasks {
+		t := &m.Tasks[i]
+		t.Normalize()
+		if !manifestKeyPattern.MatchString(t.Key) {
+			return fmt.Errorf("task %d: key %q must be lower-case letters, digits, '.', '_' or '-', up to 100 characters", i+1, t.Key)
+		}
+		if keys[t.Key] {
+			return fmt.Errorf("task %q is declared twice", t.Key)
+		}
+		keys[t.Key] = true
+		if t.ID != 0 {
+			if ids[t.ID] {
+				return fmt.Errorf("task %q: task id %d is adopted twice", t.Key, t.ID)
+			}
+			ids[t.ID] = true
+		}
+		if strings.TrimSpace(t.Title) == "" {
+			return fmt.Errorf("task %q: title is required", t.Key)
+		}
+		if _, ok := difficultyRanks[t.Difficulty]; !ok {
+			return fmt.Errorf("task %q: difficulty must be easy, medium or hard, got %q", t.Key, t.Difficulty)
+		}
+		if t.EstimatedHours < 0 {
+			return fmt.Errorf("task %q: estimated_hours cannot be negative", t.Key)
+		}
+		if t.Bounty < 0 {
+			return fmt.Errorf("task %q: bounty cannot be negative", t.Key)
+		}
+		if !IsValidClaimPolicy(t.ClaimPolicy) {
+			return fmt.Errorf("task %q: invalid claim_policy %q", t.Key, t.ClaimPolicy)
+		}
+		if t.ClaimPolicy == ClaimPolicyLimited && t.MaxClaimants < 1 {
+			return fmt.Errorf("task %q: max_claimants must be at least 1 for the limited claim policy", t.Key)
+		}
+	}
+	return nil
+}
+
+// FieldChange is one field a plan changes, with both values formatted for
+// display.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ManifestChange is what a plan does to one task. Conflict explains why a
+// change cannot be applied; a plan with conflicts is refused as a whole.
+type ManifestChange struct {
+	Action   string        `json:"action"`
+	Key      string        `json:"key"`
+	TaskID   uint          `json:"task_id,omitempty"`
+	Title    string        `json:"title"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+	Conflict string        `json:"conflict,omitempty"`
+}
+
+// ManifestPlan lists the changes that bring a project in line with its
+// manifest.
+type ManifestPlan struct {
+	ProjectID uint             `json:"project_id"`
+	Project   []FieldChange    `json:"project,omitempty"`
+	Tasks     []ManifestChange `json:"tasks"`
+	// Unmanaged counts the project's tasks that have no manifest key and are
+	// left alone.
+	Unmanaged int `json:"unmanaged"`
+}
+
+// Empty reports whether the project already matches its manifest.
+func (p *ManifestPlan) Empty() bool {
+	return len(p.Project) == 0 && len(p.Tasks) == 0
+}
+
+// Conflicts returns the changes that cannot be applied.
+func (p *ManifestPlan) Conflicts() []ManifestChange {
+	var conflicts []ManifestChange
+	for _, change := range p.Tasks {
+		if change.Conflict != "" {
+			conflicts = append(conflicts, change)
+		}
+	}
+	return conflicts
+}
+
+// ProjectChanges lists the fields of project that differ from the manifest.
+func (m *ManifestProject) ProjectChanges(project *Project) []FieldChange {
+	var changes []FieldChange
+	addString := func(field, from string, to *string) {
+		if to != nil && from != *to {
+			changes = append(changes, FieldChange{Field: field, From: from, To: *to})
+		}
+	}
+	addString("title", project.Title, m.Title)
+	addString("short_desc", project.ShortDesc, m.ShortDesc)
+	addString("repo_url", project.RepoURL, m.RepoURL)
+	if m.Tags != nil && !slices.Equal([]string(project.Tags), *m.Tags) {
+		changes = append(changes, FieldChange{Field: "tags", From: strings.Join(project.Tags, ", "), To: strings.Join(*m.Tags, ", ")})
+	}
+	addString("visibility", project.Visibility, m.Visibility)
+	return changes
+}
+
+// TaskChanges lists the fields of task that differ from the manifest. task
+// is nil for a task still to be created, in which case every field that is
+// not at its zero value is listed.
+func (t *ManifestTask) TaskChanges(task *Task) []FieldChange {
+	if task == nil {
+		task = &Task{}
+	}
+	var changes []FieldChange
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("title", task.Title, t.Title)
+	add("description", task.Description, t.Description)
+	add("difficulty", task.DifficultyLevel, t.Difficulty)
+	add("estimated_hours", strconv.Itoa(task.EstimatedHours), strconv.Itoa(t.EstimatedHours))
+	add("tags", strings.Join(task.Tags, ", "), strings.Join(t.Tags, ", "))
+	add("skills", strings.Join(task.SkillsRequired, ", "), strings.Join(t.Skills, ", "))
+	add("claim_policy", task.ClaimPolicy, t.ClaimPolicy)
+	add("max_claimants", strconv.Itoa(task.MaxClaimants), strconv.Itoa(t.MaxClaimants))
+	add("bounty", fmt.Sprintf("%.2f", task.BountyAmount), fmt.Sprintf("%.2f", t.Bounty))
+	return changes
+}
+
+// HasField reports whether the change touches field.
+func (c *ManifestChange) HasField(field string) bool {
+	for _, f := range c.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
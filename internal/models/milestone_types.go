@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+const (
+	MilestoneKindMilestone = "milestone"
+	MilestoneKindEpic      = "epic"
+)
+
+func IsValidMilestoneKind(kind string) bool {
+	return kind == MilestoneKindMilestone || kind == MilestoneKindEpic
+}
+
+// taskProgressWeights says how far along a task in each status counts
+// towards its milestone. Archived tasks are left out entirely.
+var taskProgressWeights = map[string]float64{
+	TaskStatusOpen:       0,
+	TaskStatusClaimed:    0.25,
+	TaskStatusInProgress: 0.5,
+	TaskStatusSubmitted:  0.75,
+	TaskStatusCompleted:  1,
+}
+
+// MilestoneProgress summarises the tasks of a milestone. It is computed by
+// the server, never stored.
+type MilestoneProgress struct {
+	TotalTasks     int     `json:"total_tasks"`
+	CompletedTasks int     `json:"completed_tasks"`
+	Percent        float64 `json:"percent"`
+	BountyTotal    float64 `json:"bounty_total"`
+	weighted       float64
+}
+
+// AddTasks counts count tasks in status carrying bounty between them.
+func (p *MilestoneProgress) AddTasks(status string, count int, bounty float64) {
+	weight, ok := taskProgressWeights[status]
+	if !ok {
+		return
+	}
+	p.TotalTasks += count
+	if status == TaskStatusCompleted {
+		p.CompletedTasks += count
+	}
+	p.weighted += weight * float64(count)
+	p.BountyTotal += bounty
+	p.Percent = 0
+	if p.TotalTasks > 0 {
+		p.Percent = p.weighted / float64(p.TotalTasks) * 100
+	}
+}
+
+// IsOverdue reports whether an unfinished milestone has passed its due date.
+func (m *Milestone) IsOverdue(now time.Time) bool {
+	return m.DueDate != nil && m.DueDate.Before(now) &&
+		m.Progress.CompletedTasks < m.Progress.TotalTasks
+}
@@ -32,7 +32,7 @@ type Project struct {
 
 type Task struct {
 	gorm.Model
-	ProjectID       uint            `gorm:"not null" json:"project_id"`
+	ProjectID       uint            `gorm:"not null;uniqueIndex:idx_project_github_issue;uniqueIndex:idx_project_manifest_key" json:"project_id"`
 	Title           string          `gorm:"not null" json:"title"`
 	Description     string          `json:"description"`
 	DifficultyLevel string          `gorm:"type:enum('easy', 'medium', 'hard');default:'easy'" json:"difficulty_level"`
@@ -42,19 +42,36 @@ type Task struct {
 	BountyAmount    float64         `gorm:"type:decimal(10,2);default:0.00" json:"bounty_amount"`
 	BountyEscrowID  *string         `json:"bounty_escrow_id,omitempty"`
 	Status          string          `gorm:"type:enum('open', 'claimed', 'in_progress', 'submitted', 'completed', 'archived');default:'open'" json:"status"`
+	ClaimPolicy     string          `gorm:"type:enum('exclusive', 'competitive', 'limited');default:'exclusive';not null" json:"claim_policy"`
+	MaxClaimants    int             `gorm:"default:0" json:"max_claimants,omitempty"`
+	MilestoneID     *uint           `gorm:"index" json:"milestone_id,omitempty"`
+	// GitHubIssueNumber links a task imported from the project's repository
+	// to its issue, so that re-imports update it instead of adding a copy.
+	GitHubIssueNumber *int   `gorm:"column:github_issue_number;uniqueIndex:idx_project_github_issue" json:"github_issue_number,omitempty"`
+	GitHubIssueURL    string `gorm:"column:github_issue_url" json:"github_issue_url,omitempty"`
+	// ManifestKey names the task in the project's .ossyne.yml. Tasks without
+	// one are not managed by the manifest.
+	ManifestKey *string `gorm:"size:100;uniqueIndex:idx_project_manifest_key" json:"manifest_key,omitempty"`
+	BlockedBy   []uint  `gorm:"-" json:"blocked_by,omitempty"`
 }
 
 type Claim struct {
 	gorm.Model
-	TaskID    uint      `gorm:"not null;uniqueIndex:idx_task_user_claim" json:"task_id"`
-	UserID    uint      `gorm:"not null;uniqueIndex:idx_task_user_claim" json:"user_id"`
-	ClaimDate time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"claim_date"`
-	Status    string    `gorm:"type:enum('pending', 'accepted', 'rejected', 'withdrawn');default:'pending';not null" json:"status"`
-	MentorID  *uint     `json:"mentor_id,omitempty"`
-	Notes     string    `gorm:"type:text" json:"notes"`
-	Task      Task      `gorm:"foreignKey:TaskID"`
-	User      User      `gorm:"foreignKey:UserID"`
-	Mentor    User      `gorm:"foreignKey:MentorID"`
+	TaskID        uint       `gorm:"not null;index:idx_claims_task_user" json:"task_id"`
+	UserID        uint       `gorm:"not null;index:idx_claims_task_user" json:"user_id"`
+	ClaimDate     time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"claim_date"`
+	Status        string     `gorm:"type:enum('pending', 'accepted', 'rejected', 'withdrawn', 'expired');default:'pending';not null" json:"status"`
+	MentorID      *uint      `json:"mentor_id,omitempty"`
+	Notes         string     `gorm:"type:text" json:"notes"`
+	ReviewedByID  *uint      `json:"reviewed_by_id,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote    string     `gorm:"type:text" json:"review_note,omitempty"`
+	ReleasedAt    *time.Time `json:"released_at,omitempty"`
+	ReleaseReason string     `gorm:"type:text" json:"release_reason,omitempty"`
+	EndorsedAt    *time.Time `json:"endorsed_at,omitempty"`
+	Task          Task       `gorm:"foreignKey:TaskID"`
+	User          User       `gorm:"foreignKey:UserID"`
+	Mentor        User       `gorm:"foreignKey:MentorID"`
 }
 
 type Contribution struct {
@@ -109,4 +126,148 @@ type Payment struct {
 	TransactionID  string    `gorm:"unique" json:"transaction_id"`
 	PaymentGateway string    `json:"payment_gateway"`
 	PaymentDate    time.Time `json:"payment_date"`
-}
\ No newline at end of file
+}
+
+type OAuthState struct {
+	gorm.Model
+	State                 string    `gorm:"unique;not null" json:"-"`
+	CodeVerifier          string    `gorm:"not null" json:"-"`
+	ExpiresAt             time.Time `gorm:"not null" json:"expires_at"`
+	DeviceAuthorizationID *uint     `json:"device_authorization_id,omitempty"`
+	RedirectURI           string    `json:"redirect_uri"`
+	ClientState           string    `json:"-"`
+}
+
+type Session struct {
+	gorm.Model
+	UserID           uint       `gorm:"not null" json:"user_id"`
+	TokenHash        string     `gorm:"unique;not null" json:"-"`
+	RefreshTokenHash string     `gorm:"unique;not null" json:"-"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RefreshExpiresAt time.Time  `gorm:"not null" json:"refresh_expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	UserAgent        string     `json:"user_agent"`
+	Current          bool       `gorm:"-" json:"current"`
+	User             User       `gorm:"foreignKey:UserID" json:"-"`
+}
+
+type DeviceAuthorization struct {
+	gorm.Model
+	DeviceCodeHash string     `gorm:"unique;not null" json:"-"`
+	UserCode       string     `gorm:"unique;not null" json:"user_code"`
+	Status         string     `gorm:"type:enum('pending', 'approved', 'denied', 'consumed');default:'pending';not null" json:"status"`
+	UserID         *uint      `json:"user_id,omitempty"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	PollInterval   int        `gorm:"not null;default:5" json:"poll_interval"`
+	LastPolledAt   *time.Time `json:"last_polled_at,omitempty"`
+	// ClientUserAgent and ClientIP describe the device that asked to log in,
+	// so the user approving it can tell whether it is theirs.
+	ClientUserAgent string `json:"client_user_agent"`
+	ClientIP        string `json:"client_ip"`
+	// ConfirmTokenHash guards the approval form shown after the browser
+	// login; only that browser can approve or deny the device.
+	ConfirmTokenHash *string `json:"-"`
+}
+
+type RoleChangeLog struct {
+	gorm.Model
+	ActorID      *uint  `json:"actor_id,omitempty"`
+	TargetUserID uint   `gorm:"not null" json:"target_user_id"`
+	Role         string `gorm:"type:enum('admin', 'maintainer', 'contributor');not null" json:"role"`
+	Action       string `gorm:"type:enum('grant', 'revoke');not null" json:"action"`
+	Reason       string `gorm:"type:text" json:"reason"`
+	Actor        *User  `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	TargetUser   *User  `gorm:"foreignKey:TargetUserID" json:"target_user,omitempty"`
+}
+
+type ProjectMaintainer struct {
+	gorm.Model
+	ProjectID uint    `gorm:"not null;uniqueIndex:idx_project_maintainer" json:"project_id"`
+	UserID    uint    `gorm:"not null;uniqueIndex:idx_project_maintainer" json:"user_id"`
+	AddedByID uint    `gorm:"not null" json:"added_by_id"`
+	Project   Project `gorm:"foreignKey:ProjectID" json:"-"`
+	User      User    `gorm:"foreignKey:UserID" json:"user"`
+}
+
+type APIToken struct {
+	gorm.Model
+	UserID     uint            `gorm:"not null" json:"user_id"`
+	Name       string          `gorm:"not null" json:"name"`
+	TokenHash  string          `gorm:"unique;not null" json:"-"`
+	Scopes     JSONStringSlice `gorm:"type:json" json:"scopes"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time      `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"`
+	User       User            `gorm:"foreignKey:UserID" json:"-"`
+}
+
+type TaskStatusHistory struct {
+	gorm.Model
+	TaskID     uint   `gorm:"not null;index" json:"task_id"`
+	FromStatus string `gorm:"not null" json:"from_status"`
+	ToStatus   string `gorm:"not null" json:"to_status"`
+	ActorID    *uint  `json:"actor_id,omitempty"`
+	Reason     string `gorm:"type:text" json:"reason"`
+	Actor      *User  `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+func (TaskStatusHistory) TableName() string {
+	return "task_status_history"
+}
+
+type TaskDependency struct {
+	gorm.Model
+	TaskID      uint  `gorm:"not null;uniqueIndex:idx_task_dependency" json:"task_id"`
+	DependsOnID uint  `gorm:"not null;uniqueIndex:idx_task_dependency" json:"depends_on_id"`
+	AddedByID   *uint `json:"added_by_id,omitempty"`
+	Task        *Task `gorm:"foreignKey:TaskID" json:"-"`
+	DependsOn   *Task `gorm:"foreignKey:DependsOnID" json:"depends_on,omitempty"`
+}
+
+type Milestone struct {
+	gorm.Model
+	ProjectID   uint              `gorm:"not null;index" json:"project_id"`
+	Title       string            `gorm:"not null" json:"title"`
+	Description string            `gorm:"type:text" json:"description"`
+	Kind        string            `gorm:"type:enum('milestone', 'epic');default:'milestone';not null" json:"kind"`
+	DueDate     *time.Time        `json:"due_date,omitempty"`
+	Progress    MilestoneProgress `gorm:"-" json:"progress"`
+}
+
+// Comment is a markdown message in the discussion thread of either a task or a
+// contribution; exactly one of TaskID and ContributionID is set.
+type Comment struct {
+	gorm.Model
+	TaskID         *uint      `gorm:"index" json:"task_id,omitempty"`
+	ContributionID *uint      `gorm:"index" json:"contribution_id,omitempty"`
+	ParentID       *uint      `json:"parent_id,omitempty"`
+	AuthorID       uint       `gorm:"not null" json:"author_id"`
+	Body           string     `gorm:"type:text;not null" json:"body"`
+	EditedAt       *time.Time `json:"edited_at,omitempty"`
+	Author         User       `gorm:"foreignKey:AuthorID" json:"author"`
+	Mentions       []User     `gorm:"many2many:comment_mentions;" json:"mentions"`
+}
+
+// MentorVolunteer records a user offering to mentor whoever works on a task.
+// Maintainers pick claim mentors from these volunteers, or anyone else.
+type MentorVolunteer struct {
+	gorm.Model
+	TaskID uint   `gorm:"not null;uniqueIndex:idx_task_mentor_volunteer" json:"task_id"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_task_mentor_volunteer" json:"user_id"`
+	Note   string `gorm:"type:text" json:"note,omitempty"`
+	User   *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TimeEntry is effort a claimant logged against their claim. TaskID is copied
+// from the claim so totals per task need no join.
+type TimeEntry struct {
+	gorm.Model
+	ClaimID uint      `gorm:"not null;index" json:"claim_id"`
+	TaskID  uint      `gorm:"not null;index" json:"task_id"`
+	UserID  uint      `gorm:"not null;index" json:"user_id"`
+	Minutes int       `gorm:"not null" json:"minutes"`
+	SpentOn time.Time `gorm:"type:date;not null" json:"spent_on"`
+	Note    string    `gorm:"type:text" json:"note"`
+	User    *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
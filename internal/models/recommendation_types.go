@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Weights of the parts of a recommendation score. They add up to 100, so a
+// score reads as a percentage.
+const (
+	recommendSkillWeight      = 45
+	recommendDifficultyWeight = 20
+	recommendHistoryWeight    = 20
+	recommendBountyWeight     = 15
+)
+
+// skillLevelRanks and difficultyRanks put skill levels and task difficulties
+// on the same 1-3 scale.
+var skillLevelRanks = map[string]int{"beginner": 1, "intermediate": 2, "expert": 3}
+var difficultyRanks = map[string]int{"easy": 1, "medium": 2, "hard": 3}
+
+// TaskRecommendation is a task ranked for a user, with the reasons behind its
+// score.
+type TaskRecommendation struct {
+	Task          Task     `json:"task"`
+	Score         float64  `json:"score"`
+	MatchedSkills []string `json:"matched_skills,omitempty"`
+	Reasons       []string `json:"reasons"`
+}
+
+// RecommendationProfile is what the ranking knows about a user. Skill names
+// are lower-cased.
+type RecommendationProfile struct {
+	SkillLevels map[string]string
+	// ProjectContributions counts accepted contributions per project.
+	ProjectContributions map[uint]int
+	// PastSkills are the skills required by tasks the user had contributions
+	// accepted on.
+	PastSkills map[string]bool
+}
+
+// Score ranks task for the profile on four parts: how many of its required
+// skills the user has, how their level in those skills fits the difficulty,
+// whether they have delivered on the same project or skills before, and the
+// size of the bounty.
+func (p *RecommendationProfile) Score(task *Task) TaskRecommendation {
+	rec := TaskRecommendation{Task: *task}
+
+	// Skills. Tasks without requirements are open to anyone, so they get
+	// half marks.
+	skillFit := 0.5
+	levelSum, matched := 0, 0
+	for _, skill := range task.SkillsRequired {
+		if level, ok := p.SkillLevels[strings.ToLower(skill)]; ok {
+			matched++
+			levelSum += skillLevelRanks[level]
+			rec.MatchedSkills = append(rec.MatchedSkills, skill)
+		}
+	}
+	if len(task.SkillsRequired) > 0 {
+		skillFit = float64(matched) / float64(len(task.SkillsRequired))
+		if matched > 0 {
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("You have %d of %d required skills", matched, len(task.SkillsRequired)))
+		}
+	}
+
+	// Difficulty against the user's level in the matched skills, counting a
+	// user without any of them as a beginner.
+	level := 1.0
+	if matched > 0 {
+		level = float64(levelSum) / float64(matched)
+	}
+	difficulty, ok := difficultyRanks[task.DifficultyLevel]
+	if !ok {
+		difficulty = 1
+	}
+	difficultyFit := 1 - math.Abs(level-float64(difficulty))/2
+	if difficultyFit == 1 {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("A good fit for your level (%s)", task.DifficultyLevel))
+	}
+
+	// History.
+	historyFit := 0.0
+	if count := p.ProjectContributions[task.ProjectID]; count > 0 {
+		historyFit += 0.5 * math.Min(float64(count), 3) / 3
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("You have %d accepted contributions to this project", count))
+	}
+	if len(task.SkillsRequired) > 0 {
+		seen := 0
+		for _, skill := range task.SkillsRequired {
+			if p.PastSkills[strings.ToLower(skill)] {
+				seen++
+			}
+		}
+		if seen > 0 {
+			historyFit += 0.5 * float64(seen) / float64(len(task.SkillsRequired))
+			rec.Reasons = append(rec.Reasons, "You have delivered work with these skills before")
+		}
+	}
+
+	// Bounty, on a log scale that tops out at 1000.
+	bountyFit := math.Min(1, math.Log10(1+math.Max(task.BountyAmount, 0))/3)
+	if task.BountyAmount > 0 {
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("Pays a $%.2f bounty", task.BountyAmount))
+	}
+
+	score := recommendSkillWeight*skillFit +
+		recommendDifficultyWeight*difficultyFit +
+		recommendHistoryWeight*historyFit +
+		recommendBountyWeight*bountyFit
+	rec.Score = math.Round(score*10) / 10
+	return rec
+}
@@ -0,0 +1,29 @@
+package models
+
+const (
+	RoleAdmin       = "admin"
+	RoleMaintainer  = "maintainer"
+	RoleContributor = "contributor"
+)
+
+const (
+	RoleActionGrant  = "grant"
+	RoleActionRevoke = "revoke"
+)
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleMaintainer, RoleContributor:
+		return true
+	}
+	return false
+}
+
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,14 @@
+package models
+
+// IsBlocked reports whether the task still waits on prerequisites. BlockedBy
+// is filled in by the server when tasks are listed or claimed.
+func (t *Task) IsBlocked() bool {
+	return len(t.BlockedBy) > 0
+}
+
+// DependencyNode is one task in a dependency tree together with the tasks it
+// depends on.
+type DependencyNode struct {
+	Task      Task             `json:"task"`
+	DependsOn []DependencyNode `json:"depends_on"`
+}
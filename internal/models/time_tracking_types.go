@@ -0,0 +1,109 @@
+package models
+
+import "math"
+
+// MaxTimeEntryMinutes caps a single time entry; longer stretches of work are
+// logged day by day.
+const MaxTimeEntryMinutes = 24 * 60
+
+// UserTime is one user's logged time on a task.
+type UserTime struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Minutes  int    `json:"minutes"`
+}
+
+// TaskTime is the time logged on a task, in total and per user.
+type TaskTime struct {
+	TaskID         uint        `json:"task_id"`
+	EstimatedHours int         `json:"estimated_hours"`
+	Minutes        int         `json:"minutes"`
+	Users          []UserTime  `json:"users"`
+	Entries        []TimeEntry `json:"entries"`
+}
+
+// UserTaskTime is a user's logged time on one task.
+type UserTaskTime struct {
+	TaskID  uint   `json:"task_id"`
+	Title   string `json:"title"`
+	Minutes int    `json:"minutes"`
+}
+
+// UserTimeSummary is the time a user logged, in total and per task.
+type UserTimeSummary struct {
+	UserID  uint           `json:"user_id"`
+	Minutes int            `json:"minutes"`
+	Tasks   []UserTaskTime `json:"tasks"`
+}
+
+// EstimateReportRow compares a task's estimate with the time logged on it.
+// Variance and ratio are only set when the task has both.
+type EstimateReportRow struct {
+	TaskID         uint     `json:"task_id"`
+	Title          string   `json:"title"`
+	Status         string   `json:"status"`
+	EstimatedHours int      `json:"estimated_hours"`
+	ActualHours    float64  `json:"actual_hours"`
+	VarianceHours  *float64 `json:"variance_hours,omitempty"`
+	Ratio          *float64 `json:"ratio,omitempty"`
+}
+
+// EstimateReport compares estimated and actual effort across a project. The
+// totals only count tasks that have both an estimate and logged time, so
+// tasks nobody has worked on yet do not skew the ratio.
+type EstimateReport struct {
+	ProjectID      uint                `json:"project_id"`
+	Tasks          []EstimateReportRow `json:"tasks"`
+	EstimatedHours int                 `json:"estimated_hours"`
+	ActualHours    float64             `json:"actual_hours"`
+	Ratio          *float64            `json:"ratio,omitempty"`
+	// Unestimated counts tasks with logged time but no estimate.
+	Unestimated int `json:"unestimated"`
+}
+
+// NewEstimateReport builds the report from the project's tasks and the
+// minutes logged on each. Tasks with neither an estimate nor logged time are
+// left out.
+func NewEstimateReport(projectID uint, tasks []Task, minutes map[uint]int) EstimateReport {
+	report := EstimateReport{ProjectID: projectID, Tasks: []EstimateReportRow{}}
+	comparedMinutes := 0
+	for _, task := range tasks {
+		logged := minutes[task.ID]
+		if task.EstimatedHours == 0 && logged == 0 {
+			continue
+		}
+		row := EstimateReportRow{
+			TaskID:         task.ID,
+			Title:          task.Title,
+			Status:         task.Status,
+			EstimatedHours: task.EstimatedHours,
+			ActualHours:    minutesToHours(logged),
+		}
+		switch {
+		case task.EstimatedHours > 0 && logged > 0:
+			variance := minutesToHours(logged - task.EstimatedHours*60)
+			ratio := roundRatio(float64(logged) / float64(task.EstimatedHours*60))
+			row.VarianceHours, row.Ratio = &variance, &ratio
+			report.EstimatedHours += task.EstimatedHours
+			comparedMinutes += logged
+		case logged > 0:
+			report.Unestimated++
+		}
+		report.Tasks = append(report.Tasks, row)
+	}
+	report.ActualHours = minutesToHours(comparedMinutes)
+	if report.EstimatedHours > 0 {
+		ratio := roundRatio(float64(comparedMinutes) / float64(report.EstimatedHours*60))
+		report.Ratio = &ratio
+	}
+	return report
+}
+
+// minutesToHours converts to hours, rounded to two decimals.
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
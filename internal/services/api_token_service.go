@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+
+const APITokenPrefix = "osp_"
+
+var (
+	ErrInvalidAPIToken  = errors.New("invalid, expired or revoked API token")
+	ErrAPITokenNotFound = errors.New("API token not found")
+	ErrInvalidScope     = errors.New("invalid scope")
+)
+
+// CreatedAPIToken is returned once when a token is created; only its hash is
+// stored, so the plaintext cannot be shown again.
+type CreatedAPIToken struct {
+	models.APIToken
+	Token string `json:"token"`
+}
+
+// APITokenService manages personal access tokens for non-interactive clients
+// such as CI pipelines.
+type APITokenService struct{}
+
+func NewAPITokenService() *APITokenService {
+	return &APITokenService{}
+}
+
+// Create mints a token for userID. A zero ttl creates a token that never
+// expires.
+func (s *APITokenService) Create(userID uint, name string, scopes []string, ttl time.Duration) (*CreatedAPIToken, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token := APITokenPrefix + secret
+
+	record := models.APIToken{
+		UserID:    userID,
+		Name:      truncate(name, 255),
+		TokenHash: HashToken(token),
+		Scopes:    models.JSONStringSlice(scopes),
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		record.ExpiresAt = &expiresAt
+	}
+	if err := db.DB.Create(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API token: %w", err)
+	}
+	return &CreatedAPIToken{APIToken: record, Token: token}, nil
+}
+
+// Authenticate resolves a personal access token to its user.
+func (s *APITokenService) Authenticate(token string) (*models.User, *models.APIToken, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, nil, ErrInvalidAPIToken
+	}
+	var record models.APIToken
+	if err := db.DB.Preload("User").Where("token_hash = ?", HashToken(token)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIToken
+		}
+		return nil, nil, fmt.Errorf("failed to look up API token: %w", err)
+	}
+	now := time.Now()
+	if record.RevokedAt != nil || (record.ExpiresAt != nil && now.After(*record.ExpiresAt)) {
+		return nil, nil, ErrInvalidAPIToken
+	}
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) > lastUsedResolution {
+		db.DB.Model(&record).UpdateColumn("last_used_at", now)
+		record.LastUsedAt = &now
+	}
+	user := record.User
+	return &user, &record, nil
+}
+
+// List returns the user's tokens that have not been revoked, including
+// expired ones so they can be cleaned up.
+func (s *APITokenService) List(userID uint) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	if err := db.DB.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API tokens for user %d: %w", userID, err)
+	}
+	return tokens, nil
+}
+
+func (s *APITokenService) Revoke(userID, tokenID uint) error {
+	result := db.DB.Model(&models.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke API token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("API token %d: %w", tokenID, ErrAPITokenNotFound)
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"html"
+	"html/template"
 	"io"
 	"net/http"
 	"net/url"
@@ -10,16 +14,33 @@ import (
 	"ossyne/internal/db"
 	"ossyne/internal/models"
 	"strconv"
+	"strings"
+	"time"
 	"github.com/google/go-github/v66/github"
 	"golang.org/x/oauth2"
 	oauth2_github "golang.org/x/oauth2/github"
 )
 
+// deviceConfirmCookie holds the token of the device approval form.
+const deviceConfirmCookie = "osm_device_confirm"
+
+// maxClientStateLength matches the oauth_states.client_state column.
+const maxClientStateLength = 128
+
 type AuthService struct {
 	GitHubOAuthConfig *oauth2.Config
+	States            *OAuthStateService
+	Sessions          *SessionService
+	Devices           *DeviceAuthService
+	Roles             *RoleService
+	// GitHubAPIURL is the REST API root the logged-in user is read from.
+	// Empty means api.github.com.
+	GitHubAPIURL      string
+	bootstrapAdmins   []string
+	callbackAllowlist []string
 }
 
-func NewAuthService(cfg config.Config) *AuthService {
+func NewAuthService(cfg config.Config, sessions *SessionService, roles *RoleService) *AuthService {
 	return &AuthService{
 		GitHubOAuthConfig: &oauth2.Config{
 			ClientID:     cfg.GitHubClientID,
@@ -28,28 +49,97 @@ func NewAuthService(cfg config.Config) *AuthService {
 			Endpoint:     oauth2_github.Endpoint,
 			Scopes:       []string{"read:user", "user:email"},
 		},
+		States:       NewOAuthStateService(),
+		Sessions:     sessions,
+		Devices:      NewDeviceAuthService(sessions),
+		Roles:        roles,
+		GitHubAPIURL: cfg.GitHubAPIURL,
+
+		bootstrapAdmins:   cfg.BootstrapAdmins(),
+		callbackAllowlist: cfg.CallbackAllowlist(),
 	}
 }
 
+// HandleGitHubLogin starts a browser login. The optional redirect_uri query
+// parameter names the CLI callback that receives the session and must be on
+// the configured allowlist; it defaults to the first allowed URL. The optional
+// state parameter is passed back to that callback unchanged.
 func (s *AuthService) HandleGitHubLogin(w http.ResponseWriter, r *http.Request) {
-	state := "random-state-string"
-	url := s.GitHubOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	clientState := r.URL.Query().Get("state")
+	if len(clientState) > maxClientStateLength {
+		http.Error(w, "state is too long", http.StatusBadRequest)
+		return
+	}
+	redirectURI := r.URL.Query().Get("redirect_uri")
+	if redirectURI == "" {
+		redirectURI = s.callbackAllowlist[0]
+	} else if !s.isAllowedCallback(redirectURI) {
+		http.Error(w, "redirect_uri is not an allowed callback URL", http.StatusBadRequest)
+		return
+	}
+	s.redirectToGitHub(w, r, nil, redirectURI, clientState)
+}
+
+// HandleDeviceVerification is the page a user opens on any browser to approve
+// a device login. Without a user_code it renders a form asking for one.
+func (s *AuthService) HandleDeviceVerification(w http.ResponseWriter, r *http.Request) {
+	userCode := r.URL.Query().Get("user_code")
+	if userCode == "" {
+		writeDevicePage(w, "")
+		return
+	}
+	auth, err := s.Devices.FindPending(userCode)
+	if err != nil {
+		if errors.Is(err, ErrInvalidUserCode) {
+			writeDevicePage(w, "That code is invalid or has expired. Check your terminal and try again.")
+			return
+		}
+		http.Error(w, "Failed to verify code: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.redirectToGitHub(w, r, &auth.ID, "", "")
+}
+
+func (s *AuthService) redirectToGitHub(w http.ResponseWriter, r *http.Request, deviceAuthorizationID *uint, redirectURI, clientState string) {
+	if err := s.States.PruneExpired(); err != nil {
+		fmt.Printf("[WARNING]: Failed to prune expired OAuth states: %v\n", err)
+	}
+	state, err := s.States.Issue(deviceAuthorizationID, redirectURI, clientState)
+	if err != nil {
+		http.Error(w, "Failed to start login: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	url := s.GitHubOAuthConfig.AuthCodeURL(state.State, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(state.CodeVerifier))
 	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
 }
 
 func (s *AuthService) HandleGitHubCallback(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
-	state := r.URL.Query().Get("state")
-	if state != "random-state-string" {
-		http.Error(w, "Invalid state", http.StatusBadRequest)
+	state, err := s.States.Consume(r.URL.Query().Get("state"))
+	if err != nil {
+		if errors.Is(err, ErrInvalidOAuthState) {
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to verify state: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Check the CLI callback before anything is minted for it; the allowlist
+	// may have changed since the login started.
+	if state.DeviceAuthorizationID == nil && !s.isAllowedCallback(state.RedirectURI) {
+		http.Error(w, "Login callback is no longer allowed", http.StatusBadRequest)
 		return
 	}
-	token, err := s.GitHubOAuthConfig.Exchange(context.Background(), code)
+	token, err := s.GitHubOAuthConfig.Exchange(context.Background(), code, oauth2.VerifierOption(state.CodeVerifier))
 	if err != nil {
 		http.Error(w, "Failed to exchange code for token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	client := github.NewClient(s.GitHubOAuthConfig.Client(context.Background(), token))
+	client, err := withGitHubAPIURL(github.NewClient(s.GitHubOAuthConfig.Client(context.Background(), token)), s.GitHubAPIURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	githubUser, _, err := client.Users.Get(context.Background(), "")
 	if err != nil {
 		http.Error(w, "Failed to get user from GitHub: "+err.Error(), http.StatusInternalServerError)
@@ -60,10 +150,45 @@ func (s *AuthService) HandleGitHubCallback(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Failed to find or create user: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	cliRedirectURL := "http://localhost:9999/auth/cli/callback"
+	if state.DeviceAuthorizationID != nil {
+		confirmToken, auth, err := s.Devices.Prepare(*state.DeviceAuthorizationID, user.ID)
+		if err != nil {
+			if errors.Is(err, ErrInvalidUserCode) {
+				writeDevicePage(w, "That code is invalid or has expired. Check your terminal and try again.")
+				return
+			}
+			http.Error(w, "Failed to prepare device login: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// The form carries the token and so does a cookie only this browser
+		// holds, so another site cannot submit the approval on the user's behalf.
+		http.SetCookie(w, &http.Cookie{
+			Name:     deviceConfirmCookie,
+			Value:    confirmToken,
+			Path:     "/auth/device",
+			Expires:  auth.ExpiresAt,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+		writeDeviceConfirmPage(w, user, auth, confirmToken)
+		return
+	}
+
+	session, err := s.Sessions.Create(user.ID, r.UserAgent())
+	if err != nil {
+		http.Error(w, "Failed to create session: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cliRedirectURL := state.RedirectURI
 
 	params := url.Values{}
-	params.Add("token", token.AccessToken)
+	params.Add("token", session.AccessToken)
+	params.Add("refresh_token", session.RefreshToken)
+	params.Add("expires_at", session.ExpiresAt.Format(time.RFC3339))
+	if state.ClientState != "" {
+		params.Add("state", state.ClientState)
+	}
 	redirectURLWithToken := fmt.Sprintf("%s?%s", cliRedirectURL, params.Encode())
 	w.Header().Set("Content-Type", "text/html")
 	io.WriteString(w, fmt.Sprintf(`
@@ -81,10 +206,106 @@ func (s *AuthService) HandleGitHubCallback(w http.ResponseWriter, r *http.Reques
 				<p>Welcome, %s!</p>
 				<p>Your user ID is: %d</p>
 				<p>You can now close this window and return to your terminal.</p>
-				<p>Redirecting back to the CLI...</p>
+				<p>Redirecting back to the CLI... If nothing happens, <a href="%s">continue here</a>.</p>
 			</body>
 		</html>
-	`, redirectURLWithToken, user.Username, user.ID))
+	`, template.JSEscapeString(redirectURLWithToken), html.EscapeString(user.Username), user.ID, html.EscapeString(redirectURLWithToken)))
+}
+
+// HandleDeviceConfirm records the user's answer on the page shown after a
+// device login. It only accepts a POST whose form token matches the cookie
+// set with that page.
+func (s *AuthService) HandleDeviceConfirm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	formToken := r.PostForm.Get("csrf_token")
+	cookie, err := r.Cookie(deviceConfirmCookie)
+	if err != nil || formToken == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(formToken)) != 1 {
+		http.Error(w, "Invalid or missing confirmation token", http.StatusForbidden)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: deviceConfirmCookie, Value: "", Path: "/auth/device", MaxAge: -1, HttpOnly: true})
+
+	userCode := r.PostForm.Get("user_code")
+	approve := r.PostForm.Get("decision") == "approve"
+	if approve {
+		err = s.Devices.Approve(userCode, formToken)
+	} else {
+		err = s.Devices.Deny(userCode, formToken)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidUserCode) {
+			writeDevicePage(w, "That code is invalid or has expired. Check your terminal and try again.")
+			return
+		}
+		http.Error(w, "Failed to record your answer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	title, message := "Device Denied", "The device was not logged in. You can close this window."
+	if approve {
+		title, message = "Device Approved", "Your terminal will finish logging in shortly. You can close this window."
+	}
+	w.Header().Set("Content-Type", "text/html")
+	io.WriteString(w, fmt.Sprintf(`
+		<html>
+			<head><title>%s</title></head>
+			<body>
+				<h1>%s</h1>
+				<p>%s</p>
+			</body>
+		</html>
+	`, title, title, message))
+}
+
+// writeDeviceConfirmPage asks the logged-in user whether the device that
+// requested the code is theirs.
+func writeDeviceConfirmPage(w http.ResponseWriter, user *models.User, auth *models.DeviceAuthorization, confirmToken string) {
+	userCode := FormatUserCode(auth.UserCode)
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Cache-Control", "no-store")
+	io.WriteString(w, fmt.Sprintf(`
+		<html>
+			<head><title>Approve Device</title></head>
+			<body>
+				<h1>Log in a device as %s?</h1>
+				<p>Only approve if you started this login yourself and the code matches the one in your terminal.</p>
+				<p>Code: <strong>%s</strong></p>
+				<p>Requested by: %s from %s at %s</p>
+				<form method="POST" action="/auth/device/confirm">
+					<input type="hidden" name="user_code" value="%s">
+					<input type="hidden" name="csrf_token" value="%s">
+					<button type="submit" name="decision" value="approve">Approve</button>
+					<button type="submit" name="decision" value="deny">Deny</button>
+				</form>
+			</body>
+		</html>
+	`, html.EscapeString(user.Username), html.EscapeString(userCode), html.EscapeString(auth.ClientUserAgent),
+		html.EscapeString(auth.ClientIP), auth.CreatedAt.Format(time.RFC1123), html.EscapeString(userCode), html.EscapeString(confirmToken)))
+}
+
+func writeDevicePage(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "text/html")
+	io.WriteString(w, fmt.Sprintf(`
+		<html>
+			<head><title>Device Login</title></head>
+			<body>
+				<h1>Log in a device</h1>
+				<p>Enter the code shown in your terminal.</p>
+				<p>%s</p>
+				<form method="GET" action="/auth/device">
+					<input type="text" name="user_code" placeholder="XXXX-XXXX" autofocus>
+					<button type="submit">Continue with GitHub</button>
+				</form>
+			</body>
+		</html>
+	`, html.EscapeString(message)))
 }
 
 func (s *AuthService) findOrCreateUser(githubUser *github.User, accessToken string) (*models.User, error) {
@@ -116,11 +337,37 @@ func (s *AuthService) findOrCreateUser(githubUser *github.User, accessToken stri
 		if user.Email == "" {
 			user.Email = fmt.Sprintf("%s@github.placeholder.com", user.Username)
 		}
+		user.Roles = models.JSONStringSlice{models.RoleContributor}
 		if err := db.DB.Create(&user).Error; err != nil {
 			return nil, fmt.Errorf("failed to create user: %w", err)
 		}
 		fmt.Printf("Successfully created user with ID: %d\n", user.ID)
 	}
 
+	if s.isBootstrapAdmin(user.Username) && !user.HasRole(models.RoleAdmin) {
+		if err := s.Roles.Grant(nil, user.ID, models.RoleAdmin, "bootstrap admin from ADMIN_USERNAMES"); err != nil {
+			return nil, fmt.Errorf("failed to grant bootstrap admin role: %w", err)
+		}
+		user.Roles = append(user.Roles, models.RoleAdmin)
+	}
+
 	return &user, nil
-}
\ No newline at end of file
+}
+
+func (s *AuthService) isAllowedCallback(redirectURI string) bool {
+	for _, allowed := range s.callbackAllowlist {
+		if redirectURI == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AuthService) isBootstrapAdmin(username string) bool {
+	for _, name := range s.bootstrapAdmins {
+		if strings.EqualFold(name, username) {
+			return true
+		}
+	}
+	return false
+}
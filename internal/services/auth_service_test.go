@@ -0,0 +1,292 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"ossyne/internal/config"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"golang.org/x/oauth2"
+)
+
+func newTestAuthService() *AuthService {
+	return NewAuthService(config.Config{
+		GitHubClientID:     "client-id",
+		GitHubClientSecret: "client-secret",
+		GitHubRedirectURL:  "http://localhost:8080/auth/github/callback",
+	}, NewSessionService(), nil)
+}
+
+func TestOAuthStateConsume(t *testing.T) {
+	useTestDB(t)
+	states := NewOAuthStateService()
+
+	issued, err := states.Issue(nil, config.DefaultCLICallbackURLs[0], "client-state")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	expired, err := states.Issue(nil, config.DefaultCLICallbackURLs[0], "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if err := db.DB.Model(expired).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("failed to expire state: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		state   string
+		wantErr error
+	}{
+		{name: "issued state", state: issued.State},
+		{name: "replayed state", state: issued.State, wantErr: ErrInvalidOAuthState},
+		{name: "expired state", state: expired.State, wantErr: ErrInvalidOAuthState},
+		{name: "expired state is gone too", state: expired.State, wantErr: ErrInvalidOAuthState},
+		{name: "unknown state", state: "not-a-state", wantErr: ErrInvalidOAuthState},
+		{name: "empty state", state: "", wantErr: ErrInvalidOAuthState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := states.Consume(tt.state)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Consume() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if record.CodeVerifier != issued.CodeVerifier || record.ClientState != "client-state" {
+				t.Errorf("Consume() = %+v, want the issued record", record)
+			}
+		})
+	}
+}
+
+func TestHandleGitHubLogin(t *testing.T) {
+	useTestDB(t)
+	auth := newTestAuthService()
+
+	tests := []struct {
+		name            string
+		query           url.Values
+		wantStatus      int
+		wantRedirectURI string
+		wantClientState string
+	}{
+		{
+			name:            "default callback",
+			query:           url.Values{},
+			wantStatus:      http.StatusTemporaryRedirect,
+			wantRedirectURI: config.DefaultCLICallbackURLs[0],
+		},
+		{
+			name:            "allowed callback with client state",
+			query:           url.Values{"redirect_uri": {config.DefaultCLICallbackURLs[1]}, "state": {"abc123"}},
+			wantStatus:      http.StatusTemporaryRedirect,
+			wantRedirectURI: config.DefaultCLICallbackURLs[1],
+			wantClientState: "abc123",
+		},
+		{
+			name:       "callback not on the allowlist",
+			query:      url.Values{"redirect_uri": {"http://evil.example.com/auth/cli/callback"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "client state too long",
+			query:      url.Values{"state": {strings.Repeat("a", maxClientStateLength+1)}},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			auth.HandleGitHubLogin(rec, httptest.NewRequest(http.MethodGet, "/auth/github?"+tt.query.Encode(), nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusTemporaryRedirect {
+				return
+			}
+
+			location, err := url.Parse(rec.Header().Get("Location"))
+			if err != nil {
+				t.Fatalf("invalid redirect: %v", err)
+			}
+			params := location.Query()
+			var stored models.OAuthState
+			if err := db.DB.Where("state = ?", params.Get("state")).First(&stored).Error; err != nil {
+				t.Fatalf("state %q was not stored: %v", params.Get("state"), err)
+			}
+			sum := sha256.Sum256([]byte(stored.CodeVerifier))
+			if got, want := params.Get("code_challenge"), base64.RawURLEncoding.EncodeToString(sum[:]); got != want {
+				t.Errorf("code_challenge = %q, want S256 of the stored verifier %q", got, want)
+			}
+			if got := params.Get("code_challenge_method"); got != "S256" {
+				t.Errorf("code_challenge_method = %q, want S256", got)
+			}
+			if params.Get("state") == tt.wantClientState {
+				t.Errorf("the client state was sent to GitHub as the OAuth state")
+			}
+			if stored.RedirectURI != tt.wantRedirectURI || stored.ClientState != tt.wantClientState {
+				t.Errorf("stored redirect %q and client state %q, want %q and %q",
+					stored.RedirectURI, stored.ClientState, tt.wantRedirectURI, tt.wantClientState)
+			}
+		})
+	}
+}
+
+func TestHandleGitHubCallbackSendsVerifier(t *testing.T) {
+	useTestDB(t)
+	var gotVerifier string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		gotVerifier = r.PostForm.Get("code_verifier")
+		http.Error(w, `{"error":"bad_verification_code"}`, http.StatusBadRequest)
+	}))
+	defer github.Close()
+
+	auth := newTestAuthService()
+	auth.GitHubOAuthConfig.Endpoint = oauth2.Endpoint{AuthURL: github.URL + "/authorize", TokenURL: github.URL + "/token"}
+	state, err := auth.States.Issue(nil, config.DefaultCLICallbackURLs[0], "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		state        string
+		wantStatus   int
+		wantVerifier string
+	}{
+		{name: "issued state", state: state.State, wantStatus: http.StatusInternalServerError, wantVerifier: state.CodeVerifier},
+		{name: "replayed state", state: state.State, wantStatus: http.StatusBadRequest},
+		{name: "forged state", state: "forged", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVerifier = ""
+			rec := httptest.NewRecorder()
+			query := url.Values{"code": {"code"}, "state": {tt.state}}
+			auth.HandleGitHubCallback(rec, httptest.NewRequest(http.MethodGet, "/auth/github/callback?"+query.Encode(), nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if gotVerifier != tt.wantVerifier {
+				t.Errorf("code_verifier sent to GitHub = %q, want %q", gotVerifier, tt.wantVerifier)
+			}
+		})
+	}
+}
+
+func TestHandleGitHubCallback(t *testing.T) {
+	useTestDB(t)
+	var tokenRequests int
+	var gotVerifier string
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			r.ParseForm()
+			gotVerifier = r.PostForm.Get("code_verifier")
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"access_token":"gho_test","token_type":"bearer"}`)
+		case "/api/user":
+			if r.Header.Get("Authorization") != "Bearer gho_test" {
+				http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"id":4242,"login":"octocat"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer github.Close()
+
+	auth := newTestAuthService()
+	auth.GitHubOAuthConfig.Endpoint = oauth2.Endpoint{AuthURL: github.URL + "/authorize", TokenURL: github.URL + "/token"}
+	auth.GitHubAPIURL = github.URL + "/api"
+	callbackURL := config.DefaultCLICallbackURLs[1]
+	state, err := auth.States.Issue(nil, callbackURL, "client-state")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	// Stored before the allowlist was narrowed.
+	stale, err := auth.States.Issue(nil, "http://localhost:9999/old/callback", "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	callback := func(state string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		query := url.Values{"code": {"code"}, "state": {state}}
+		auth.HandleGitHubCallback(rec, httptest.NewRequest(http.MethodGet, "/auth/github/callback?"+query.Encode(), nil))
+		return rec
+	}
+
+	rec := callback(state.State)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if gotVerifier != state.CodeVerifier {
+		t.Errorf("code_verifier sent to GitHub = %q, want %q", gotVerifier, state.CodeVerifier)
+	}
+	link := regexp.MustCompile(`href="([^"]+)"`).FindStringSubmatch(rec.Body.String())
+	if link == nil {
+		t.Fatalf("no redirect link in the page: %s", rec.Body.String())
+	}
+	redirect, err := url.Parse(html.UnescapeString(link[1]))
+	if err != nil {
+		t.Fatalf("invalid redirect: %v", err)
+	}
+	params := redirect.Query()
+	redirect.RawQuery = ""
+	if redirect.String() != callbackURL {
+		t.Errorf("redirected to %s, want %s", redirect, callbackURL)
+	}
+	if got := params.Get("state"); got != "client-state" {
+		t.Errorf("client state = %q, want %q", got, "client-state")
+	}
+	user, _, err := auth.Sessions.Authenticate(params.Get("token"))
+	if err != nil {
+		t.Fatalf("the issued session does not authenticate: %v", err)
+	}
+	if user.Username != "octocat" {
+		t.Errorf("session belongs to %s, want octocat", user.Username)
+	}
+
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{name: "replayed state", state: state.State},
+		{name: "callback no longer allowed", state: stale.State},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tokenRequests
+			if rec := callback(tt.state); rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+			if tokenRequests != before {
+				t.Errorf("the code was exchanged with GitHub")
+			}
+		})
+	}
+	var sessions int64
+	if err := db.DB.Model(&models.Session{}).Count(&sessions).Error; err != nil {
+		t.Fatalf("failed to count sessions: %v", err)
+	}
+	if sessions != 1 {
+		t.Errorf("%d sessions were created, want 1", sessions)
+	}
+}
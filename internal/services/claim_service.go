@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"time"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrClaimNotFound     = errors.New("claim not found")
+	ErrClaimNotPending   = errors.New("claim is no longer pending")
+	ErrTaskNotOpen       = errors.New("task is not open for claims")
+	ErrAlreadyClaimed    = errors.New("user has already claimed this task")
+	ErrNoActiveClaim     = errors.New("user has no pending or accepted claim on this task")
+	ErrClaimLimitReached = errors.New("task already has as many accepted claims as its policy allows")
+	ErrWorkSubmitted     = errors.New("work has already been submitted for this task")
+)
+
+// ClaimService runs the claim approval workflow. Contributors request a task
+// with a pending claim; the task only locks once a maintainer accepts one, or
+// once its claim policy's limit of accepted claims is reached.
+type ClaimService struct {
+	TaskStates *TaskStateService
+}
+
+func NewClaimService(taskStates *TaskStateService) *ClaimService {
+	return &ClaimService{TaskStates: taskStates}
+}
+
+// Request records a pending claim by userID. Several users may request the
+// same task while it is open; tasks whose policy allows parallel work keep
+// taking requests until the limit of accepted claims is reached.
+func (s *ClaimService) Request(taskID, userID uint) (*models.Claim, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	if err := checkNotBlocked(db.DB, &task); err != nil {
+		return nil, err
+	}
+	if !task.AcceptsClaims() {
+		return nil, fmt.Errorf("%w: task '%s' is %s", ErrTaskNotOpen, task.Title, task.Status)
+	}
+	if limit := task.ClaimLimit(); limit > 0 && task.Status != models.TaskStatusOpen {
+		accepted, err := countAcceptedClaims(db.DB, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if accepted >= int64(limit) {
+			return nil, fmt.Errorf("%w: task '%s' allows %d", ErrClaimLimitReached, task.Title, limit)
+		}
+	}
+
+	// Earlier claims that were rejected, withdrawn or expired do not count; the
+	// user may try again.
+	var existing int64
+	if err := db.DB.Model(&models.Claim{}).
+		Where("task_id = ? AND user_id = ? AND status IN ?", taskID, userID, []string{models.ClaimStatusPending, models.ClaimStatusAccepted}).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing claims: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("%w: task '%s'", ErrAlreadyClaimed, task.Title)
+	}
+
+	claim := models.Claim{
+		TaskID: taskID,
+		UserID: userID,
+		Status: models.ClaimStatusPending,
+	}
+	if err := db.DB.Omit("Task", "User", "Mentor").Create(&claim).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: task '%s'", ErrAlreadyClaimed, task.Title)
+		}
+		return nil, fmt.Errorf("failed to create claim: %w", err)
+	}
+	return &claim, nil
+}
+
+// ListPending returns the pending claims on tasks of projects user maintains,
+// optionally narrowed to one task.
+func (s *ClaimService) ListPending(user *models.User, taskID uint) ([]models.Claim, error) {
+	query := db.DB.Preload("Task").Preload("User").Preload("Mentor").
+		Joins("JOIN tasks ON tasks.id = claims.task_id AND tasks.deleted_at IS NULL").
+		Joins("JOIN projects ON projects.id = tasks.project_id AND projects.deleted_at IS NULL").
+		Where("claims.status = ?", models.ClaimStatusPending)
+	maintained := db.DB.Model(&models.ProjectMaintainer{}).Select("project_id").Where("user_id = ?", user.ID)
+	query = query.Where("projects.owner_id = ? OR projects.id IN (?)", user.ID, maintained)
+	if taskID != 0 {
+		query = query.Where("claims.task_id = ?", taskID)
+	}
+
+	var claims []models.Claim
+	if err := query.Order("claims.claim_date, claims.id").Find(&claims).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pending claims: %w", err)
+	}
+	return claims, nil
+}
+
+// Accept approves a pending claim and marks an open task claimed. Claims on
+// blocked tasks cannot be accepted. Once the task's claim limit is reached
+// every other pending claim on it is rejected.
+func (s *ClaimService) Accept(claimID uint, actorID *uint, note string) (*models.Claim, error) {
+	var claim models.Claim
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.review(tx, &claim, claimID, models.ClaimStatusAccepted, actorID, note); err != nil {
+			return err
+		}
+
+		// Lock the task so concurrent accepts on it count each other's claims.
+		var task models.Task
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, claim.TaskID).Error; err != nil {
+			return fmt.Errorf("failed to fetch task %d: %w", claim.TaskID, err)
+		}
+		// A prerequisite may have been added since the claim was requested.
+		if err := checkNotBlocked(tx, &task); err != nil {
+			return err
+		}
+		// Exclusive tasks must still be open; parallel ones may already be claimed.
+		if task.Status == models.TaskStatusOpen || !task.AllowsParallelWork() {
+			reason := fmt.Sprintf("Claim %d by user %d accepted", claim.ID, claim.UserID)
+			if err := s.TaskStates.Transition(tx, &task, models.TaskStatusClaimed, actorID, reason); err != nil {
+				return err
+			}
+		} else if !task.AcceptsClaims() {
+			return fmt.Errorf("%w: task '%s' is %s", ErrTaskNotOpen, task.Title, task.Status)
+		}
+
+		limit := task.ClaimLimit()
+		if limit == 0 {
+			return nil
+		}
+		// A locking read sees claims accepted by transactions that held the
+		// task lock before this one, which a plain snapshot read may not.
+		accepted, err := countAcceptedClaims(tx.Clauses(clause.Locking{Strength: "UPDATE"}), task.ID)
+		if err != nil {
+			return err
+		}
+		if accepted > int64(limit) {
+			return fmt.Errorf("%w: task '%s' allows %d", ErrClaimLimitReached, task.Title, limit)
+		}
+		if accepted < int64(limit) {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Claim{}).
+			Where("task_id = ? AND id <> ? AND status = ?", claim.TaskID, claim.ID, models.ClaimStatusPending).
+			Updates(map[string]interface{}{
+				"status":         models.ClaimStatusRejected,
+				"reviewed_by_id": actorID,
+				"reviewed_at":    now,
+				"review_note":    fmt.Sprintf("Claim %d was accepted for this task", claim.ID),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to reject competing claims: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// Reject turns down a pending claim. The task stays open for other claims.
+func (s *ClaimService) Reject(claimID uint, actorID *uint, note string) (*models.Claim, error) {
+	var claim models.Claim
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		return s.review(tx, &claim, claimID, models.ClaimStatusRejected, actorID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// review moves a pending claim to status. The update is conditional on the
+// claim still being pending so two maintainers cannot both decide on it.
+func (s *ClaimService) review(tx *gorm.DB, claim *models.Claim, claimID uint, status string, actorID *uint, note string) error {
+	if err := tx.First(claim, claimID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("claim %d: %w", claimID, ErrClaimNotFound)
+		}
+		return fmt.Errorf("failed to fetch claim %d: %w", claimID, err)
+	}
+	if claim.Status != models.ClaimStatusPending {
+		return fmt.Errorf("%w: claim %d is %s", ErrClaimNotPending, claimID, claim.Status)
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.Claim{}).
+		Where("id = ? AND status = ?", claimID, models.ClaimStatusPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": actorID,
+			"reviewed_at":    now,
+			"review_note":    note,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update claim %d: %w", claimID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: claim %d", ErrClaimNotPending, claimID)
+	}
+	claim.Status = status
+	claim.ReviewedByID = actorID
+	claim.ReviewedAt = &now
+	claim.ReviewNote = note
+	return nil
+}
+
+// Withdraw releases userID's pending or accepted claim on a task. Withdrawing
+// an accepted claim reopens the task; once work has been submitted the claim
+// can no longer be withdrawn.
+func (s *ClaimService) Withdraw(taskID, userID uint, reason string) (*models.Claim, error) {
+	var claim models.Claim
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ? AND user_id = ? AND status IN ?", taskID, userID,
+			[]string{models.ClaimStatusPending, models.ClaimStatusAccepted}).First(&claim).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrNoActiveClaim)
+			}
+			return fmt.Errorf("failed to fetch claim on task %d: %w", taskID, err)
+		}
+		var task models.Task
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, taskID).Error; err != nil {
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		if task.Status == models.TaskStatusSubmitted || task.Status == models.TaskStatusCompleted {
+			return fmt.Errorf("%w: task '%s' is %s", ErrWorkSubmitted, task.Title, task.Status)
+		}
+		var pendingWork int64
+		if err := tx.Model(&models.Contribution{}).
+			Where("task_id = ? AND user_id = ? AND verification_status = ?", taskID, userID, models.VerificationStatusUnverified).
+			Count(&pendingWork).Error; err != nil {
+			return fmt.Errorf("failed to check contributions on task %d: %w", taskID, err)
+		}
+		if pendingWork > 0 {
+			return fmt.Errorf("%w: your contribution to task '%s' is awaiting review", ErrWorkSubmitted, task.Title)
+		}
+
+		wasAccepted := claim.Status == models.ClaimStatusAccepted
+		if reason == "" {
+			reason = "Withdrawn by contributor"
+		}
+		if err := s.release(tx, &claim, models.ClaimStatusWithdrawn, reason); err != nil {
+			return err
+		}
+		if !wasAccepted {
+			return nil
+		}
+		return s.reopenIfUnclaimed(tx, claim.TaskID, &userID, fmt.Sprintf("Claim %d withdrawn: %s", claim.ID, reason))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// Release lets a maintainer take a claimed or in-progress task back from its
+// claimants. Their accepted claims end as withdrawn, with the reason recorded,
+// and the task reopens. Pending claims stay open for review.
+func (s *ClaimService) Release(taskID uint, actorID *uint, reason string) (*models.Task, error) {
+	var task models.Task
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		if task.Status != models.TaskStatusClaimed && task.Status != models.TaskStatusInProgress {
+			return fmt.Errorf("%w: task %d is %s; only claimed or in-progress tasks can be released", ErrIllegalTaskTransition, task.ID, task.Status)
+		}
+		note := "Released by a maintainer"
+		if reason != "" {
+			note += ": " + reason
+		}
+		var accepted []models.Claim
+		if err := tx.Where("task_id = ? AND status = ?", task.ID, models.ClaimStatusAccepted).Find(&accepted).Error; err != nil {
+			return fmt.Errorf("failed to load claims on task %d: %w", task.ID, err)
+		}
+		for i := range accepted {
+			if err := s.release(tx, &accepted[i], models.ClaimStatusWithdrawn, note); err != nil {
+				return err
+			}
+		}
+		return s.TaskStates.Transition(tx, &task, models.TaskStatusOpen, actorID, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ExpireStale expires accepted claims whose holder has neither moved the task
+// nor submitted work for longer than deadline, and reopens tasks left without
+// claimants. It returns how many claims expired.
+func (s *ClaimService) ExpireStale(deadline time.Duration) (int, error) {
+	cutoff := time.Now().Add(-deadline)
+	lastActivity := db.DB.Model(&models.TaskStatusHistory{}).
+		Select("MAX(created_at)").
+		Where("task_status_history.task_id = claims.task_id AND task_status_history.actor_id = claims.user_id")
+	pendingWork := db.DB.Model(&models.Contribution{}).
+		Select("1").
+		Where("contributions.task_id = claims.task_id AND contributions.user_id = claims.user_id AND contributions.verification_status = ?", models.VerificationStatusUnverified)
+
+	var stale []models.Claim
+	if err := db.DB.
+		Joins("JOIN tasks ON tasks.id = claims.task_id AND tasks.deleted_at IS NULL").
+		Where("claims.status = ? AND tasks.status IN ?", models.ClaimStatusAccepted,
+			[]string{models.TaskStatusClaimed, models.TaskStatusInProgress, models.TaskStatusSubmitted}).
+		Where("GREATEST(COALESCE((?), claims.claim_date), COALESCE(claims.reviewed_at, claims.claim_date)) < ?", lastActivity, cutoff).
+		Where("NOT EXISTS (?)", pendingWork).
+		Find(&stale).Error; err != nil {
+		return 0, fmt.Errorf("failed to find stale claims: %w", err)
+	}
+
+	expired := 0
+	var errs []error
+	reason := fmt.Sprintf("No activity for %s", deadline)
+	for i := range stale {
+		claim := &stale[i]
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := s.release(tx, claim, models.ClaimStatusExpired, reason); err != nil {
+				return err
+			}
+			return s.reopenIfUnclaimed(tx, claim.TaskID, nil, fmt.Sprintf("Claim %d expired: %s", claim.ID, reason))
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("claim %d: %w", claim.ID, err))
+			continue
+		}
+		expired++
+	}
+	return expired, errors.Join(errs...)
+}
+
+// StartExpiry runs ExpireStale every interval until ctx is cancelled.
+func (s *ClaimService) StartExpiry(ctx context.Context, deadline, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			expired, err := s.ExpireStale(deadline)
+			if err != nil {
+				fmt.Printf("[ERROR]: Claim expiry: %v\n", err)
+			}
+			if expired > 0 {
+				fmt.Printf("Expired %d stale claims and reopened their tasks.\n", expired)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// release ends an active claim with status and records why.
+func (s *ClaimService) release(tx *gorm.DB, claim *models.Claim, status, reason string) error {
+	now := time.Now()
+	result := tx.Model(&models.Claim{}).
+		Where("id = ? AND status = ?", claim.ID, claim.Status).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"released_at":    now,
+			"release_reason": reason,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update claim %d: %w", claim.ID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: claim %d changed concurrently", ErrNoActiveClaim, claim.ID)
+	}
+	claim.Status = status
+	claim.ReleasedAt = &now
+	claim.ReleaseReason = reason
+	return nil
+}
+
+// reopenIfUnclaimed puts a claimed or in-progress task back on the board once
+// its last accepted claim is gone. Tasks other claimants are still working on
+// stay as they are, and so do tasks that already have work submitted.
+func (s *ClaimService) reopenIfUnclaimed(tx *gorm.DB, taskID uint, actorID *uint, reason string) error {
+	remaining, err := countAcceptedClaims(tx, taskID)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return nil
+	}
+	var task models.Task
+	if err := tx.First(&task, taskID).Error; err != nil {
+		return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	if task.Status != models.TaskStatusClaimed && task.Status != models.TaskStatusInProgress {
+		return nil
+	}
+	return s.TaskStates.Transition(tx, &task, models.TaskStatusOpen, actorID, reason)
+}
+
+func countAcceptedClaims(tx *gorm.DB, taskID uint) (int64, error) {
+	var count int64
+	if err := tx.Model(&models.Claim{}).
+		Where("task_id = ? AND status = ?", taskID, models.ClaimStatusAccepted).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count accepted claims on task %d: %w", taskID, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"testing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimFixture is a task of a project owned by owner, with an accepted claim
+// by contributor.
+type claimFixture struct {
+	owner, contributor *models.User
+	project            models.Project
+	task               models.Task
+	claim              models.Claim
+}
+
+func newClaimFixture(t *testing.T, taskStatus, claimPolicy string, maxClaimants int) *claimFixture {
+	t.Helper()
+	f := &claimFixture{
+		owner:       createTestUser(t, "owner"),
+		contributor: createTestUser(t, "contributor"),
+	}
+	f.project = models.Project{OwnerID: f.owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	mustCreate(t, &f.project)
+	f.task = models.Task{ProjectID: f.project.ID, Title: "Task", Status: taskStatus, ClaimPolicy: claimPolicy, MaxClaimants: maxClaimants}
+	mustCreate(t, &f.task)
+	if taskStatus != models.TaskStatusOpen {
+		f.claim = models.Claim{TaskID: f.task.ID, UserID: f.contributor.ID, Status: models.ClaimStatusAccepted}
+		mustCreate(t, &f.claim)
+	}
+	return f
+}
+
+func TestClaimWithdraw(t *testing.T) {
+	tests := []struct {
+		name         string
+		taskStatus   string
+		contribution string
+		wantErr      error
+		wantTask     string
+	}{
+		{name: "claimed task", taskStatus: models.TaskStatusClaimed, wantTask: models.TaskStatusOpen},
+		{name: "task in progress", taskStatus: models.TaskStatusInProgress, wantTask: models.TaskStatusOpen},
+		{
+			name: "earlier contribution was rejected", taskStatus: models.TaskStatusInProgress,
+			contribution: models.VerificationStatusRejected, wantTask: models.TaskStatusOpen,
+		},
+		{
+			name: "work submitted", taskStatus: models.TaskStatusSubmitted,
+			contribution: models.VerificationStatusUnverified, wantErr: ErrWorkSubmitted, wantTask: models.TaskStatusSubmitted,
+		},
+		{
+			name: "contribution awaiting review", taskStatus: models.TaskStatusInProgress,
+			contribution: models.VerificationStatusUnverified, wantErr: ErrWorkSubmitted, wantTask: models.TaskStatusInProgress,
+		},
+		{
+			name: "task completed", taskStatus: models.TaskStatusCompleted,
+			contribution: models.VerificationStatusManualVerified, wantErr: ErrWorkSubmitted, wantTask: models.TaskStatusCompleted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestDB(t)
+			f := newClaimFixture(t, tt.taskStatus, models.ClaimPolicyExclusive, 0)
+			if tt.contribution != "" {
+				mustCreate(t, &models.Contribution{
+					TaskID: f.task.ID, UserID: f.contributor.ID,
+					PRURL: "https://github.com/ossyne/project/pull/1", VerificationStatus: tt.contribution,
+				})
+			}
+
+			claims := NewClaimService(NewTaskStateService())
+			_, err := claims.Withdraw(f.task.ID, f.contributor.ID, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Withdraw() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var claim models.Claim
+			var task models.Task
+			if err := db.DB.First(&claim, f.claim.ID).Error; err != nil {
+				t.Fatalf("failed to reload claim: %v", err)
+			}
+			if err := db.DB.First(&task, f.task.ID).Error; err != nil {
+				t.Fatalf("failed to reload task: %v", err)
+			}
+			wantClaim := models.ClaimStatusWithdrawn
+			if tt.wantErr != nil {
+				wantClaim = models.ClaimStatusAccepted
+			}
+			if claim.Status != wantClaim || task.Status != tt.wantTask {
+				t.Errorf("claim %s and task %s, want claim %s and task %s", claim.Status, task.Status, wantClaim, tt.wantTask)
+			}
+		})
+	}
+}
+
+func TestClaimRequestAgain(t *testing.T) {
+	useTestDB(t)
+	f := newClaimFixture(t, models.TaskStatusOpen, models.ClaimPolicyExclusive, 0)
+	claims := NewClaimService(NewTaskStateService())
+
+	var claimID uint
+	request := func() error {
+		claim, err := claims.Request(f.task.ID, f.contributor.ID)
+		if err == nil {
+			claimID = claim.ID
+		}
+		return err
+	}
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "first claim", run: request},
+		{name: "second claim while pending", run: request, wantErr: ErrAlreadyClaimed},
+		{name: "maintainer rejects", run: func() error { _, err := claims.Reject(claimID, &f.owner.ID, ""); return err }},
+		{name: "claim again after rejection", run: request},
+		{name: "maintainer accepts", run: func() error { _, err := claims.Accept(claimID, &f.owner.ID, ""); return err }},
+		{name: "contributor withdraws", run: func() error { _, err := claims.Withdraw(f.task.ID, f.contributor.ID, ""); return err }},
+		{name: "claim again after withdrawal", run: request},
+		{name: "second claim while the new one is pending", run: request, wantErr: ErrAlreadyClaimed},
+	}
+	// The steps build on each other, so they run in order and stop at the
+	// first failure.
+	for _, tt := range tests {
+		if !t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		}) {
+			return
+		}
+	}
+
+	var count int64
+	if err := db.DB.Model(&models.Claim{}).Where("task_id = ? AND user_id = ?", f.task.ID, f.contributor.ID).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count claims: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("contributor has %d claims, want 3", count)
+	}
+	// The unique index backs the check in Request against concurrent claims.
+	duplicate := models.Claim{TaskID: f.task.ID, UserID: f.contributor.ID, Status: models.ClaimStatusAccepted}
+	if err := db.DB.Omit(clause.Associations).Create(&duplicate).Error; !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("second active claim insert error = %v, want %v", err, gorm.ErrDuplicatedKey)
+	}
+}
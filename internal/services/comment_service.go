@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"regexp"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+
+// MaxCommentLength caps comment bodies, in bytes.
+const MaxCommentLength = 10000
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("only the author can edit this comment")
+	ErrInvalidComment   = errors.New("invalid comment")
+	ErrNotInThread      = errors.New("only the contributor and project maintainers can take part in this thread")
+)
+
+// mentionPattern matches @username mentions, but not the domain part of an
+// email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9](?:[A-Za-z0-9_-]*[A-Za-z0-9])?)`)
+
+// CommentService runs the discussion threads on tasks and contributions. Task
+// threads are public; a contribution's thread is limited to its contributor
+// and the maintainers reviewing it.
+type CommentService struct {
+	Access *ProjectAccessService
+}
+
+func NewCommentService(access *ProjectAccessService) *CommentService {
+	return &CommentService{Access: access}
+}
+
+// ListForTask returns the task's comments, oldest first.
+func (s *CommentService) ListForTask(taskID uint) ([]models.Comment, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	return s.list(db.DB.Where("task_id = ?", taskID))
+}
+
+// ListForContribution returns the contribution's comments, oldest first.
+func (s *CommentService) ListForContribution(user *models.User, contributionID uint) ([]models.Comment, error) {
+	if _, err := s.authorizeContributionThread(user, contributionID); err != nil {
+		return nil, err
+	}
+	return s.list(db.DB.Where("contribution_id = ?", contributionID))
+}
+
+// AddToTask posts a comment on a task. Any signed-in user may comment.
+func (s *CommentService) AddToTask(author *models.User, taskID uint, parentID *uint, body string) (*models.Comment, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	comment := models.Comment{TaskID: &task.ID}
+	return s.add(&comment, author, parentID, body)
+}
+
+// AddToContribution posts review feedback, or a reply to it, on a
+// contribution.
+func (s *CommentService) AddToContribution(author *models.User, contributionID uint, parentID *uint, body string) (*models.Comment, error) {
+	contribution, err := s.authorizeContributionThread(author, contributionID)
+	if err != nil {
+		return nil, err
+	}
+	comment := models.Comment{ContributionID: &contribution.ID}
+	return s.add(&comment, author, parentID, body)
+}
+
+// Edit replaces the body of a comment. Only its author may edit it.
+func (s *CommentService) Edit(user *models.User, commentID uint, body string) (*models.Comment, error) {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.get(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != user.ID {
+		return nil, fmt.Errorf("comment %d: %w", commentID, ErrNotCommentAuthor)
+	}
+	mentions, err := resolveMentions(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Updates(map[string]interface{}{"body": body, "edited_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to update comment %d: %w", commentID, err)
+		}
+		if err := tx.Model(comment).Omit("Mentions.*").Association("Mentions").Replace(mentions); err != nil {
+			return fmt.Errorf("failed to update mentions of comment %d: %w", commentID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(commentID)
+}
+
+// Delete removes a comment. Authors may delete their own comments and project
+// maintainers may delete any comment in their project's threads. Replies to a
+// deleted comment are kept.
+func (s *CommentService) Delete(user *models.User, commentID uint) error {
+	comment, err := s.get(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != user.ID {
+		if comment.TaskID != nil {
+			_, err = s.Access.AuthorizeTask(user, *comment.TaskID)
+		} else {
+			_, err = s.Access.AuthorizeContribution(user, *comment.ContributionID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := db.DB.Delete(comment).Error; err != nil {
+		return fmt.Errorf("failed to delete comment %d: %w", commentID, err)
+	}
+	return nil
+}
+
+func (s *CommentService) list(query *gorm.DB) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := query.Preload("Author").Preload("Mentions").Order("created_at, id").Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (s *CommentService) get(commentID uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := db.DB.Preload("Author").Preload("Mentions").First(&comment, commentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("comment %d: %w", commentID, ErrCommentNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch comment %d: %w", commentID, err)
+	}
+	return &comment, nil
+}
+
+// add fills in and saves comment, whose thread (task or contribution) is
+// already set.
+func (s *CommentService) add(comment *models.Comment, author *models.User, parentID *uint, body string) (*models.Comment, error) {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		parent, err := s.get(*parentID)
+		if err != nil {
+			return nil, err
+		}
+		if !sameThread(parent, comment) {
+			return nil, fmt.Errorf("%w: comment %d belongs to a different thread", ErrInvalidComment, *parentID)
+		}
+	}
+	mentions, err := resolveMentions(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.ParentID = parentID
+	comment.AuthorID = author.ID
+	comment.Body = body
+	comment.Mentions = mentions
+	if err := db.DB.Omit("Author", "Mentions.*").Create(comment).Error; err != nil {
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	return s.get(comment.ID)
+}
+
+// authorizeContributionThread lets the contributor and the project's
+// maintainers into a contribution's thread.
+func (s *CommentService) authorizeContributionThread(user *models.User, contributionID uint) (*models.Contribution, error) {
+	var contribution models.Contribution
+	if err := db.DB.First(&contribution, contributionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("contribution %d: %w", contributionID, ErrContributionNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch contribution %d: %w", contributionID, err)
+	}
+	if contribution.UserID == user.ID {
+		return &contribution, nil
+	}
+	if _, err := s.Access.AuthorizeContribution(user, contributionID); err != nil {
+		if errors.Is(err, ErrNotProjectMaintainer) {
+			return nil, fmt.Errorf("contribution %d: %w", contributionID, ErrNotInThread)
+		}
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+func sameThread(a, b *models.Comment) bool {
+	if a.TaskID != nil && b.TaskID != nil {
+		return *a.TaskID == *b.TaskID
+	}
+	if a.ContributionID != nil && b.ContributionID != nil {
+		return *a.ContributionID == *b.ContributionID
+	}
+	return false
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body cannot be empty", ErrInvalidComment)
+	}
+	if len(body) > MaxCommentLength {
+		return "", fmt.Errorf("%w: body is longer than %d characters", ErrInvalidComment, MaxCommentLength)
+	}
+	return body, nil
+}
+
+// ParseMentions returns the distinct usernames @mentioned in body, in order
+// of first appearance.
+func ParseMentions(body string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := match[1]
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// resolveMentions looks up the users @mentioned in body. Names that match no
+// user are left as plain text.
+func resolveMentions(body string) ([]models.User, error) {
+	names := ParseMentions(body)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var users []models.User
+	if err := db.DB.Where("username IN ?", names).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve mentions: %w", err)
+	}
+	return users, nil
+}
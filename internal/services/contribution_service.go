@@ -1,23 +1,95 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"ossyne/internal/db"
 	"ossyne/internal/models"
 	"time"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrContributionNotPending = errors.New("contribution has already been reviewed")
+	ErrSelfReview             = errors.New("maintainers cannot accept their own contributions")
+	ErrTaskNotAcceptingWork   = errors.New("task is not in a state to accept contributions")
+	ErrNoClaimForSubmission   = errors.New("user does not hold an accepted claim on this task")
+	ErrAlreadySubmitted       = errors.New("user has already submitted a contribution for this task")
 )
 
 type ContributionService struct {
 	PaymentService *PaymentService
+	TaskStates     *TaskStateService
 }
 
-func NewContributionService(paymentService *PaymentService) *ContributionService {
+func NewContributionService(paymentService *PaymentService, taskStates *TaskStateService) *ContributionService {
 	return &ContributionService{
 		PaymentService: paymentService,
+		TaskStates:     taskStates,
+	}
+}
+
+// Submit records userID's pull request for a task they hold an accepted claim
+// on and moves the task to submitted. reason goes into the task history.
+func (s *ContributionService) Submit(taskID, userID uint, prURL, reason string) (*models.Contribution, error) {
+	contribution := &models.Contribution{
+		TaskID:             taskID,
+		UserID:             userID,
+		PRURL:              prURL,
+		VerificationStatus: models.VerificationStatusUnverified,
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		if !task.AcceptsContributions() {
+			return fmt.Errorf("%w: task '%s' is %s", ErrTaskNotAcceptingWork, task.Title, task.Status)
+		}
+
+		var acceptedClaims int64
+		if err := tx.Model(&models.Claim{}).
+			Where("task_id = ? AND user_id = ? AND status = ?", taskID, userID, models.ClaimStatusAccepted).
+			Count(&acceptedClaims).Error; err != nil {
+			return fmt.Errorf("failed to check claims on task %d: %w", taskID, err)
+		}
+		if acceptedClaims == 0 {
+			return fmt.Errorf("%w: user %d on task '%s'", ErrNoClaimForSubmission, userID, task.Title)
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Contribution{}).
+			Where("task_id = ? AND user_id = ?", taskID, userID).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check contributions on task %d: %w", taskID, err)
+		}
+		if existing > 0 {
+			return fmt.Errorf("%w: user %d on task '%s'", ErrAlreadySubmitted, userID, task.Title)
+		}
+
+		if err := tx.Omit("Task", "User", "Payment").Create(contribution).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("%w: user %d on task '%s'", ErrAlreadySubmitted, userID, task.Title)
+			}
+			return fmt.Errorf("failed to create contribution: %w", err)
+		}
+		// On parallel tasks later submissions leave the task in submitted.
+		if task.Status == models.TaskStatusSubmitted {
+			return nil
+		}
+		return s.TaskStates.Transition(tx, &task, models.TaskStatusSubmitted, &userID, reason)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return contribution, nil
 }
 
-func (s *ContributionService) VerifyAndAcceptContribution(contributionID uint, prURL string) error {
+func (s *ContributionService) VerifyAndAcceptContribution(contributionID uint, prURL string, actorID *uint) error {
 	tx := db.DB.Begin()
 	if tx.Error != nil {
 		return fmt.Errorf("failed to start transaction: %w", tx.Error)
@@ -38,6 +110,11 @@ func (s *ContributionService) VerifyAndAcceptContribution(contributionID uint, p
 		tx.Rollback()
 		return fmt.Errorf("contribution %d is already %s", contributionID, contribution.VerificationStatus)
 	}
+	// Accepting releases the bounty, so nobody may do it for their own work.
+	if actorID != nil && *actorID == contribution.UserID {
+		tx.Rollback()
+		return fmt.Errorf("%w: contribution %d", ErrSelfReview, contributionID)
+	}
 
 	fmt.Printf("Simulating Git verification for PR: %s\n", prURL)
 	now := time.Now()
@@ -53,10 +130,17 @@ func (s *ContributionService) VerifyAndAcceptContribution(contributionID uint, p
 		tx.Rollback()
 		return fmt.Errorf("task with ID %d not found: %w", contribution.TaskID, err)
 	}
-	if err := tx.Model(&task).Update("status", "completed").Error; err != nil {
+	if err := s.TaskStates.Transition(tx, &task, models.TaskStatusCompleted, actorID, fmt.Sprintf("Contribution %d accepted", contributionID)); err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update task status to completed: %w", err)
 	}
+	// On competitive tasks the first accepted contribution wins; close out the rest.
+	if err := tx.Model(&models.Contribution{}).
+		Where("task_id = ? AND id <> ? AND verification_status = ?", task.ID, contribution.ID, models.VerificationStatusUnverified).
+		Update("verification_status", models.VerificationStatusRejected).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to reject competing contributions: %w", err)
+	}
 
 	reputationAward := 100
 	if task.BountyAmount > 0 {
@@ -103,79 +187,52 @@ func (s *ContributionService) VerifyAndAcceptContribution(contributionID uint, p
 	return nil
 }
 
-func (s *ContributionService) RejectContribution(contributionID uint, reason string) error {
-	result := db.DB.Model(&models.Contribution{}).
-		Where("id = ?", contributionID).
-		Update("verification_status", "rejected").
-		Update("accepted_at", nil)
-
-	if result.Error != nil {
-		return fmt.Errorf("failed to reject contribution: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("contribution with ID %d not found", contributionID)
-	}
-
-	var contribution models.Contribution
-	if err := db.DB.First(&contribution, contributionID).Error; err == nil {
-		db.DB.Model(&models.Task{}).
-			Where("id = ?", contribution.TaskID).
-			Update("status", "claimed")
-	}
-
-	return nil
-}
-
-func (s *ContributionService) MentorEndorsements(mentorID, userID, relatedID uint, notes string) error {
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+func (s *ContributionService) RejectContribution(contributionID uint, reason string, actorID *uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var contribution models.Contribution
+		if err := tx.First(&contribution, contributionID).Error; err != nil {
+			return fmt.Errorf("contribution with ID %d not found: %w", contributionID, err)
 		}
-	}()
-	var user models.User
-	if err := tx.First(&user, userID).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("user with ID %d not found: %w", userID, err)
-	}
-
-	endorsementScore := 20
-	user.Ratings += endorsementScore
-	if err := tx.Save(&user).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to update user ratings for endorsement: %w", err)
-	}
-
-	repLog := models.ReputationEventLog{
-		UserID:      user.ID,
-		EventType:   models.ReputationEventMentorEndorsement,
-		ScoreChange: endorsementScore,
-		RelatedID:   &relatedID,
-		Notes:       notes,
-	}
-	if err := tx.Create(&repLog).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to log mentor endorsement event: %w", err)
-	}
-
-	var mentor models.User
-	if err := tx.First(&mentor, mentorID).Error; err == nil {
-		mentor.Ratings += 5
-		tx.Save(&mentor)
-		mentorLog := models.ReputationEventLog{
-			UserID:      mentor.ID,
-			EventType:   models.ReputationEventManualAdjustment,
-			ScoreChange: 5,
-			Notes:       fmt.Sprintf("Mentored user %d for related ID %d", userID, relatedID),
+		// Accepted contributions have been paid and credited; only those still
+		// awaiting review can be rejected.
+		if contribution.VerificationStatus != models.VerificationStatusUnverified {
+			return fmt.Errorf("%w: contribution %d is %s", ErrContributionNotPending, contributionID, contribution.VerificationStatus)
+		}
+		result := tx.Model(&models.Contribution{}).
+			Where("id = ? AND verification_status = ?", contribution.ID, models.VerificationStatusUnverified).
+			Updates(map[string]interface{}{
+				"verification_status": models.VerificationStatusRejected,
+				"accepted_at":         nil,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to reject contribution: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: contribution %d", ErrContributionNotPending, contributionID)
 		}
-		tx.Create(&mentorLog)
-	}
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction for endorsement: %w", err)
-	}
 
-	return nil
-}
\ No newline at end of file
+		var task models.Task
+		if err := tx.First(&task, contribution.TaskID).Error; err != nil {
+			return fmt.Errorf("task with ID %d not found: %w", contribution.TaskID, err)
+		}
+		// The claimant keeps the task and may submit again. On parallel tasks
+		// the task stays submitted while other contributions await review.
+		if task.Status != models.TaskStatusSubmitted {
+			return nil
+		}
+		var awaitingReview int64
+		if err := tx.Model(&models.Contribution{}).
+			Where("task_id = ? AND verification_status = ?", task.ID, models.VerificationStatusUnverified).
+			Count(&awaitingReview).Error; err != nil {
+			return fmt.Errorf("failed to count contributions awaiting review: %w", err)
+		}
+		if awaitingReview > 0 {
+			return nil
+		}
+		note := fmt.Sprintf("Contribution %d rejected", contributionID)
+		if reason != "" {
+			note += ": " + reason
+		}
+		return s.TaskStates.Transition(tx, &task, models.TaskStatusClaimed, actorID, note)
+	})
+}
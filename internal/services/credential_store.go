@@ -0,0 +1,292 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	CredentialStoreEnvVar      = "OSSYNE_CREDENTIAL_STORE"
+	CredentialPassphraseEnvVar = "OSSYNE_CREDENTIALS_PASSPHRASE"
+	CredentialKeyEnvVar        = "OSSYNE_CREDENTIALS_KEY"
+
+	credentialFileVersion   = 1
+	credentialKDFIterations = 600000
+	keyringProbeUser        = "backend_probe"
+)
+
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore is where the CLI keeps session tokens between runs.
+type CredentialStore interface {
+	// Name identifies the backend in 'osm auth status'.
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// CredentialStoreInfo describes the store picked for this process and, when
+// the OS keyring was skipped, why.
+type CredentialStoreInfo struct {
+	Store          CredentialStore
+	FallbackReason string
+}
+
+var (
+	defaultStoreOnce sync.Once
+	defaultStoreInfo CredentialStoreInfo
+)
+
+// DefaultCredentialStore returns the OS keyring when it works and the
+// encrypted file store otherwise. OSSYNE_CREDENTIAL_STORE=keyring|file forces
+// a backend.
+func DefaultCredentialStore() CredentialStoreInfo {
+	defaultStoreOnce.Do(func() {
+		defaultStoreInfo = selectCredentialStore()
+	})
+	return defaultStoreInfo
+}
+
+func selectCredentialStore() CredentialStoreInfo {
+	switch os.Getenv(CredentialStoreEnvVar) {
+	case "keyring":
+		return CredentialStoreInfo{Store: &OSKeyringStore{}}
+	case "file":
+		return CredentialStoreInfo{Store: NewEncryptedFileStore(""), FallbackReason: CredentialStoreEnvVar + "=file"}
+	}
+	store := &OSKeyringStore{}
+	if err := store.probe(); err != nil {
+		return CredentialStoreInfo{Store: NewEncryptedFileStore(""), FallbackReason: fmt.Sprintf("OS keyring unavailable: %v", err)}
+	}
+	return CredentialStoreInfo{Store: store}
+}
+
+// OSKeyringStore keeps credentials in the platform keyring (Secret Service,
+// macOS Keychain or Windows Credential Manager).
+type OSKeyringStore struct{}
+
+func (s *OSKeyringStore) Name() string {
+	return "os-keyring"
+}
+
+func (s *OSKeyringStore) Get(key string) (string, error) {
+	value, err := keyring.Get(keyringService, key)
+	if err == keyring.ErrNotFound {
+		return "", ErrCredentialNotFound
+	}
+	return value, err
+}
+
+func (s *OSKeyringStore) Set(key, value string) error {
+	return keyring.Set(keyringService, key, value)
+}
+
+func (s *OSKeyringStore) Delete(key string) error {
+	err := keyring.Delete(keyringService, key)
+	if err == keyring.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// probe checks that the keyring daemon can be reached at all; a missing entry
+// still proves it is running.
+func (s *OSKeyringStore) probe() error {
+	if _, err := keyring.Get(keyringService, keyringProbeUser); err != nil && err != keyring.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// EncryptedFileStore keeps credentials in a single AES-256-GCM encrypted file.
+// The key comes from OSSYNE_CREDENTIALS_KEY (base64, 32 bytes) or is derived
+// from OSSYNE_CREDENTIALS_PASSPHRASE with PBKDF2.
+type EncryptedFileStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+type encryptedCredentialFile struct {
+	Version    int    `json:"version"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// NewEncryptedFileStore stores credentials at path, or at credentials.enc in
+// the OSSYNE config directory when path is empty.
+func NewEncryptedFileStore(path string) *EncryptedFileStore {
+	if path == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "ossyne", "credentials.enc")
+		}
+	}
+	return &EncryptedFileStore{Path: path}
+}
+
+func (s *EncryptedFileStore) Name() string {
+	return "encrypted-file"
+}
+
+func (s *EncryptedFileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, _, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := creds[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+func (s *EncryptedFileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	creds[key] = value
+	return s.save(creds, salt)
+}
+
+func (s *EncryptedFileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creds, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := creds[key]; !ok {
+		return nil
+	}
+	delete(creds, key)
+	return s.save(creds, salt)
+}
+
+// load decrypts the credential file. A missing file yields an empty set and a
+// fresh salt.
+func (s *EncryptedFileStore) load() (map[string]string, []byte, error) {
+	if s.Path == "" {
+		return nil, nil, fmt.Errorf("cannot locate a directory for the credential file")
+	}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		return map[string]string{}, salt, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", s.Path, err)
+	}
+
+	var file encryptedCredentialFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", s.Path, err)
+	}
+	if file.Version != credentialFileVersion {
+		return nil, nil, fmt.Errorf("unsupported credential file version %d", file.Version)
+	}
+	salt, err := base64.StdEncoding.DecodeString(file.Salt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("corrupt credential file salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, nil, fmt.Errorf("corrupt credential file nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, nil, fmt.Errorf("corrupt credential file: %w", err)
+	}
+	aead, err := credentialCipher(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt %s, check %s or %s", s.Path, CredentialPassphraseEnvVar, CredentialKeyEnvVar)
+	}
+	creds := map[string]string{}
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse decrypted credentials: %w", err)
+	}
+	return creds, salt, nil
+}
+
+func (s *EncryptedFileStore) save(creds map[string]string, salt []byte) error {
+	aead, err := credentialCipher(salt)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data, err := json.Marshal(encryptedCredentialFile{
+		Version:    credentialFileVersion,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode credential file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create credential directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated store.
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write credential file: %w", err)
+	}
+	if err := os.Rename(tmp, s.Path); err != nil {
+		return fmt.Errorf("failed to replace credential file: %w", err)
+	}
+	return nil
+}
+
+func credentialCipher(salt []byte) (cipher.AEAD, error) {
+	var key []byte
+	if encoded := os.Getenv(CredentialKeyEnvVar); encoded != "" {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("%s must be 32 bytes, base64 encoded", CredentialKeyEnvVar)
+		}
+		key = decoded
+	} else if passphrase := os.Getenv(CredentialPassphraseEnvVar); passphrase != "" {
+		derived, err := pbkdf2.Key(sha256.New, passphrase, salt, credentialKDFIterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive credential key: %w", err)
+		}
+		key = derived
+	} else {
+		return nil, fmt.Errorf("no OS keyring is available; set %s or %s to store credentials in an encrypted file", CredentialPassphraseEnvVar, CredentialKeyEnvVar)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+
+const (
+	deviceCodeTTL      = 15 * time.Minute
+	devicePollInterval = 5
+	userCodeAlphabet   = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength     = 8
+)
+
+// Errors returned by DeviceAuthService.Poll. Their messages match the error
+// codes of RFC 8628 so handlers can pass them straight to the client.
+var (
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrAccessDenied         = errors.New("access_denied")
+	ErrExpiredToken         = errors.New("expired_token")
+	ErrInvalidUserCode      = errors.New("invalid or expired user code")
+)
+
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceAuthService implements the device authorization grant used by
+// terminals that cannot receive a browser redirect.
+type DeviceAuthService struct {
+	Sessions *SessionService
+	TTL      time.Duration
+	Interval int
+}
+
+func NewDeviceAuthService(sessions *SessionService) *DeviceAuthService {
+	return &DeviceAuthService{
+		Sessions: sessions,
+		TTL:      deviceCodeTTL,
+		Interval: devicePollInterval,
+	}
+}
+
+// Start issues a new device code / user code pair for the client described by
+// userAgent and clientIP.
+func (s *DeviceAuthService) Start(verificationURI, userAgent, clientIP string) (*DeviceCodeResponse, error) {
+	deviceCode, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate device code: %w", err)
+	}
+	userCode, err := newUserCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user code: %w", err)
+	}
+	auth := models.DeviceAuthorization{
+		DeviceCodeHash: HashToken(deviceCode),
+		UserCode:       userCode,
+		Status:         models.DeviceAuthStatusPending,
+		ExpiresAt:      time.Now().Add(s.TTL),
+		PollInterval:   s.Interval,
+
+		ClientUserAgent: truncate(userAgent, 255),
+		ClientIP:        truncate(clientIP, 64),
+	}
+	if err := db.DB.Create(&auth).Error; err != nil {
+		return nil, fmt.Errorf("failed to store device authorization: %w", err)
+	}
+	return &DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                FormatUserCode(userCode),
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + FormatUserCode(userCode),
+		ExpiresIn:               int(s.TTL.Seconds()),
+		Interval:                s.Interval,
+	}, nil
+}
+
+// FindPending returns the pending, unexpired authorization for a user code
+// as typed by the user.
+func (s *DeviceAuthService) FindPending(userCode string) (*models.DeviceAuthorization, error) {
+	var auth models.DeviceAuthorization
+	err := db.DB.Where("user_code = ? AND status = ? AND expires_at > ?",
+		normalizeUserCode(userCode), models.DeviceAuthStatusPending, time.Now()).First(&auth).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidUserCode
+		}
+		return nil, fmt.Errorf("failed to look up device authorization: %w", err)
+	}
+	return &auth, nil
+}
+
+// Prepare links a pending authorization to the user who completed the browser
+// login and returns the token that user must send back to approve or deny it.
+// The device stays pending until then, so following someone else's
+// verification link never logs their device in by itself.
+func (s *DeviceAuthService) Prepare(authorizationID, userID uint) (string, *models.DeviceAuthorization, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	result := db.DB.Model(&models.DeviceAuthorization{}).
+		Where("id = ? AND status = ? AND expires_at > ?", authorizationID, models.DeviceAuthStatusPending, time.Now()).
+		Updates(map[string]interface{}{"user_id": userID, "confirm_token_hash": HashToken(token)})
+	if result.Error != nil {
+		return "", nil, fmt.Errorf("failed to prepare device authorization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return "", nil, ErrInvalidUserCode
+	}
+	var auth models.DeviceAuthorization
+	if err := db.DB.First(&auth, authorizationID).Error; err != nil {
+		return "", nil, fmt.Errorf("failed to fetch device authorization: %w", err)
+	}
+	return token, &auth, nil
+}
+
+// Approve lets the device log in as the user who prepared the authorization.
+func (s *DeviceAuthService) Approve(userCode, confirmToken string) error {
+	return s.decide(userCode, confirmToken, models.DeviceAuthStatusApproved)
+}
+
+// Deny refuses the device; its next poll gets access_denied.
+func (s *DeviceAuthService) Deny(userCode, confirmToken string) error {
+	return s.decide(userCode, confirmToken, models.DeviceAuthStatusDenied)
+}
+
+func (s *DeviceAuthService) decide(userCode, confirmToken, status string) error {
+	if confirmToken == "" {
+		return ErrInvalidUserCode
+	}
+	result := db.DB.Model(&models.DeviceAuthorization{}).
+		Where("user_code = ? AND status = ? AND expires_at > ? AND user_id IS NOT NULL AND confirm_token_hash = ?",
+			normalizeUserCode(userCode), models.DeviceAuthStatusPending, time.Now(), HashToken(confirmToken)).
+		Updates(map[string]interface{}{"status": status, "confirm_token_hash": nil})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update device authorization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidUserCode
+	}
+	return nil
+}
+
+// Poll is called repeatedly by the device. Once the authorization has been
+// approved it is consumed and a new session is minted for the user.
+func (s *DeviceAuthService) Poll(deviceCode, userAgent string) (*SessionTokens, error) {
+	var auth models.DeviceAuthorization
+	if err := db.DB.Where("device_code_hash = ?", HashToken(deviceCode)).First(&auth).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExpiredToken
+		}
+		return nil, fmt.Errorf("failed to look up device authorization: %w", err)
+	}
+
+	now := time.Now()
+	if now.After(auth.ExpiresAt) || auth.Status == models.DeviceAuthStatusConsumed {
+		return nil, ErrExpiredToken
+	}
+	if auth.Status == models.DeviceAuthStatusDenied {
+		return nil, ErrAccessDenied
+	}
+	tooSoon := auth.LastPolledAt != nil && now.Sub(*auth.LastPolledAt) < time.Duration(auth.PollInterval)*time.Second
+	db.DB.Model(&auth).UpdateColumn("last_polled_at", now)
+	if tooSoon {
+		return nil, ErrSlowDown
+	}
+	if auth.Status != models.DeviceAuthStatusApproved || auth.UserID == nil {
+		return nil, ErrAuthorizationPending
+	}
+
+	result := db.DB.Model(&models.DeviceAuthorization{}).
+		Where("id = ? AND status = ?", auth.ID, models.DeviceAuthStatusApproved).
+		Update("status", models.DeviceAuthStatusConsumed)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to consume device authorization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrExpiredToken
+	}
+	return s.Sessions.Create(*auth.UserID, userAgent)
+}
+
+func newUserCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < userCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// FormatUserCode renders a stored user code the way it is shown to users.
+func FormatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
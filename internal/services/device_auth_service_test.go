@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserCodeFormatting(t *testing.T) {
+	tests := []struct {
+		name          string
+		typed         string
+		wantNormal    string
+		wantFormatted string
+	}{
+		{name: "as displayed", typed: "BCDF-GHJK", wantNormal: "BCDFGHJK", wantFormatted: "BCDF-GHJK"},
+		{name: "lower case", typed: "bcdf-ghjk", wantNormal: "BCDFGHJK", wantFormatted: "BCDF-GHJK"},
+		{name: "spaces", typed: " BCDF GHJK ", wantNormal: "BCDFGHJK", wantFormatted: "BCDF-GHJK"},
+		{name: "no separator", typed: "BCDFGHJK", wantNormal: "BCDFGHJK", wantFormatted: "BCDF-GHJK"},
+		{name: "wrong length", typed: "BCD-FG", wantNormal: "BCDFG", wantFormatted: "BCDFG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normal := normalizeUserCode(tt.typed)
+			if normal != tt.wantNormal {
+				t.Errorf("normalizeUserCode(%q) = %q, want %q", tt.typed, normal, tt.wantNormal)
+			}
+			if got := FormatUserCode(normal); got != tt.wantFormatted {
+				t.Errorf("FormatUserCode(%q) = %q, want %q", normal, got, tt.wantFormatted)
+			}
+		})
+	}
+}
+
+func TestNewUserCode(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		code, err := newUserCode()
+		if err != nil {
+			t.Fatalf("newUserCode: %v", err)
+		}
+		if len(code) != userCodeLength || normalizeUserCode(code) != code {
+			t.Fatalf("newUserCode() = %q, want %d letters from %s", code, userCodeLength, userCodeAlphabet)
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(userCodeAlphabet, r) {
+				t.Fatalf("newUserCode() = %q uses %q, outside %s", code, r, userCodeAlphabet)
+			}
+		}
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	useTestDB(t)
+	user := createTestUser(t, "device-user")
+	devices := NewDeviceAuthService(NewSessionService())
+	devices.Interval = 0
+
+	tests := []struct {
+		name string
+		// decide runs after the device started polling. It gets the user
+		// code as shown to the user.
+		decide       func(t *testing.T, userCode string)
+		wantDecision error
+		wantPolls    []error
+	}{
+		{
+			name:      "nobody answered",
+			decide:    func(t *testing.T, userCode string) {},
+			wantPolls: []error{ErrAuthorizationPending, ErrAuthorizationPending},
+		},
+		{
+			name: "approved",
+			decide: func(t *testing.T, userCode string) {
+				token := prepareDevice(t, devices, userCode, user.ID)
+				if err := devices.Approve(userCode, token); err != nil {
+					t.Fatalf("Approve: %v", err)
+				}
+			},
+			wantPolls: []error{nil, ErrExpiredToken},
+		},
+		{
+			name: "denied",
+			decide: func(t *testing.T, userCode string) {
+				token := prepareDevice(t, devices, userCode, user.ID)
+				if err := devices.Deny(userCode, token); err != nil {
+					t.Fatalf("Deny: %v", err)
+				}
+			},
+			wantPolls: []error{ErrAccessDenied, ErrAccessDenied},
+		},
+		{
+			name: "approved with the wrong confirmation token",
+			decide: func(t *testing.T, userCode string) {
+				prepareDevice(t, devices, userCode, user.ID)
+				if err := devices.Approve(userCode, "forged"); !errors.Is(err, ErrInvalidUserCode) {
+					t.Fatalf("Approve() error = %v, want %v", err, ErrInvalidUserCode)
+				}
+			},
+			wantPolls: []error{ErrAuthorizationPending},
+		},
+		{
+			name: "approved without a browser login",
+			decide: func(t *testing.T, userCode string) {
+				if err := devices.Approve(userCode, ""); !errors.Is(err, ErrInvalidUserCode) {
+					t.Fatalf("Approve() error = %v, want %v", err, ErrInvalidUserCode)
+				}
+			},
+			wantPolls: []error{ErrAuthorizationPending},
+		},
+		{
+			name: "confirmation token used twice",
+			decide: func(t *testing.T, userCode string) {
+				token := prepareDevice(t, devices, userCode, user.ID)
+				if err := devices.Deny(userCode, token); err != nil {
+					t.Fatalf("Deny: %v", err)
+				}
+				if err := devices.Approve(userCode, token); !errors.Is(err, ErrInvalidUserCode) {
+					t.Fatalf("Approve() after Deny error = %v, want %v", err, ErrInvalidUserCode)
+				}
+			},
+			wantPolls: []error{ErrAccessDenied},
+		},
+		{
+			name: "expired",
+			decide: func(t *testing.T, userCode string) {
+				if err := db.DB.Model(&models.DeviceAuthorization{}).
+					Where("user_code = ?", normalizeUserCode(userCode)).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+					t.Fatalf("failed to expire device code: %v", err)
+				}
+				if _, err := devices.FindPending(userCode); !errors.Is(err, ErrInvalidUserCode) {
+					t.Fatalf("FindPending() error = %v, want %v", err, ErrInvalidUserCode)
+				}
+			},
+			wantPolls: []error{ErrExpiredToken},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := devices.Start("http://localhost:8080/auth/device", "osm-test", "127.0.0.1")
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if _, err := devices.Poll(start.DeviceCode, "osm-test"); !errors.Is(err, ErrAuthorizationPending) {
+				t.Fatalf("first Poll() error = %v, want %v", err, ErrAuthorizationPending)
+			}
+			tt.decide(t, start.UserCode)
+			for i, want := range tt.wantPolls {
+				tokens, err := devices.Poll(start.DeviceCode, "osm-test")
+				if !errors.Is(err, want) {
+					t.Fatalf("Poll() #%d error = %v, want %v", i+1, err, want)
+				}
+				if want == nil && tokens.AccessToken == "" {
+					t.Fatalf("Poll() #%d returned no session", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestDevicePollSlowDown(t *testing.T) {
+	useTestDB(t)
+	devices := NewDeviceAuthService(NewSessionService())
+	start, err := devices.Start("http://localhost:8080/auth/device", "osm-test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for i, want := range []error{ErrAuthorizationPending, ErrSlowDown} {
+		if _, err := devices.Poll(start.DeviceCode, "osm-test"); !errors.Is(err, want) {
+			t.Fatalf("Poll() #%d error = %v, want %v", i+1, err, want)
+		}
+	}
+}
+
+// prepareDevice does what the GitHub callback does for a device login and
+// returns the confirmation token of the page it shows.
+func prepareDevice(t *testing.T, devices *DeviceAuthService, userCode string, userID uint) string {
+	t.Helper()
+	auth, err := devices.FindPending(userCode)
+	if err != nil {
+		t.Fatalf("FindPending: %v", err)
+	}
+	token, _, err := devices.Prepare(auth.ID, userID)
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	return token
+}
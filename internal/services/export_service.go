@@ -0,0 +1,371 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"slices"
+	"time"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many rows an export loads at a time.
+const exportBatchSize = 500
+
+var (
+	ErrInvalidExport   = errors.New("invalid project export")
+	ErrProjectNotEmpty = errors.New("project already has tasks, import into a new project")
+)
+
+// ExportService writes a project's tasks, claims, contributions, payouts and
+// reputation events out for reports, and restores exports into a project.
+// Exports are streamed in batches so large projects are never loaded whole.
+type ExportService struct {
+	Access *ProjectAccessService
+}
+
+func NewExportService(access *ProjectAccessService) *ExportService {
+	return &ExportService{Access: access}
+}
+
+// IsValidExportTable reports whether table can be exported as CSV.
+func IsValidExportTable(table string) bool {
+	return slices.Contains(models.ExportTables, table)
+}
+
+// exportRecord is a row of an export, as models.Export* types.
+type exportRecord interface {
+	CSVRow() []string
+}
+
+// WriteJSON streams the project as a models.ProjectExport document.
+func (s *ExportService) WriteJSON(w io.Writer, project *models.Project) error {
+	out := bufio.NewWriter(w)
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	meta, err := json.Marshal(models.NewExportProject(project))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, `{"version":%d,"exported_at":%s,"project":%s`, models.ExportFormatVersion, exportedAt, meta)
+
+	scope := exportScope{projectID: project.ID}
+	for _, table := range models.ExportTables {
+		fmt.Fprintf(out, `,%q:[`, table)
+		first := true
+		err := scope.each(table, func(record exportRecord) error {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if !first {
+				out.WriteByte(',')
+			}
+			first = false
+			_, err = out.Write(data)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export %s of project %d: %w", table, project.ID, err)
+		}
+		out.WriteByte(']')
+	}
+	out.WriteString("}\n")
+	return out.Flush()
+}
+
+// WriteCSV streams one table of the project as CSV, with a header row.
+func (s *ExportService) WriteCSV(w io.Writer, project *models.Project, table string) error {
+	columns := map[string][]string{
+		models.ExportTableTasks:            models.ExportTaskColumns,
+		models.ExportTableClaims:           models.ExportClaimColumns,
+		models.ExportTableContributions:    models.ExportContributionColumns,
+		models.ExportTablePayouts:          models.ExportPayoutColumns,
+		models.ExportTableReputationEvents: models.ExportReputationEventColumns,
+	}[table]
+	if columns == nil {
+		return fmt.Errorf("%w: unknown table %q", ErrInvalidExport, table)
+	}
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+	err := exportScope{projectID: project.ID}.each(table, func(record exportRecord) error {
+		return out.Write(record.CSVRow())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export %s of project %d: %w", table, project.ID, err)
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// exportScope builds the queries for the rows that belong to one project.
+type exportScope struct {
+	projectID uint
+}
+
+func (p exportScope) tasks() *gorm.DB {
+	return db.DB.Model(&models.Task{}).Where("project_id = ?", p.projectID)
+}
+
+func (p exportScope) claims() *gorm.DB {
+	return db.DB.Model(&models.Claim{}).Where("task_id IN (?)", p.tasks().Select("id"))
+}
+
+func (p exportScope) contributions() *gorm.DB {
+	return db.DB.Model(&models.Contribution{}).Where("task_id IN (?)", p.tasks().Select("id"))
+}
+
+func (p exportScope) payouts() *gorm.DB {
+	return db.DB.Model(&models.Payment{}).Where("contribution_id IN (?)", p.contributions().Select("id"))
+}
+
+// reputationEvents matches events by what their related ID points to:
+// contributions for accepted work and claims for endorsements.
+func (p exportScope) reputationEvents() *gorm.DB {
+	return db.DB.Model(&models.ReputationEventLog{}).Where(
+		"(event_type IN ? AND related_id IN (?)) OR (event_type = ? AND related_id IN (?))",
+		[]string{models.ReputationEventContributionAccepted, models.ReputationEventBountyEarned}, p.contributions().Select("id"),
+		models.ReputationEventMentorEndorsement, p.claims().Select("id"),
+	)
+}
+
+// each calls emit for every row of table, oldest first.
+func (p exportScope) each(table string, emit func(exportRecord) error) error {
+	switch table {
+	case models.ExportTableTasks:
+		return exportRows(p.tasks(), func(t *models.Task) error {
+			record := models.NewExportTask(t)
+			return emit(&record)
+		})
+	case models.ExportTableClaims:
+		return exportRows(p.claims(), func(c *models.Claim) error {
+			record := models.NewExportClaim(c)
+			return emit(&record)
+		})
+	case models.ExportTableContributions:
+		return exportRows(p.contributions(), func(c *models.Contribution) error {
+			record := models.NewExportContribution(c)
+			return emit(&record)
+		})
+	case models.ExportTablePayouts:
+		return exportRows(p.payouts(), func(payment *models.Payment) error {
+			record := models.NewExportPayout(payment)
+			return emit(&record)
+		})
+	case models.ExportTableReputationEvents:
+		return exportRows(p.reputationEvents(), func(e *models.ReputationEventLog) error {
+			record := models.NewExportReputationEvent(e)
+			return emit(&record)
+		})
+	}
+	return fmt.Errorf("%w: unknown table %q", ErrInvalidExport, table)
+}
+
+// exportRows loads the rows of query exportBatchSize at a time, in primary
+// key order, and calls fn for each.
+func exportRows[T any](query *gorm.DB, fn func(*T) error) error {
+	var batch []T
+	return query.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// Import restores an export into an empty project: its metadata (except the
+// repository URL, which must stay unique), tasks, and the claims and
+// contributions of users that exist on this server. Everything is saved in
+// one transaction. Tasks are not put back into milestones and their
+// bounties are not escrowed again; payouts and reputation events are not
+// replayed.
+func (s *ExportService) Import(actor *models.User, projectID uint, doc *models.ProjectExport) (*models.ProjectImportResult, error) {
+	project, err := s.Access.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Version != models.ExportFormatVersion {
+		return nil, fmt.Errorf("%w: version %d, this server reads version %d", ErrInvalidExport, doc.Version, models.ExportFormatVersion)
+	}
+	if err := validateExport(doc); err != nil {
+		return nil, err
+	}
+	var existing int64
+	if err := db.DB.Model(&models.Task{}).Where("project_id = ?", project.ID).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tasks of project %d: %w", project.ID, err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("project %d: %w", project.ID, ErrProjectNotEmpty)
+	}
+	users, err := exportUsers(doc)
+	if err != nil {
+		return nil, err
+	}
+	knownUser := func(id *uint) *uint {
+		if id != nil && users[*id] {
+			return id
+		}
+		return nil
+	}
+
+	result := &models.ProjectImportResult{Payouts: len(doc.Payouts), ReputationEvents: len(doc.ReputationEvents)}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"short_desc": doc.Project.ShortDesc,
+			"tags":       models.JSONStringSlice(doc.Project.Tags),
+		}
+		if doc.Project.Title != "" {
+			updates["title"] = doc.Project.Title
+		}
+		if doc.Project.Visibility == "public" || doc.Project.Visibility == "private" {
+			updates["visibility"] = doc.Project.Visibility
+		}
+		if err := tx.Model(project).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update project %d: %w", project.ID, err)
+		}
+
+		taskIDs := map[uint]uint{}
+		for _, t := range doc.Tasks {
+			// Bounties only exist once escrowed, so they are funded again
+			// rather than copied.
+			if t.BountyAmount > 0 {
+				result.BountiesNotFunded++
+			}
+			task := models.Task{
+				ProjectID:         project.ID,
+				Title:             t.Title,
+				Description:       t.Description,
+				DifficultyLevel:   t.DifficultyLevel,
+				EstimatedHours:    t.EstimatedHours,
+				Tags:              t.Tags,
+				SkillsRequired:    t.SkillsRequired,
+				Status:            t.Status,
+				ClaimPolicy:       t.ClaimPolicy,
+				MaxClaimants:      t.MaxClaimants,
+				GitHubIssueNumber: t.GitHubIssueNumber,
+				GitHubIssueURL:    t.GitHubIssueURL,
+				ManifestKey:       t.ManifestKey,
+			}
+			task.CreatedAt = t.CreatedAt
+			if err := tx.Create(&task).Error; err != nil {
+				return fmt.Errorf("failed to import task %d: %w", t.ID, err)
+			}
+			taskIDs[t.ID] = task.ID
+			result.Tasks++
+		}
+
+		for _, c := range doc.Claims {
+			taskID, ok := taskIDs[c.TaskID]
+			if !ok || !users[c.UserID] {
+				result.SkippedClaims++
+				continue
+			}
+			claim := models.Claim{
+				TaskID:        taskID,
+				UserID:        c.UserID,
+				ClaimDate:     c.ClaimDate,
+				Status:        c.Status,
+				MentorID:      knownUser(c.MentorID),
+				Notes:         c.Notes,
+				ReviewedByID:  knownUser(c.ReviewedByID),
+				ReviewedAt:    c.ReviewedAt,
+				ReviewNote:    c.ReviewNote,
+				ReleasedAt:    c.ReleasedAt,
+				ReleaseReason: c.ReleaseReason,
+				EndorsedAt:    c.EndorsedAt,
+			}
+			if err := tx.Omit("Task", "User", "Mentor").Create(&claim).Error; err != nil {
+				return fmt.Errorf("failed to import claim %d: %w", c.ID, err)
+			}
+			result.Claims++
+		}
+
+		for _, c := range doc.Contributions {
+			taskID, ok := taskIDs[c.TaskID]
+			if !ok || !users[c.UserID] {
+				result.SkippedContributions++
+				continue
+			}
+			contribution := models.Contribution{
+				TaskID:             taskID,
+				UserID:             c.UserID,
+				PRURL:              c.PRURL,
+				PRCommitHashes:     c.PRCommitHashes,
+				SubmittedAt:        c.SubmittedAt,
+				VerificationStatus: c.VerificationStatus,
+				AcceptedAt:         c.AcceptedAt,
+				PayoutAmount:       c.PayoutAmount,
+			}
+			if err := tx.Omit("Task", "User", "Payment").Create(&contribution).Error; err != nil {
+				return fmt.Errorf("failed to import contribution %d: %w", c.ID, err)
+			}
+			result.Contributions++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateExport checks the values the database would otherwise reject with
+// a less helpful error.
+func validateExport(doc *models.ProjectExport) error {
+	for _, t := range doc.Tasks {
+		switch {
+		case t.Title == "":
+			return fmt.Errorf("%w: task %d has no title", ErrInvalidExport, t.ID)
+		case !IsValidTaskStatus(t.Status):
+			return fmt.Errorf("%w: task %d has unknown status %q", ErrInvalidExport, t.ID, t.Status)
+		case !models.IsValidClaimPolicy(t.ClaimPolicy):
+			return fmt.Errorf("%w: task %d has unknown claim policy %q", ErrInvalidExport, t.ID, t.ClaimPolicy)
+		}
+		switch t.DifficultyLevel {
+		case "easy", "medium", "hard":
+		default:
+			return fmt.Errorf("%w: task %d has unknown difficulty %q", ErrInvalidExport, t.ID, t.DifficultyLevel)
+		}
+	}
+	return nil
+}
+
+// exportUsers returns which of the users the export refers to exist.
+func exportUsers(doc *models.ProjectExport) (map[uint]bool, error) {
+	var ids []uint
+	for _, c := range doc.Claims {
+		ids = append(ids, c.UserID)
+		if c.MentorID != nil {
+			ids = append(ids, *c.MentorID)
+		}
+		if c.ReviewedByID != nil {
+			ids = append(ids, *c.ReviewedByID)
+		}
+	}
+	for _, c := range doc.Contributions {
+		ids = append(ids, c.UserID)
+	}
+	users := map[uint]bool{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	var found []uint
+	if err := db.DB.Model(&models.User{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up users of the export: %w", err)
+	}
+	for _, id := range found {
+		users[id] = true
+	}
+	return users, nil
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"slices"
+	"strings"
+	"time"
+	"github.com/google/go-github/v66/github"
+	"gorm.io/gorm"
+)
+
+// maxImportedIssues caps how many issues one import reads.
+const maxImportedIssues = 1000
+
+var (
+	ErrNotGitHubRepo = errors.New("project repository is not a GitHub repository")
+	ErrGitHubRequest = errors.New("GitHub request failed")
+	ErrTooManyIssues = fmt.Errorf("more than %d matching issues, narrow the import with labels", maxImportedIssues)
+)
+
+// IssueImportService turns the open issues of a project's GitHub repository
+// into tasks. Imported tasks remember their issue number, so running an
+// import again updates them instead of adding copies.
+type IssueImportService struct {
+	Access *ProjectAccessService
+	// APIURL is the root of the GitHub REST API. Empty means api.github.com;
+	// point it at a GitHub Enterprise install or a fake server.
+	APIURL string
+	// HTTPClient sends the API requests. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+func NewIssueImportService(access *ProjectAccessService, apiURL string) *IssueImportService {
+	return &IssueImportService{Access: access, APIURL: apiURL}
+}
+
+// Import reads the open issues carrying all of labels from the project's
+// repository and creates or updates a task for each. Title, description,
+// tags and URL follow the issue; the difficulty follows it when a label
+// names one. Bounties, status and claims are never touched. The actor's
+// GitHub token is used when they have one, so the rate limit is the user's
+// own. Login only asks GitHub for read:user and user:email, so the token
+// cannot read private repositories and imports are limited to public ones.
+func (s *IssueImportService) Import(actor *models.User, projectID uint, labels []string) (*models.IssueImportResult, error) {
+	project, err := s.Access.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	owner, repo, err := ParseGitHubRepo(project.RepoURL, s.repoHosts()...)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.client(actor)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	issues, err := fetchIssues(ctx, client, owner, repo, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.IssueImportResult{Created: []models.Task{}, Updated: []models.Task{}}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				result.Skipped++
+				continue
+			}
+			if err := importIssue(tx, project.ID, issue, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *IssueImportService) client(actor *models.User) (*github.Client, error) {
+	client := github.NewClient(s.HTTPClient)
+	if actor.GitHubAccessToken != nil && *actor.GitHubAccessToken != "" {
+		client = client.WithAuthToken(*actor.GitHubAccessToken)
+	}
+	return withGitHubAPIURL(client, s.APIURL)
+}
+
+// withGitHubAPIURL points client at apiURL, such as a GitHub Enterprise API
+// root. An empty apiURL keeps api.github.com.
+func withGitHubAPIURL(client *github.Client, apiURL string) (*github.Client, error) {
+	if apiURL == "" {
+		return client, nil
+	}
+	base, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL %q: %w", apiURL, err)
+	}
+	client.BaseURL = base
+	return client, nil
+}
+
+// fetchIssues pages through the repository's open issues with all of labels.
+func fetchIssues(ctx context.Context, client *github.Client, owner, repo string, labels []string) ([]*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      labels,
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var issues []*github.Issue
+	for {
+		page, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: listing issues of %s/%s: %v", ErrGitHubRequest, owner, repo, err)
+		}
+		issues = append(issues, page...)
+		if len(issues) > maxImportedIssues {
+			return nil, ErrTooManyIssues
+		}
+		if resp.NextPage == 0 {
+			return issues, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// importIssue creates the task for issue, or brings the existing one up to
+// date, and records which it did in result.
+func importIssue(tx *gorm.DB, projectID uint, issue *github.Issue, result *models.IssueImportResult) error {
+	number := issue.GetNumber()
+	labels := []string{}
+	for _, label := range issue.Labels {
+		if name := label.GetName(); name != "" {
+			labels = append(labels, name)
+		}
+	}
+	difficulty := models.DifficultyFromLabels(labels)
+
+	var task models.Task
+	err := tx.Unscoped().Where("project_id = ? AND github_issue_number = ?", projectID, number).First(&task).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		task = models.Task{
+			ProjectID:         projectID,
+			Title:             issue.GetTitle(),
+			Description:       issue.GetBody(),
+			DifficultyLevel:   difficulty,
+			Tags:              labels,
+			SkillsRequired:    []string{},
+			Status:            models.TaskStatusOpen,
+			ClaimPolicy:       models.ClaimPolicyExclusive,
+			GitHubIssueNumber: &number,
+			GitHubIssueURL:    issue.GetHTMLURL(),
+		}
+		if task.DifficultyLevel == "" {
+			task.DifficultyLevel = "easy"
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return fmt.Errorf("failed to create task for issue #%d: %w", number, err)
+		}
+		result.Created = append(result.Created, task)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up task for issue #%d: %w", number, err)
+	}
+	if task.DeletedAt.Valid {
+		result.Skipped++
+		return nil
+	}
+
+	updates := map[string]interface{}{}
+	if task.Title != issue.GetTitle() {
+		updates["title"] = issue.GetTitle()
+	}
+	if task.Description != issue.GetBody() {
+		updates["description"] = issue.GetBody()
+	}
+	if !slices.Equal([]string(task.Tags), labels) {
+		updates["tags"] = models.JSONStringSlice(labels)
+	}
+	if difficulty != "" && task.DifficultyLevel != difficulty {
+		updates["difficulty_level"] = difficulty
+	}
+	if task.GitHubIssueURL != issue.GetHTMLURL() {
+		updates["github_issue_url"] = issue.GetHTMLURL()
+	}
+	if len(updates) == 0 {
+		result.Unchanged++
+		return nil
+	}
+	if err := tx.Model(&task).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update task %d from issue #%d: %w", task.ID, number, err)
+	}
+	if err := tx.First(&task, task.ID).Error; err != nil {
+		return fmt.Errorf("failed to reload task %d: %w", task.ID, err)
+	}
+	result.Updated = append(result.Updated, task)
+	return nil
+}
+
+// repoHosts lists the hosts project repositories may live on: github.com,
+// plus the web host of the GitHub Enterprise install APIURL points at. An
+// Enterprise API root such as https://ghe.example.com/api/v3 serves the
+// repositories of https://ghe.example.com.
+func (s *IssueImportService) repoHosts() []string {
+	hosts := []string{"github.com", "www.github.com"}
+	if s.APIURL == "" {
+		return hosts
+	}
+	u, err := url.Parse(s.APIURL)
+	if err != nil || u.Host == "" || u.Host == "api.github.com" {
+		return hosts
+	}
+	return append(hosts, strings.TrimPrefix(u.Host, "api."))
+}
+
+// ParseGitHubRepo extracts the owner and repository name from a repository
+// URL on one of hosts, in https, ssh or bare "host/owner/repo" form. No hosts
+// means github.com.
+func ParseGitHubRepo(repoURL string, hosts ...string) (owner, repo string, err error) {
+	if len(hosts) == 0 {
+		hosts = []string{"github.com", "www.github.com"}
+	}
+	path := strings.TrimSpace(repoURL)
+	sshPath := ""
+	for _, host := range hosts {
+		if rest, ok := strings.CutPrefix(path, "git@"+host+":"); ok {
+			sshPath = rest
+			break
+		}
+	}
+	if sshPath != "" {
+		path = sshPath
+	} else {
+		if !strings.Contains(path, "://") {
+			path = "https://" + path
+		}
+		u, parseErr := url.Parse(path)
+		if parseErr != nil || !slices.Contains(hosts, u.Host) {
+			return "", "", fmt.Errorf("%w: %q", ErrNotGitHubRepo, repoURL)
+		}
+		path = u.Path
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrNotGitHubRepo, repoURL)
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), nil
+}
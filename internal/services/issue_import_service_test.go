@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"github.com/google/go-github/v66/github"
+)
+
+// fakeGitHub serves the issues of ossyne/widgets under a GitHub Enterprise
+// style /api/v3 root, pageSize at a time.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	issues   []*github.Issue
+	pageSize int
+	status   int
+
+	authHeaders []string
+	labels      []string
+}
+
+const fakeIssuesPath = "/api/v3/repos/ossyne/widgets/issues"
+
+func newFakeGitHub(t *testing.T, issues ...*github.Issue) (*fakeGitHub, *httptest.Server) {
+	t.Helper()
+	fake := &fakeGitHub{issues: issues, pageSize: 2}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != fakeIssuesPath {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		return
+	}
+	f.authHeaders = append(f.authHeaders, r.Header.Get("Authorization"))
+	f.labels = append(f.labels, r.URL.Query().Get("labels"))
+	if f.status != 0 {
+		http.Error(w, `{"message":"Server Error"}`, f.status)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	start := min((page-1)*f.pageSize, len(f.issues))
+	end := min(start+f.pageSize, len(f.issues))
+	if end < len(f.issues) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.String()))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(f.issues[start:end])
+}
+
+func fakeIssue(number int, title string, labels ...string) *github.Issue {
+	issue := &github.Issue{
+		Number:  github.Int(number),
+		Title:   github.String(title),
+		Body:    github.String(title + " body"),
+		HTMLURL: github.String(fmt.Sprintf("https://ghe.example.com/ossyne/widgets/issues/%d", number)),
+	}
+	for _, label := range labels {
+		issue.Labels = append(issue.Labels, &github.Label{Name: github.String(label)})
+	}
+	return issue
+}
+
+func fakePullRequest(number int, title string) *github.Issue {
+	issue := fakeIssue(number, title)
+	issue.PullRequestLinks = &github.PullRequestLinks{URL: github.String("https://ghe.example.com/pulls/1")}
+	return issue
+}
+
+func TestParseGitHubRepo(t *testing.T) {
+	tests := []struct {
+		name      string
+		repoURL   string
+		hosts     []string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{name: "https", repoURL: "https://github.com/ossyne/widgets", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "https with .git", repoURL: "https://github.com/ossyne/widgets.git", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "trailing slash", repoURL: "https://github.com/ossyne/widgets/", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "www", repoURL: "https://www.github.com/ossyne/widgets", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "bare", repoURL: "github.com/ossyne/widgets", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "ssh", repoURL: "git@github.com:ossyne/widgets.git", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "surrounding spaces", repoURL: "  https://github.com/ossyne/widgets ", wantOwner: "ossyne", wantRepo: "widgets"},
+		{name: "other host", repoURL: "https://gitlab.com/ossyne/widgets", wantErr: true},
+		{name: "enterprise host not configured", repoURL: "https://ghe.example.com/ossyne/widgets", wantErr: true},
+		{
+			name:      "enterprise host",
+			repoURL:   "https://ghe.example.com/ossyne/widgets",
+			hosts:     []string{"github.com", "ghe.example.com"},
+			wantOwner: "ossyne", wantRepo: "widgets",
+		},
+		{
+			name:      "enterprise ssh",
+			repoURL:   "git@ghe.example.com:ossyne/widgets.git",
+			hosts:     []string{"github.com", "ghe.example.com"},
+			wantOwner: "ossyne", wantRepo: "widgets",
+		},
+		{name: "owner only", repoURL: "https://github.com/ossyne", wantErr: true},
+		{name: "deeper path", repoURL: "https://github.com/ossyne/widgets/issues", wantErr: true},
+		{name: "empty", repoURL: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, repo, err := ParseGitHubRepo(tt.repoURL, tt.hosts...)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotGitHubRepo) {
+					t.Fatalf("ParseGitHubRepo(%q) error = %v, want %v", tt.repoURL, err, ErrNotGitHubRepo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGitHubRepo(%q): %v", tt.repoURL, err)
+			}
+			if owner != tt.wantOwner || repo != tt.wantRepo {
+				t.Errorf("ParseGitHubRepo(%q) = %s/%s, want %s/%s", tt.repoURL, owner, repo, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestIssueImportRepoHosts(t *testing.T) {
+	tests := []struct {
+		apiURL    string
+		wantExtra string
+	}{
+		{apiURL: ""},
+		{apiURL: "https://api.github.com/"},
+		{apiURL: "https://ghe.example.com/api/v3", wantExtra: "ghe.example.com"},
+		{apiURL: "https://api.ghe.example.com/", wantExtra: "ghe.example.com"},
+		{apiURL: "http://127.0.0.1:8443/api/v3", wantExtra: "127.0.0.1:8443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.apiURL, func(t *testing.T) {
+			hosts := (&IssueImportService{APIURL: tt.apiURL}).repoHosts()
+			want := []string{"github.com", "www.github.com"}
+			if tt.wantExtra != "" {
+				want = append(want, tt.wantExtra)
+			}
+			if strings.Join(hosts, ",") != strings.Join(want, ",") {
+				t.Errorf("repoHosts() = %v, want %v", hosts, want)
+			}
+		})
+	}
+}
+
+func TestFetchIssues(t *testing.T) {
+	token := "gho_user_token"
+	tests := []struct {
+		name       string
+		issues     int
+		status     int
+		labels     []string
+		actor      *models.User
+		wantIssues int
+		wantPages  int
+		wantAuth   string
+		wantErr    error
+	}{
+		{name: "no issues", actor: &models.User{}, wantPages: 1},
+		{name: "one page", issues: 2, actor: &models.User{}, wantIssues: 2, wantPages: 1},
+		{name: "several pages", issues: 5, actor: &models.User{}, wantIssues: 5, wantPages: 3},
+		{
+			name: "labels and token", issues: 1, labels: []string{"good first issue", "help wanted"},
+			actor: &models.User{GitHubAccessToken: &token}, wantIssues: 1, wantPages: 1, wantAuth: "Bearer " + token,
+		},
+		{name: "server error", status: http.StatusBadGateway, actor: &models.User{}, wantPages: 1, wantErr: ErrGitHubRequest},
+		{name: "too many issues", issues: maxImportedIssues + 1, actor: &models.User{}, wantErr: ErrTooManyIssues},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var issues []*github.Issue
+			for i := 1; i <= tt.issues; i++ {
+				issues = append(issues, fakeIssue(i, fmt.Sprintf("Issue %d", i)))
+			}
+			fake, server := newFakeGitHub(t, issues...)
+			fake.status = tt.status
+			if tt.issues > maxImportedIssues {
+				fake.pageSize = 100
+			}
+
+			service := &IssueImportService{APIURL: server.URL + "/api/v3", HTTPClient: server.Client()}
+			client, err := service.client(tt.actor)
+			if err != nil {
+				t.Fatalf("client: %v", err)
+			}
+			got, err := fetchIssues(context.Background(), client, "ossyne", "widgets", tt.labels)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("fetchIssues() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != tt.wantIssues {
+				t.Errorf("fetchIssues() returned %d issues, want %d", len(got), tt.wantIssues)
+			}
+			if len(fake.authHeaders) != tt.wantPages {
+				t.Errorf("fetched %d pages, want %d", len(fake.authHeaders), tt.wantPages)
+			}
+			for i, auth := range fake.authHeaders {
+				if auth != tt.wantAuth {
+					t.Errorf("page %d Authorization = %q, want %q", i+1, auth, tt.wantAuth)
+				}
+				if labels := strings.Join(tt.labels, ","); fake.labels[i] != labels {
+					t.Errorf("page %d labels = %q, want %q", i+1, fake.labels[i], labels)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueImport(t *testing.T) {
+	useTestDB(t)
+	fake, server := newFakeGitHub(t,
+		fakeIssue(1, "Fix the parser", "good first issue"),
+		fakePullRequest(2, "Refactor the lexer"),
+		fakeIssue(3, "Speed up builds", "difficulty: hard", "performance"),
+	)
+	service := NewIssueImportService(NewProjectAccessService(), server.URL+"/api/v3")
+	service.HTTPClient = server.Client()
+
+	owner := createTestUser(t, "owner")
+	stranger := createTestUser(t, "stranger")
+	host := strings.TrimPrefix(server.URL, "http://")
+	project := models.Project{OwnerID: owner.ID, Title: "Widgets", RepoURL: "http://" + host + "/ossyne/widgets"}
+	mustCreate(t, &project)
+	elsewhere := models.Project{OwnerID: owner.ID, Title: "Elsewhere", RepoURL: "https://gitlab.com/ossyne/widgets"}
+	mustCreate(t, &elsewhere)
+
+	tests := []struct {
+		name          string
+		actor         *models.User
+		projectID     uint
+		before        func()
+		wantErr       error
+		wantCreated   []string
+		wantUpdated   []string
+		wantUnchanged int
+		wantSkipped   int
+	}{
+		{
+			name: "first import", actor: owner, projectID: project.ID,
+			wantCreated: []string{"Fix the parser", "Speed up builds"}, wantSkipped: 1,
+		},
+		{
+			name: "nothing changed", actor: owner, projectID: project.ID,
+			wantUnchanged: 2, wantSkipped: 1,
+		},
+		{
+			name: "issue renamed", actor: owner, projectID: project.ID,
+			before:      func() { fake.issues[0].Title = github.String("Fix the parser crash") },
+			wantUpdated: []string{"Fix the parser crash"}, wantUnchanged: 1, wantSkipped: 1,
+		},
+		{name: "not a maintainer", actor: stranger, projectID: project.ID, wantErr: ErrNotProjectMaintainer},
+		{name: "repository not on GitHub", actor: owner, projectID: elsewhere.ID, wantErr: ErrNotGitHubRepo},
+	}
+	// Each import starts from the tasks the previous ones left behind.
+	for _, tt := range tests {
+		if !t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			result, err := service.Import(tt.actor, tt.projectID, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := taskTitles(result.Created); got != strings.Join(tt.wantCreated, ",") {
+				t.Errorf("created %q, want %q", got, strings.Join(tt.wantCreated, ","))
+			}
+			if got := taskTitles(result.Updated); got != strings.Join(tt.wantUpdated, ",") {
+				t.Errorf("updated %q, want %q", got, strings.Join(tt.wantUpdated, ","))
+			}
+			if result.Unchanged != tt.wantUnchanged || result.Skipped != tt.wantSkipped {
+				t.Errorf("unchanged %d and skipped %d, want %d and %d", result.Unchanged, result.Skipped, tt.wantUnchanged, tt.wantSkipped)
+			}
+		}) {
+			return
+		}
+	}
+
+	var tasks []models.Task
+	if err := db.DB.Where("project_id = ?", project.ID).Order("github_issue_number").Find(&tasks).Error; err != nil {
+		t.Fatalf("failed to load imported tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("project has %d tasks, want 2", len(tasks))
+	}
+	if tasks[1].DifficultyLevel != "hard" || tasks[1].Status != models.TaskStatusOpen || tasks[1].BountyAmount != 0 {
+		t.Errorf("issue #3 imported as %s task with status %s and bounty %.2f, want an open hard task without bounty",
+			tasks[1].DifficultyLevel, tasks[1].Status, tasks[1].BountyAmount)
+	}
+}
+
+func taskTitles(tasks []models.Task) string {
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return strings.Join(titles, ",")
+}
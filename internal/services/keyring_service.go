@@ -1,43 +1,109 @@
 package services
 
 import (
+	"errors"
 	"fmt"
-	"github.com/zalando/go-keyring"
+	"ossyne/internal/config"
 )
 
 const (
-	keyringService = "ossyne-cli"
-	keyringUser    = "github_access_token"
+	keyringService     = "ossyne-cli"
+	keyringUser        = "session_token"
+	keyringRefreshUser = "refresh_token"
+	// keyringLegacyUser held the raw GitHub access token before the server
+	// issued its own sessions. The server no longer accepts it, so it is never
+	// read, only removed when the user logs in or out again.
+	keyringLegacyUser = "github_access_token"
 )
 
-type KeyringService struct{}
+var ErrNotLoggedIn = errors.New("not logged in. Please run 'osm auth login'")
+
+// KeyringService stores the session tokens of one CLI profile. The zero value
+// uses the default profile, whose entries have unprefixed names, and the
+// process-wide DefaultCredentialStore. Logins from before session tokens are
+// not carried over; those users must run 'osm auth login' again.
+type KeyringService struct {
+	Profile string
+	Store   CredentialStore
+}
+
+func (s *KeyringService) key(user string) string {
+	if s.Profile == "" || s.Profile == config.DefaultProfile {
+		return user
+	}
+	return s.Profile + "/" + user
+}
+
+func (s *KeyringService) store() CredentialStore {
+	if s.Store != nil {
+		return s.Store
+	}
+	return DefaultCredentialStore().Store
+}
+
+// Backend names the credential store in use, e.g. "os-keyring".
+func (s *KeyringService) Backend() string {
+	return s.store().Name()
+}
 
 func (s *KeyringService) SetToken(token string) error {
-	err := keyring.Set(keyringService, keyringUser, token)
+	err := s.store().Set(s.key(keyringUser), token)
 	if err != nil {
-		return fmt.Errorf("failed to store token in keyring: %w", err)
+		return fmt.Errorf("failed to store token in %s: %w", s.Backend(), err)
 	}
 	return nil
 }
 
 func (s *KeyringService) GetToken() (string, error) {
-	token, err := keyring.Get(keyringService, keyringUser)
+	token, err := s.store().Get(s.key(keyringUser))
 	if err != nil {
-		if err == keyring.ErrNotFound {
-			return "", fmt.Errorf("not logged in. Please run 'osm login --provider github'")
+		if errors.Is(err, ErrCredentialNotFound) {
+			return "", ErrNotLoggedIn
 		}
-		return "", fmt.Errorf("failed to retrieve token from keyring: %w", err)
+		return "", fmt.Errorf("failed to retrieve token from %s: %w", s.Backend(), err)
 	}
 	return token, nil
 }
 
-func (s *KeyringService) ClearToken() error {
-	err := keyring.Delete(keyringService, keyringUser)
+func (s *KeyringService) SetRefreshToken(token string) error {
+	err := s.store().Set(s.key(keyringRefreshUser), token)
+	if err != nil {
+		return fmt.Errorf("failed to store refresh token in %s: %w", s.Backend(), err)
+	}
+	return nil
+}
+
+func (s *KeyringService) GetRefreshToken() (string, error) {
+	token, err := s.store().Get(s.key(keyringRefreshUser))
 	if err != nil {
-		if err == keyring.ErrNotFound {
-			return nil
+		if errors.Is(err, ErrCredentialNotFound) {
+			return "", fmt.Errorf("no refresh token stored. Please run 'osm auth login'")
+		}
+		return "", fmt.Errorf("failed to retrieve refresh token from %s: %w", s.Backend(), err)
+	}
+	return token, nil
+}
+
+// SetSession stores both halves of a session token pair and drops any GitHub
+// token left by an older CLI.
+func (s *KeyringService) SetSession(token, refreshToken string) error {
+	if err := s.SetToken(token); err != nil {
+		return err
+	}
+	if err := s.store().Delete(s.key(keyringLegacyUser)); err != nil {
+		return fmt.Errorf("failed to clear old GitHub token from %s: %w", s.Backend(), err)
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	return s.SetRefreshToken(refreshToken)
+}
+
+func (s *KeyringService) ClearToken() error {
+	for _, user := range []string{keyringUser, keyringRefreshUser, keyringLegacyUser} {
+		if err := s.store().Delete(s.key(user)); err != nil {
+			return fmt.Errorf("failed to clear token from %s: %w", s.Backend(), err)
 		}
-		return fmt.Errorf("failed to clear token from keyring: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyringDropsLegacyGitHubToken(t *testing.T) {
+	t.Setenv(CredentialPassphraseEnvVar, "test-passphrase")
+	store := NewEncryptedFileStore(filepath.Join(t.TempDir(), "credentials.enc"))
+	if err := store.Set(keyringLegacyUser, "gho_old"); err != nil {
+		t.Fatalf("failed to store legacy token: %v", err)
+	}
+	keyring := &KeyringService{Store: store}
+
+	if _, err := keyring.GetToken(); !errors.Is(err, ErrNotLoggedIn) {
+		t.Fatalf("GetToken() with only a GitHub token error = %v, want %v", err, ErrNotLoggedIn)
+	}
+	if err := keyring.SetSession("access", "refresh"); err != nil {
+		t.Fatalf("SetSession: %v", err)
+	}
+	if token, err := keyring.GetToken(); err != nil || token != "access" {
+		t.Errorf("GetToken() = %q, %v, want access", token, err)
+	}
+	if _, err := store.Get(keyringLegacyUser); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("legacy GitHub token is still stored: %v", err)
+	}
+}
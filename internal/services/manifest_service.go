@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidManifest  = errors.New("invalid manifest")
+	ErrManifestConflict = errors.New("manifest has changes that cannot be applied")
+)
+
+// ManifestService keeps a project's tasks in line with the .ossyne.yml in its
+// repository. Plan compares the manifest with the server; Apply makes the
+// changes. Tasks the manifest declares are matched by key, managed tasks it
+// no longer declares are archived, and tasks without a key are left alone.
+type ManifestService struct {
+	Access *ProjectAccessService
+	Tasks  *TaskService
+}
+
+func NewManifestService(access *ProjectAccessService, tasks *TaskService) *ManifestService {
+	return &ManifestService{Access: access, Tasks: tasks}
+}
+
+// Plan lists the changes Apply would make, without making them.
+func (s *ManifestService) Plan(actor *models.User, projectID uint, manifest *models.ProjectManifest) (*models.ManifestPlan, error) {
+	project, err := s.Access.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	plan, _, err := s.plan(project, manifest)
+	return plan, err
+}
+
+// Apply brings the project in line with manifest and returns the plan it
+// carried out. Nothing is changed if any part of the plan conflicts. Task
+// rows are saved in one transaction; bounties then go through escrow and
+// removed tasks are archived one by one, so if one of those steps fails a new
+// plan shows what is left to do.
+func (s *ManifestService) Apply(actor *models.User, projectID uint, manifest *models.ProjectManifest) (*models.ManifestPlan, error) {
+	project, err := s.Access.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	plan, tasks, err := s.plan(project, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if conflicts := plan.Conflicts(); len(conflicts) > 0 {
+		return plan, fmt.Errorf("%w: %d task(s) in conflict", ErrManifestConflict, len(conflicts))
+	}
+	declared := map[string]*models.ManifestTask{}
+	for i := range manifest.Tasks {
+		declared[manifest.Tasks[i].Key] = &manifest.Tasks[i]
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if len(plan.Project) > 0 {
+			if err := tx.Model(project).Updates(projectUpdates(&manifest.Project)).Error; err != nil {
+				return fmt.Errorf("failed to update project %d: %w", project.ID, err)
+			}
+		}
+		for i := range plan.Tasks {
+			change := &plan.Tasks[i]
+			mt := declared[change.Key]
+			switch change.Action {
+			case models.ManifestActionCreate:
+				key := mt.Key
+				task := models.Task{
+					ProjectID:       project.ID,
+					Title:           mt.Title,
+					Description:     mt.Description,
+					DifficultyLevel: mt.Difficulty,
+					EstimatedHours:  mt.EstimatedHours,
+					Tags:            mt.Tags,
+					SkillsRequired:  mt.Skills,
+					Status:          models.TaskStatusOpen,
+					ClaimPolicy:     mt.ClaimPolicy,
+					MaxClaimants:    mt.MaxClaimants,
+					ManifestKey:     &key,
+				}
+				if err := tx.Create(&task).Error; err != nil {
+					return fmt.Errorf("failed to create task %q: %w", mt.Key, err)
+				}
+				change.TaskID = task.ID
+				tasks[mt.Key] = &task
+			case models.ManifestActionReopen, models.ManifestActionUpdate:
+				task := tasks[mt.Key]
+				if change.Action == models.ManifestActionReopen {
+					if err := s.Tasks.States.Transition(tx, task, models.TaskStatusOpen, &actor.ID, "Declared again in "+models.ManifestFileName); err != nil {
+						return err
+					}
+				}
+				if err := tx.Model(task).Updates(taskUpdates(mt)).Error; err != nil {
+					return fmt.Errorf("failed to update task %q: %w", mt.Key, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range plan.Tasks {
+		switch {
+		case change.Action == models.ManifestActionArchive:
+			if _, err := s.Tasks.Archive(change.TaskID, &actor.ID, "Removed from "+models.ManifestFileName); err != nil {
+				return plan, fmt.Errorf("failed to archive task %q: %w", change.Key, err)
+			}
+		case change.HasField("bounty"):
+			if err := s.setBounty(tasks[change.Key].ID, actor.ID, declared[change.Key].Bounty); err != nil {
+				return plan, fmt.Errorf("failed to set the bounty of task %q: %w", change.Key, err)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// plan compares the project with the manifest. Alongside the plan it
+// returns the existing tasks matched to manifest keys.
+func (s *ManifestService) plan(project *models.Project, manifest *models.ProjectManifest) (*models.ManifestPlan, map[string]*models.Task, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if manifest.Project.ID != 0 && manifest.Project.ID != project.ID {
+		return nil, nil, fmt.Errorf("%w: the manifest is for project %d, not %d", ErrInvalidManifest, manifest.Project.ID, project.ID)
+	}
+
+	var existing []models.Task
+	if err := db.DB.Where("project_id = ?", project.ID).Order("id").Find(&existing).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load tasks of project %d: %w", project.ID, err)
+	}
+	plan := &models.ManifestPlan{
+		ProjectID: project.ID,
+		Project:   manifest.Project.ProjectChanges(project),
+		Tasks:     []models.ManifestChange{},
+	}
+	byKey := map[string]*models.Task{}
+	byID := map[uint]*models.Task{}
+	for i := range existing {
+		task := &existing[i]
+		byID[task.ID] = task
+		if task.ManifestKey != nil {
+			byKey[*task.ManifestKey] = task
+		} else {
+			plan.Unmanaged++
+		}
+	}
+
+	matched := map[string]*models.Task{}
+	matchedIDs := map[uint]bool{}
+	for i := range manifest.Tasks {
+		mt := &manifest.Tasks[i]
+		change := models.ManifestChange{Key: mt.Key, Title: mt.Title}
+		task := byKey[mt.Key]
+		switch {
+		case task != nil && mt.ID != 0 && task.ID != mt.ID:
+			return nil, nil, fmt.Errorf("%w: task %q: the key belongs to task %d, not %d", ErrInvalidManifest, mt.Key, task.ID, mt.ID)
+		case task == nil && mt.ID != 0:
+			task = byID[mt.ID]
+			if task == nil {
+				return nil, nil, fmt.Errorf("%w: task %q: task %d is not in project %d", ErrInvalidManifest, mt.Key, mt.ID, project.ID)
+			}
+			if task.ManifestKey != nil {
+				return nil, nil, fmt.Errorf("%w: task %q: task %d is already managed as %q", ErrInvalidManifest, mt.Key, mt.ID, *task.ManifestKey)
+			}
+			plan.Unmanaged--
+			change.Fields = append(change.Fields, models.FieldChange{Field: "key", To: mt.Key})
+		}
+		if task == nil {
+			change.Action = models.ManifestActionCreate
+			change.Fields = mt.TaskChanges(nil)
+			plan.Tasks = append(plan.Tasks, change)
+			continue
+		}
+
+		matched[mt.Key] = task
+		matchedIDs[task.ID] = true
+		change.TaskID = task.ID
+		change.Fields = append(change.Fields, mt.TaskChanges(task)...)
+		switch {
+		case task.Status == models.TaskStatusArchived:
+			change.Action = models.ManifestActionReopen
+		case len(change.Fields) > 0:
+			change.Action = models.ManifestActionUpdate
+		default:
+			continue
+		}
+		change.Conflict = manifestConflict(task, &change)
+		plan.Tasks = append(plan.Tasks, change)
+	}
+
+	for i := range existing {
+		task := &existing[i]
+		if task.ManifestKey == nil || matchedIDs[task.ID] || task.Status == models.TaskStatusArchived {
+			continue
+		}
+		change := models.ManifestChange{
+			Action: models.ManifestActionArchive,
+			Key:    *task.ManifestKey,
+			TaskID: task.ID,
+			Title:  task.Title,
+		}
+		change.Conflict = manifestConflict(task, &change)
+		plan.Tasks = append(plan.Tasks, change)
+	}
+	return plan, matched, nil
+}
+
+// manifestConflict explains why change cannot be made to task, or returns ""
+// if it can. Tasks contributors are working on are not archived from under
+// them, and the claim policy and a bounty already set can only change while
+// the task is open, as with the task and bounty endpoints.
+func manifestConflict(task *models.Task, change *models.ManifestChange) string {
+	if change.Action == models.ManifestActionArchive {
+		switch task.Status {
+		case models.TaskStatusClaimed, models.TaskStatusInProgress, models.TaskStatusSubmitted:
+			return fmt.Sprintf("the task is %s; release its claims or finish it before removing it from %s", task.Status, models.ManifestFileName)
+		}
+		return ""
+	}
+	status := task.Status
+	if change.Action == models.ManifestActionReopen {
+		status = models.TaskStatusOpen
+	}
+	if status == models.TaskStatusOpen {
+		return ""
+	}
+	if change.HasField("claim_policy") || change.HasField("max_claimants") {
+		return fmt.Sprintf("the claim policy can only change while the task is open (current status: %s)", status)
+	}
+	if change.HasField("bounty") && task.BountyAmount > 0 {
+		return fmt.Sprintf("the bounty can only change while the task is open (current status: %s)", status)
+	}
+	return ""
+}
+
+// setBounty moves the task's bounty to amount: an escrowed bounty is
+// refunded and the new amount escrowed in its place.
+func (s *ManifestService) setBounty(taskID, funderID uint, amount float64) error {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	if task.BountyEscrowID != nil && *task.BountyEscrowID != "" {
+		if err := s.Tasks.Payments.RefundTaskBounty(task.ID, "Bounty changed in "+models.ManifestFileName); err != nil {
+			return err
+		}
+	} else if task.BountyAmount != 0 {
+		if err := db.DB.Model(&task).Update("bounty_amount", 0).Error; err != nil {
+			return fmt.Errorf("failed to clear bounty of task %d: %w", task.ID, err)
+		}
+	}
+	if amount > 0 {
+		return s.Tasks.Payments.FundTaskBounty(task.ID, funderID, amount, "USD")
+	}
+	return nil
+}
+
+// projectUpdates returns the project columns the manifest sets.
+func projectUpdates(p *models.ManifestProject) map[string]interface{} {
+	updates := map[string]interface{}{}
+	if p.Title != nil {
+		updates["title"] = *p.Title
+	}
+	if p.ShortDesc != nil {
+		updates["short_desc"] = *p.ShortDesc
+	}
+	if p.RepoURL != nil {
+		updates["repo_url"] = *p.RepoURL
+	}
+	if p.Tags != nil {
+		updates["tags"] = models.JSONStringSlice(*p.Tags)
+	}
+	if p.Visibility != nil {
+		updates["visibility"] = *p.Visibility
+	}
+	return updates
+}
+
+// taskUpdates returns the task columns the manifest sets. The bounty is left
+// to setBounty.
+func taskUpdates(t *models.ManifestTask) map[string]interface{} {
+	return map[string]interface{}{
+		"manifest_key":     t.Key,
+		"title":            t.Title,
+		"description":      t.Description,
+		"difficulty_level": t.Difficulty,
+		"estimated_hours":  t.EstimatedHours,
+		"tags":             models.JSONStringSlice(t.Tags),
+		"skills_required":  models.JSONStringSlice(t.Skills),
+		"claim_policy":     t.ClaimPolicy,
+		"max_claimants":    t.MaxClaimants,
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"time"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyVolunteered = errors.New("user already volunteered to mentor this task")
+	ErrNotVolunteer       = errors.New("user has not volunteered to mentor this task")
+	ErrSelfMentoring      = errors.New("contributors cannot mentor their own claim")
+	ErrMaintainerMentor   = errors.New("maintainers cannot mentor claims on their own project")
+	ErrClaimNotActive     = errors.New("claim is not pending or accepted")
+	ErrNotClaimMentor     = errors.New("only the claim's assigned mentor can endorse its contributor")
+	ErrAlreadyEndorsed    = errors.New("claim has already been endorsed")
+)
+
+const endorsementScore = 20
+
+// MentorService pairs contributors with mentors. Users volunteer to mentor a
+// task, maintainers assign a mentor to a claim, and the assigned mentor may
+// endorse the claim's contributor once.
+type MentorService struct {
+	Access *ProjectAccessService
+}
+
+func NewMentorService(access *ProjectAccessService) *MentorService {
+	return &MentorService{Access: access}
+}
+
+// Volunteer offers user as a mentor for a task that is not finished yet.
+func (s *MentorService) Volunteer(user *models.User, taskID uint, note string) (*models.MentorVolunteer, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	if task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusArchived {
+		return nil, fmt.Errorf("%w: task %d is %s", ErrTaskNotOpen, taskID, task.Status)
+	}
+
+	var existing int64
+	if err := db.DB.Model(&models.MentorVolunteer{}).
+		Where("task_id = ? AND user_id = ?", taskID, user.ID).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing volunteers: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("task %d: %w", taskID, ErrAlreadyVolunteered)
+	}
+
+	volunteer := models.MentorVolunteer{TaskID: taskID, UserID: user.ID, Note: note}
+	if err := db.DB.Omit("User").Create(&volunteer).Error; err != nil {
+		return nil, fmt.Errorf("failed to save mentor volunteer: %w", err)
+	}
+	volunteer.User = user
+	return &volunteer, nil
+}
+
+// Withdraw takes back user's offer to mentor a task. Claims the user was
+// already assigned to keep their mentor.
+func (s *MentorService) Withdraw(user *models.User, taskID uint) error {
+	result := db.DB.Unscoped().Where("task_id = ? AND user_id = ?", taskID, user.ID).Delete(&models.MentorVolunteer{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to withdraw mentor volunteer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task %d: %w", taskID, ErrNotVolunteer)
+	}
+	return nil
+}
+
+// Volunteers lists who offered to mentor a task, earliest first.
+func (s *MentorService) Volunteers(taskID uint) ([]models.MentorVolunteer, error) {
+	var volunteers []models.MentorVolunteer
+	if err := db.DB.Preload("User").Where("task_id = ?", taskID).Order("created_at, id").Find(&volunteers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list mentor volunteers of task %d: %w", taskID, err)
+	}
+	return volunteers, nil
+}
+
+// AssignMentor sets or changes the mentor of a pending or accepted claim. A
+// nil mentorID removes the mentor. Only maintainers of the task's project may
+// assign mentors. The mentor must have volunteered for the task and may not
+// maintain its project, since its endorsement earns the contributor reputation.
+func (s *MentorService) AssignMentor(actor *models.User, claimID uint, mentorID *uint) (*models.Claim, error) {
+	claim, err := s.Access.AuthorizeClaim(actor, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != models.ClaimStatusPending && claim.Status != models.ClaimStatusAccepted {
+		return nil, fmt.Errorf("claim %d is %s: %w", claimID, claim.Status, ErrClaimNotActive)
+	}
+	if mentorID != nil {
+		if *mentorID == claim.UserID {
+			return nil, fmt.Errorf("claim %d: %w", claimID, ErrSelfMentoring)
+		}
+		var mentor models.User
+		if err := db.DB.First(&mentor, *mentorID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("mentor %d: %w", *mentorID, ErrUserNotFound)
+			}
+			return nil, fmt.Errorf("failed to fetch user %d: %w", *mentorID, err)
+		}
+		if _, err := s.Access.AuthorizeTask(&mentor, claim.TaskID); err == nil {
+			return nil, fmt.Errorf("user %d: %w", mentor.ID, ErrMaintainerMentor)
+		} else if !errors.Is(err, ErrNotProjectMaintainer) {
+			return nil, err
+		}
+		var volunteered int64
+		if err := db.DB.Model(&models.MentorVolunteer{}).
+			Where("task_id = ? AND user_id = ?", claim.TaskID, mentor.ID).
+			Count(&volunteered).Error; err != nil {
+			return nil, fmt.Errorf("failed to check mentor volunteers: %w", err)
+		}
+		if volunteered == 0 {
+			return nil, fmt.Errorf("user %d on task %d: %w", mentor.ID, claim.TaskID, ErrNotVolunteer)
+		}
+	}
+
+	if err := db.DB.Model(claim).Update("mentor_id", mentorID).Error; err != nil {
+		return nil, fmt.Errorf("failed to assign mentor to claim %d: %w", claimID, err)
+	}
+	if err := db.DB.Preload("Mentor").First(claim, claimID).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload claim %d: %w", claimID, err)
+	}
+	return claim, nil
+}
+
+// Endorse lets the assigned mentor of an accepted claim vouch for its
+// contributor. The contributor gains reputation; each claim can be endorsed
+// once.
+func (s *MentorService) Endorse(mentor *models.User, claimID uint, notes string) (*models.Claim, error) {
+	var claim models.Claim
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&claim, claimID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("claim %d: %w", claimID, ErrClaimNotFound)
+			}
+			return fmt.Errorf("failed to fetch claim %d: %w", claimID, err)
+		}
+		if claim.MentorID == nil || *claim.MentorID != mentor.ID {
+			return fmt.Errorf("claim %d: %w", claimID, ErrNotClaimMentor)
+		}
+		if claim.Status != models.ClaimStatusAccepted {
+			return fmt.Errorf("claim %d is %s: %w", claimID, claim.Status, ErrClaimNotActive)
+		}
+
+		// Guard on endorsed_at so concurrent requests cannot endorse twice.
+		now := time.Now()
+		result := tx.Model(&models.Claim{}).
+			Where("id = ? AND endorsed_at IS NULL", claimID).
+			Update("endorsed_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark claim %d as endorsed: %w", claimID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("claim %d: %w", claimID, ErrAlreadyEndorsed)
+		}
+		claim.EndorsedAt = &now
+
+		if err := tx.Model(&models.User{}).Where("id = ?", claim.UserID).
+			Update("ratings", gorm.Expr("ratings + ?", endorsementScore)).Error; err != nil {
+			return fmt.Errorf("failed to update user ratings for endorsement: %w", err)
+		}
+		if err := tx.Create(&models.ReputationEventLog{
+			UserID:      claim.UserID,
+			EventType:   models.ReputationEventMentorEndorsement,
+			ScoreChange: endorsementScore,
+			RelatedID:   &claim.ID,
+			Notes:       notes,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to log mentor endorsement event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMilestoneNotFound        = errors.New("milestone not found")
+	ErrMilestoneProjectMismatch = errors.New("milestone belongs to a different project")
+	ErrNoFundableTasks          = errors.New("milestone has no tasks without a bounty")
+)
+
+// MilestoneService groups tasks of a project into milestones and epics and
+// reports how far along each one is.
+type MilestoneService struct {
+	Payments *PaymentService
+}
+
+func NewMilestoneService(payments *PaymentService) *MilestoneService {
+	return &MilestoneService{Payments: payments}
+}
+
+func (s *MilestoneService) Create(milestone *models.Milestone) error {
+	if milestone.Kind == "" {
+		milestone.Kind = models.MilestoneKindMilestone
+	}
+	if err := db.DB.Create(milestone).Error; err != nil {
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+	return nil
+}
+
+// Get loads a milestone with its progress.
+func (s *MilestoneService) Get(milestoneID uint) (*models.Milestone, error) {
+	var milestone models.Milestone
+	if err := db.DB.First(&milestone, milestoneID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("milestone %d: %w", milestoneID, ErrMilestoneNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch milestone %d: %w", milestoneID, err)
+	}
+	milestones := []models.Milestone{milestone}
+	if err := fillProgress(milestones); err != nil {
+		return nil, err
+	}
+	return &milestones[0], nil
+}
+
+// List returns the milestones of a project, or of every live project when
+// projectID is 0, ordered by due date with undated ones last.
+func (s *MilestoneService) List(projectID uint) ([]models.Milestone, error) {
+	query := db.DB.Where("project_id IN (?)", db.DB.Model(&models.Project{}).Select("id"))
+	if projectID != 0 {
+		query = query.Where("project_id = ?", projectID)
+	}
+	var milestones []models.Milestone
+	if err := query.Order("due_date IS NULL, due_date, id").Find(&milestones).Error; err != nil {
+		return nil, fmt.Errorf("failed to list milestones: %w", err)
+	}
+	if err := fillProgress(milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+// Tasks returns the tasks assigned to a milestone, archived ones included.
+func (s *MilestoneService) Tasks(milestoneID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	if err := db.DB.Where("milestone_id = ?", milestoneID).Order("id").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tasks of milestone %d: %w", milestoneID, err)
+	}
+	return tasks, nil
+}
+
+// Update applies updates to a milestone. Keys are column names.
+func (s *MilestoneService) Update(milestone *models.Milestone, updates map[string]interface{}) error {
+	if err := db.DB.Model(milestone).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update milestone %d: %w", milestone.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a milestone. Its tasks stay in the project, unassigned.
+func (s *MilestoneService) Delete(milestoneID uint) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("milestone_id = ?", milestoneID).Update("milestone_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to unassign tasks: %w", err)
+		}
+		if err := tx.Delete(&models.Milestone{}, milestoneID).Error; err != nil {
+			return fmt.Errorf("failed to delete milestone %d: %w", milestoneID, err)
+		}
+		return nil
+	})
+}
+
+// CheckAssignable returns ErrMilestoneProjectMismatch unless the milestone
+// exists in the given project.
+func (s *MilestoneService) CheckAssignable(milestoneID, projectID uint) error {
+	var milestone models.Milestone
+	if err := db.DB.First(&milestone, milestoneID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("milestone %d: %w", milestoneID, ErrMilestoneNotFound)
+		}
+		return fmt.Errorf("failed to fetch milestone %d: %w", milestoneID, err)
+	}
+	if milestone.ProjectID != projectID {
+		return fmt.Errorf("%w: milestone %d is in project %d, not %d", ErrMilestoneProjectMismatch, milestoneID, milestone.ProjectID, projectID)
+	}
+	return nil
+}
+
+// AssignTask moves task into the milestone, or out of any milestone when
+// milestoneID is nil.
+func (s *MilestoneService) AssignTask(task *models.Task, milestoneID *uint) error {
+	if milestoneID != nil {
+		if err := s.CheckAssignable(*milestoneID, task.ProjectID); err != nil {
+			return err
+		}
+	}
+	if err := db.DB.Model(task).Update("milestone_id", milestoneID).Error; err != nil {
+		return fmt.Errorf("failed to assign task %d: %w", task.ID, err)
+	}
+	task.MilestoneID = milestoneID
+	return nil
+}
+
+// FundBounty splits amount evenly across the milestone's unfinished tasks that
+// have no bounty yet and escrows each share as that task's bounty, so every
+// task still pays out on its own. Cents left over from the split go to the
+// first tasks. If funding a task fails, the shares already escrowed are
+// refunded.
+func (s *MilestoneService) FundBounty(milestoneID, funderID uint, amount float64, currency string) ([]models.Task, error) {
+	if _, err := s.Get(milestoneID); err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	if err := db.DB.Where("milestone_id = ? AND status NOT IN ? AND (bounty_escrow_id IS NULL OR bounty_escrow_id = '')",
+		milestoneID, []string{models.TaskStatusCompleted, models.TaskStatusArchived}).
+		Order("id").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tasks of milestone %d: %w", milestoneID, err)
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("milestone %d: %w", milestoneID, ErrNoFundableTasks)
+	}
+
+	cents := int64(amount*100 + 0.5)
+	share := cents / int64(len(tasks))
+	leftover := cents % int64(len(tasks))
+	if share == 0 {
+		return nil, fmt.Errorf("%.2f %s is too little to split across %d tasks", amount, currency, len(tasks))
+	}
+
+	var funded []uint
+	for i := range tasks {
+		taskCents := share
+		if int64(i) < leftover {
+			taskCents++
+		}
+		if err := s.Payments.FundTaskBounty(tasks[i].ID, funderID, float64(taskCents)/100, currency); err != nil {
+			for _, taskID := range funded {
+				if refundErr := s.Payments.RefundTaskBounty(taskID, "Milestone funding failed"); refundErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to refund task %d: %w", taskID, refundErr))
+				}
+			}
+			return nil, fmt.Errorf("failed to fund task %d: %w", tasks[i].ID, err)
+		}
+		funded = append(funded, tasks[i].ID)
+	}
+
+	if err := db.DB.Where("id IN ?", funded).Order("id").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload funded tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// fillProgress computes Progress for every milestone with one query.
+func fillProgress(milestones []models.Milestone) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(milestones))
+	for i := range milestones {
+		ids[i] = milestones[i].ID
+	}
+	var rows []struct {
+		MilestoneID uint
+		Status      string
+		Count       int
+		Bounty      float64
+	}
+	if err := db.DB.Model(&models.Task{}).
+		Select("milestone_id, status, COUNT(*) AS count, COALESCE(SUM(bounty_amount), 0) AS bounty").
+		Where("milestone_id IN ?", ids).
+		Group("milestone_id, status").
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("failed to compute milestone progress: %w", err)
+	}
+	byID := map[uint]*models.Milestone{}
+	for i := range milestones {
+		milestones[i].Progress = models.MilestoneProgress{}
+		byID[milestones[i].ID] = &milestones[i]
+	}
+	for _, row := range rows {
+		if milestone, ok := byID[row.MilestoneID]; ok {
+			milestone.Progress.AddTasks(row.Status, row.Count, row.Bounty)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"time"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+var ErrInvalidOAuthState = errors.New("invalid or expired OAuth state")
+
+// OAuthStateService issues and verifies the per-login state values (and the
+// PKCE verifier bound to each one) used by the GitHub OAuth flow.
+type OAuthStateService struct {
+	TTL time.Duration
+}
+
+func NewOAuthStateService() *OAuthStateService {
+	return &OAuthStateService{TTL: oauthStateTTL}
+}
+
+// Issue creates a fresh random state and PKCE verifier and stores them
+// server-side until they are consumed or expire. deviceAuthorizationID links
+// the login to a pending device authorization, if any; redirectURI is where
+// the CLI waits for the session token otherwise, and clientState is echoed
+// back to it so the CLI can tell its own login from a forged callback.
+func (s *OAuthStateService) Issue(deviceAuthorizationID *uint, redirectURI, clientState string) (*models.OAuthState, error) {
+	state, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	record := models.OAuthState{
+		State:                 state,
+		CodeVerifier:          oauth2.GenerateVerifier(),
+		ExpiresAt:             time.Now().Add(s.TTL),
+		DeviceAuthorizationID: deviceAuthorizationID,
+		RedirectURI:           redirectURI,
+		ClientState:           clientState,
+	}
+	if err := db.DB.Create(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to store OAuth state: %w", err)
+	}
+	return &record, nil
+}
+
+// Consume looks up the given state, deletes it so it cannot be replayed and
+// returns the stored record. Unknown, expired or already used states yield
+// ErrInvalidOAuthState.
+func (s *OAuthStateService) Consume(state string) (*models.OAuthState, error) {
+	if state == "" {
+		return nil, ErrInvalidOAuthState
+	}
+	var record models.OAuthState
+	if err := db.DB.Where("state = ?", state).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOAuthState
+		}
+		return nil, fmt.Errorf("failed to look up OAuth state: %w", err)
+	}
+	result := db.DB.Unscoped().Where("id = ?", record.ID).Delete(&models.OAuthState{})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to consume OAuth state: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidOAuthState
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, ErrInvalidOAuthState
+	}
+	return &record, nil
+}
+
+// PruneExpired removes states that were issued but never completed.
+func (s *OAuthStateService) PruneExpired() error {
+	return db.DB.Unscoped().Where("expires_at < ?", time.Now()).Delete(&models.OAuthState{}).Error
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
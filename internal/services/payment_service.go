@@ -4,6 +4,7 @@ import (
 	"ossyne/internal/db"
 	"ossyne/internal/models"
 	"time"
+	"gorm.io/gorm"
 )
 
 type PaymentService struct {
@@ -110,6 +111,14 @@ func (s *PaymentService) ReleaseBountyToContributor(contributionID uint) error {
 		return fmt.Errorf("bounty for contribution %d has already been released (payment ID: %d)", contributionID, *contribution.PaymentID)
 	}
 
+	// A task pays out once, even when its claim policy lets several
+	// contributors compete for it.
+	var winner models.Contribution
+	if err := tx.Where("task_id = ? AND id <> ? AND payment_id IS NOT NULL AND payment_id <> 0", contribution.TaskID, contribution.ID).First(&winner).Error; err == nil {
+		tx.Rollback()
+		return fmt.Errorf("bounty for task %d has already been released to contribution %d", contribution.TaskID, winner.ID)
+	}
+
 	transactionID, err := s.PaymentGateway.ReleaseEscrow(
 		*contribution.Task.BountyEscrowID,
 		contribution.Task.BountyAmount,
@@ -154,54 +163,44 @@ func (s *PaymentService) ReleaseBountyToContributor(contributionID uint) error {
 }
 
 func (s *PaymentService) RefundTaskBounty(taskID uint, reason string) error {
-	tx := db.DB.Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to start transaction: %w", tx.Error)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.First(&task, taskID).Error; err != nil {
+			return fmt.Errorf("task with ID %d not found: %w", taskID, err)
 		}
-	}()
-
-	var task models.Task
-	if err := tx.First(&task, taskID).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("task with ID %d not found: %w", taskID, err)
-	}
+		return s.RefundTaskBountyTx(tx, &task)
+	})
+}
 
+// RefundTaskBountyTx refunds task's escrowed bounty as part of tx. The gateway
+// refund cannot be rolled back, so callers should make it the last step
+// before committing.
+func (s *PaymentService) RefundTaskBountyTx(tx *gorm.DB, task *models.Task) error {
 	if task.BountyEscrowID == nil || *task.BountyEscrowID == "" {
-		tx.Rollback()
-		return fmt.Errorf("task %d has no escrowed bounty to refund", taskID)
-	}
-
-	if err := s.PaymentGateway.RefundEscrow(*task.BountyEscrowID, task.BountyAmount, "USD"); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to refund escrowed funds: %w", err)
+		return fmt.Errorf("task %d has no escrowed bounty to refund", task.ID)
 	}
 
 	var escrowPayment models.Payment
 	if err := tx.Where("transaction_id = ? AND type = ?", *task.BountyEscrowID, models.PaymentTypeEscrowDeposit).First(&escrowPayment).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("escrow payment record for task %d not found: %w", taskID, err)
+		return fmt.Errorf("escrow payment record for task %d not found: %w", task.ID, err)
 	}
 	escrowPayment.Status = models.PaymentStatusRefunded
 	if err := tx.Save(&escrowPayment).Error; err != nil {
-		tx.Rollback()
 		return fmt.Errorf("failed to update escrow payment status to refunded: %w", err)
 	}
 
-	task.BountyAmount = 0.00
-	task.BountyEscrowID = nil
-	if err := tx.Save(&task).Error; err != nil {
-		tx.Rollback()
+	escrowID := *task.BountyEscrowID
+	amount := task.BountyAmount
+	if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).
+		Updates(map[string]interface{}{"bounty_amount": 0, "bounty_escrow_id": nil}).Error; err != nil {
 		return fmt.Errorf("failed to clear task bounty details: %w", err)
 	}
+	task.BountyAmount = 0.00
+	task.BountyEscrowID = nil
 
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := s.PaymentGateway.RefundEscrow(escrowID, amount, "USD"); err != nil {
+		return fmt.Errorf("failed to refund escrowed funds: %w", err)
 	}
-
 	return nil
 }
 
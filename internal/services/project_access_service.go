@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrContributionNotFound = errors.New("contribution not found")
+	ErrNotProjectMaintainer = errors.New("only the project owner or a co-maintainer can do this")
+	ErrAlreadyMaintainer    = errors.New("user already maintains this project")
+	ErrMaintainerNotFound   = errors.New("user is not a co-maintainer of this project")
+)
+
+// ProjectAccessService decides who may act as a maintainer of a project: its
+// owner and its co-maintainers. The global admin role does not count; admins
+// who need to act on a project are added as co-maintainers like anyone else.
+type ProjectAccessService struct{}
+
+func NewProjectAccessService() *ProjectAccessService {
+	return &ProjectAccessService{}
+}
+
+// AuthorizeProject returns the project if user may maintain it.
+func (s *ProjectAccessService) AuthorizeProject(user *models.User, projectID uint) (*models.Project, error) {
+	var project models.Project
+	if err := db.DB.First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project %d: %w", projectID, ErrProjectNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch project %d: %w", projectID, err)
+	}
+	ok, err := s.canMaintain(user, &project)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("project %d: %w", projectID, ErrNotProjectMaintainer)
+	}
+	return &project, nil
+}
+
+// AuthorizeDeletedProject returns a soft-deleted project if user may maintain
+// it, so it can be restored.
+func (s *ProjectAccessService) AuthorizeDeletedProject(user *models.User, projectID uint) (*models.Project, error) {
+	var project models.Project
+	if err := db.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("deleted project %d: %w", projectID, ErrProjectNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch project %d: %w", projectID, err)
+	}
+	ok, err := s.canMaintain(user, &project)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("project %d: %w", projectID, ErrNotProjectMaintainer)
+	}
+	return &project, nil
+}
+
+// AuthorizeTask returns the task if user may maintain the project it belongs to.
+func (s *ProjectAccessService) AuthorizeTask(user *models.User, taskID uint) (*models.Task, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+	if _, err := s.AuthorizeProject(user, task.ProjectID); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// AuthorizeContribution returns the contribution if user may maintain the
+// project behind its task.
+func (s *ProjectAccessService) AuthorizeContribution(user *models.User, contributionID uint) (*models.Contribution, error) {
+	var contribution models.Contribution
+	if err := db.DB.First(&contribution, contributionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("contribution %d: %w", contributionID, ErrContributionNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch contribution %d: %w", contributionID, err)
+	}
+	if _, err := s.AuthorizeTask(user, contribution.TaskID); err != nil {
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+// AuthorizeClaim returns the claim if user may maintain the project behind
+// its task.
+func (s *ProjectAccessService) AuthorizeClaim(user *models.User, claimID uint) (*models.Claim, error) {
+	var claim models.Claim
+	if err := db.DB.First(&claim, claimID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("claim %d: %w", claimID, ErrClaimNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch claim %d: %w", claimID, err)
+	}
+	if _, err := s.AuthorizeTask(user, claim.TaskID); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (s *ProjectAccessService) canMaintain(user *models.User, project *models.Project) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+	if project.OwnerID == user.ID {
+		return true, nil
+	}
+	var count int64
+	if err := db.DB.Model(&models.ProjectMaintainer{}).
+		Where("project_id = ? AND user_id = ?", project.ID, user.ID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check project maintainers: %w", err)
+	}
+	return count > 0, nil
+}
+
+// AddMaintainer makes userID a co-maintainer of the project. Only users who
+// already maintain the project may add others.
+func (s *ProjectAccessService) AddMaintainer(actor *models.User, projectID, userID uint) (*models.ProjectMaintainer, error) {
+	project, err := s.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d: %w", userID, ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch user %d: %w", userID, err)
+	}
+	if project.OwnerID == userID {
+		return nil, fmt.Errorf("user %d owns project %d: %w", userID, projectID, ErrAlreadyMaintainer)
+	}
+	var existing int64
+	if err := db.DB.Model(&models.ProjectMaintainer{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check project maintainers: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("user %d on project %d: %w", userID, projectID, ErrAlreadyMaintainer)
+	}
+
+	maintainer := models.ProjectMaintainer{
+		ProjectID: projectID,
+		UserID:    userID,
+		AddedByID: actor.ID,
+		User:      user,
+	}
+	if err := db.DB.Omit("User", "Project").Create(&maintainer).Error; err != nil {
+		return nil, fmt.Errorf("failed to add maintainer: %w", err)
+	}
+	return &maintainer, nil
+}
+
+// RemoveMaintainer drops userID from the project's co-maintainers.
+func (s *ProjectAccessService) RemoveMaintainer(actor *models.User, projectID, userID uint) error {
+	if _, err := s.AuthorizeProject(actor, projectID); err != nil {
+		return err
+	}
+	// Hard delete so the user can be added again later without tripping the
+	// unique (project_id, user_id) index.
+	result := db.DB.Unscoped().
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Delete(&models.ProjectMaintainer{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove maintainer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %d on project %d: %w", userID, projectID, ErrMaintainerNotFound)
+	}
+	return nil
+}
+
+func (s *ProjectAccessService) ListMaintainers(projectID uint) ([]models.ProjectMaintainer, error) {
+	var maintainers []models.ProjectMaintainer
+	if err := db.DB.Preload("User").Where("project_id = ?", projectID).Order("created_at").Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list maintainers: %w", err)
+	}
+	return maintainers, nil
+}
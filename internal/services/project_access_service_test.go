@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"ossyne/internal/db"
+	"ossyne/internal/db/dbtest"
+	"ossyne/internal/models"
+	"testing"
+)
+
+// accessFixture is a project with one task, one claim and one contribution,
+// and users standing in every relation to it.
+type accessFixture struct {
+	owner, coMaintainer, admin, stranger *models.User
+
+	project      models.Project
+	task         models.Task
+	claim        models.Claim
+	contribution models.Contribution
+}
+
+func newAccessFixture(t *testing.T) *accessFixture {
+	t.Helper()
+	f := &accessFixture{
+		owner:        createTestUser(t, "owner"),
+		coMaintainer: createTestUser(t, "co-maintainer"),
+		admin:        createTestUser(t, "admin", models.RoleAdmin),
+		stranger:     createTestUser(t, "stranger", models.RoleMaintainer),
+	}
+	f.project = models.Project{OwnerID: f.owner.ID, Title: "Project", RepoURL: "https://github.com/ossyne/project"}
+	mustCreate(t, &f.project)
+	mustCreate(t, &models.ProjectMaintainer{ProjectID: f.project.ID, UserID: f.coMaintainer.ID, AddedByID: f.owner.ID})
+	f.task = models.Task{ProjectID: f.project.ID, Title: "Task", Status: models.TaskStatusOpen}
+	mustCreate(t, &f.task)
+	f.claim = models.Claim{TaskID: f.task.ID, UserID: f.stranger.ID, Status: models.ClaimStatusPending}
+	mustCreate(t, &f.claim)
+	f.contribution = models.Contribution{TaskID: f.task.ID, UserID: f.stranger.ID, PRURL: "https://github.com/ossyne/project/pull/1"}
+	mustCreate(t, &f.contribution)
+	return f
+}
+
+func mustCreate(t *testing.T, value interface{}) {
+	t.Helper()
+	dbtest.Create(t, value)
+}
+
+func TestProjectAccessAuthorize(t *testing.T) {
+	useTestDB(t)
+	f := newAccessFixture(t)
+	access := NewProjectAccessService()
+
+	type check struct {
+		name      string
+		authorize func(user *models.User) error
+		missing   func(user *models.User) error
+		notFound  error
+	}
+	checks := []check{
+		{
+			name:      "project",
+			authorize: func(u *models.User) error { _, err := access.AuthorizeProject(u, f.project.ID); return err },
+			missing:   func(u *models.User) error { _, err := access.AuthorizeProject(u, f.project.ID+1000); return err },
+			notFound:  ErrProjectNotFound,
+		},
+		{
+			name:      "task",
+			authorize: func(u *models.User) error { _, err := access.AuthorizeTask(u, f.task.ID); return err },
+			missing:   func(u *models.User) error { _, err := access.AuthorizeTask(u, f.task.ID+1000); return err },
+			notFound:  ErrTaskNotFound,
+		},
+		{
+			name:      "claim",
+			authorize: func(u *models.User) error { _, err := access.AuthorizeClaim(u, f.claim.ID); return err },
+			missing:   func(u *models.User) error { _, err := access.AuthorizeClaim(u, f.claim.ID+1000); return err },
+			notFound:  ErrClaimNotFound,
+		},
+		{
+			name:      "contribution",
+			authorize: func(u *models.User) error { _, err := access.AuthorizeContribution(u, f.contribution.ID); return err },
+			missing:   func(u *models.User) error { _, err := access.AuthorizeContribution(u, f.contribution.ID+1000); return err },
+			notFound:  ErrContributionNotFound,
+		},
+	}
+	users := []struct {
+		name    string
+		user    *models.User
+		wantErr error
+	}{
+		{name: "owner", user: f.owner},
+		{name: "co-maintainer", user: f.coMaintainer},
+		{name: "admin", user: f.admin, wantErr: ErrNotProjectMaintainer},
+		{name: "maintainer role on another project", user: f.stranger, wantErr: ErrNotProjectMaintainer},
+		{name: "anonymous", user: nil, wantErr: ErrNotProjectMaintainer},
+	}
+	for _, c := range checks {
+		for _, u := range users {
+			t.Run(c.name+"/"+u.name, func(t *testing.T) {
+				if err := c.authorize(u.user); !errors.Is(err, u.wantErr) {
+					t.Errorf("error = %v, want %v", err, u.wantErr)
+				}
+			})
+		}
+		t.Run(c.name+"/missing", func(t *testing.T) {
+			if err := c.missing(f.owner); !errors.Is(err, c.notFound) {
+				t.Errorf("error = %v, want %v", err, c.notFound)
+			}
+		})
+	}
+}
+
+func TestProjectAccessDeletedProject(t *testing.T) {
+	useTestDB(t)
+	f := newAccessFixture(t)
+	access := NewProjectAccessService()
+	if err := db.DB.Delete(&f.project).Error; err != nil {
+		t.Fatalf("failed to delete project: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		wantErr error
+	}{
+		{name: "owner", user: f.owner},
+		{name: "co-maintainer", user: f.coMaintainer},
+		{name: "stranger", user: f.stranger, wantErr: ErrNotProjectMaintainer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := access.AuthorizeProject(tt.user, f.project.ID); !errors.Is(err, ErrProjectNotFound) {
+				t.Errorf("AuthorizeProject() error = %v, want %v", err, ErrProjectNotFound)
+			}
+			if _, err := access.AuthorizeDeletedProject(tt.user, f.project.ID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorizeDeletedProject() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectAccessMaintainers(t *testing.T) {
+	useTestDB(t)
+	f := newAccessFixture(t)
+	access := NewProjectAccessService()
+	newcomer := createTestUser(t, "newcomer")
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name:    "stranger cannot add maintainers",
+			run:     func() error { _, err := access.AddMaintainer(f.stranger, f.project.ID, newcomer.ID); return err },
+			wantErr: ErrNotProjectMaintainer,
+		},
+		{
+			name: "co-maintainer adds a maintainer",
+			run:  func() error { _, err := access.AddMaintainer(f.coMaintainer, f.project.ID, newcomer.ID); return err },
+		},
+		{
+			name:    "maintainer added twice",
+			run:     func() error { _, err := access.AddMaintainer(f.owner, f.project.ID, newcomer.ID); return err },
+			wantErr: ErrAlreadyMaintainer,
+		},
+		{
+			name:    "owner added as maintainer",
+			run:     func() error { _, err := access.AddMaintainer(f.coMaintainer, f.project.ID, f.owner.ID); return err },
+			wantErr: ErrAlreadyMaintainer,
+		},
+		{
+			name:    "unknown user",
+			run:     func() error { _, err := access.AddMaintainer(f.owner, f.project.ID, newcomer.ID+1000); return err },
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name: "new maintainer can act on the project",
+			run:  func() error { _, err := access.AuthorizeTask(newcomer, f.task.ID); return err },
+		},
+		{
+			name:    "stranger cannot remove maintainers",
+			run:     func() error { return access.RemoveMaintainer(f.stranger, f.project.ID, newcomer.ID) },
+			wantErr: ErrNotProjectMaintainer,
+		},
+		{
+			name: "owner removes a maintainer",
+			run:  func() error { return access.RemoveMaintainer(f.owner, f.project.ID, newcomer.ID) },
+		},
+		{
+			name:    "removed maintainer loses access",
+			run:     func() error { _, err := access.AuthorizeTask(newcomer, f.task.ID); return err },
+			wantErr: ErrNotProjectMaintainer,
+		},
+		{
+			name:    "maintainer removed twice",
+			run:     func() error { return access.RemoveMaintainer(f.owner, f.project.ID, newcomer.ID) },
+			wantErr: ErrMaintainerNotFound,
+		},
+		{
+			name: "removed maintainer can be added again",
+			run:  func() error { _, err := access.AddMaintainer(f.owner, f.project.ID, newcomer.ID); return err },
+		},
+	}
+	// The steps build on each other, so they run in order and stop at the
+	// first failure.
+	for _, tt := range tests {
+		if !t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		}) {
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultRecommendationLimit = 10
+	MaxRecommendationLimit     = 50
+	// recommendationCandidates caps how many of the newest claimable tasks
+	// are scored per request.
+	recommendationCandidates = 500
+)
+
+// RecommendationService suggests tasks to contributors based on their
+// skills and the work they have delivered before.
+type RecommendationService struct {
+	Dependencies *TaskDependencyService
+}
+
+func NewRecommendationService(dependencies *TaskDependencyService) *RecommendationService {
+	return &RecommendationService{Dependencies: dependencies}
+}
+
+// Recommend returns up to limit claimable tasks ranked for user, best first.
+// Tasks of projects the user owns or maintains are left out, as are tasks
+// they ever claimed, including claims withdrawn, rejected or expired since.
+func (s *RecommendationService) Recommend(user *models.User, limit int) ([]models.TaskRecommendation, error) {
+	if limit <= 0 {
+		limit = DefaultRecommendationLimit
+	}
+	if limit > MaxRecommendationLimit {
+		limit = MaxRecommendationLimit
+	}
+
+	profile, err := s.profile(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	if err := db.DB.Model(&models.Task{}).
+		Where("project_id IN (?)", db.DB.Model(&models.Project{}).Select("id").Where("owner_id <> ?", user.ID)).
+		Where("project_id NOT IN (?)", db.DB.Model(&models.ProjectMaintainer{}).Select("project_id").Where("user_id = ?", user.ID)).
+		Where("status IN ?", []string{models.TaskStatusOpen, models.TaskStatusClaimed, models.TaskStatusInProgress, models.TaskStatusSubmitted}).
+		Where("id NOT IN (?)", db.DB.Model(&models.Claim{}).Select("task_id").Where("user_id = ?", user.ID)).
+		Order("created_at DESC").Limit(recommendationCandidates).
+		Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load candidate tasks: %w", err)
+	}
+	if err := s.Dependencies.FillBlockedBy(tasks); err != nil {
+		return nil, err
+	}
+
+	recommendations := []models.TaskRecommendation{}
+	for i := range tasks {
+		if !tasks[i].AcceptsClaims() {
+			continue
+		}
+		recommendations = append(recommendations, profile.Score(&tasks[i]))
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// profile gathers the user's skills and accepted work.
+func (s *RecommendationService) profile(userID uint) (*models.RecommendationProfile, error) {
+	profile := &models.RecommendationProfile{
+		SkillLevels:          map[string]string{},
+		ProjectContributions: map[uint]int{},
+		PastSkills:           map[string]bool{},
+	}
+
+	var skills []models.UserSkill
+	if err := db.DB.Preload("Skill").Where("user_id = ?", userID).Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("failed to load skills of user %d: %w", userID, err)
+	}
+	for _, skill := range skills {
+		profile.SkillLevels[strings.ToLower(skill.Skill.Name)] = skill.Level
+	}
+
+	var contributions []models.Contribution
+	if err := db.DB.Preload("Task").
+		Where("user_id = ? AND accepted_at IS NOT NULL", userID).
+		Find(&contributions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load contributions of user %d: %w", userID, err)
+	}
+	for _, contribution := range contributions {
+		if contribution.Task == nil {
+			continue
+		}
+		profile.ProjectContributions[contribution.Task.ProjectID]++
+		for _, skill := range contribution.Task.SkillsRequired {
+			profile.PastSkills[strings.ToLower(skill)] = true
+		}
+	}
+	return profile, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrLastAdmin     = errors.New("cannot revoke the admin role from the last admin")
+	ErrRoleUnchanged = errors.New("role assignment unchanged")
+)
+
+// RoleService grants and revokes platform roles, recording every change in
+// the role change log.
+type RoleService struct{}
+
+func NewRoleService() *RoleService {
+	return &RoleService{}
+}
+
+// Grant adds role to the user. actorID is nil for changes made by the system.
+func (s *RoleService) Grant(actorID *uint, userID uint, role, reason string) error {
+	return s.change(actorID, userID, role, models.RoleActionGrant, reason)
+}
+
+func (s *RoleService) Revoke(actorID *uint, userID uint, role, reason string) error {
+	return s.change(actorID, userID, role, models.RoleActionRevoke, reason)
+}
+
+func (s *RoleService) change(actorID *uint, userID uint, role, action, reason string) error {
+	if !models.IsValidRole(role) {
+		return fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock every admin before reading the target, so two admins revoking
+		// each other cannot both see the other one still in place.
+		var adminIDs []uint
+		if action == models.RoleActionRevoke && role == models.RoleAdmin {
+			if err := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("JSON_CONTAINS(roles, JSON_QUOTE(?))", models.RoleAdmin).
+				Pluck("id", &adminIDs).Error; err != nil {
+				return fmt.Errorf("failed to lock admins: %w", err)
+			}
+		}
+
+		var user models.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("user %d: %w", userID, ErrUserNotFound)
+			}
+			return fmt.Errorf("failed to fetch user %d: %w", userID, err)
+		}
+
+		roles := models.JSONStringSlice{}
+		for _, r := range user.Roles {
+			if r != role {
+				roles = append(roles, r)
+			}
+		}
+		switch action {
+		case models.RoleActionGrant:
+			if user.HasRole(role) {
+				return fmt.Errorf("user %d already has role %s: %w", userID, role, ErrRoleUnchanged)
+			}
+			roles = append(roles, role)
+		case models.RoleActionRevoke:
+			if !user.HasRole(role) {
+				return fmt.Errorf("user %d does not have role %s: %w", userID, role, ErrRoleUnchanged)
+			}
+			if role == models.RoleAdmin && len(adminIDs) <= 1 {
+				return ErrLastAdmin
+			}
+		}
+
+		if err := tx.Model(&user).Update("roles", roles).Error; err != nil {
+			return fmt.Errorf("failed to update roles for user %d: %w", userID, err)
+		}
+		entry := models.RoleChangeLog{
+			ActorID:      actorID,
+			TargetUserID: userID,
+			Role:         role,
+			Action:       action,
+			Reason:       reason,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return fmt.Errorf("failed to record role change: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListChanges returns the role change audit log, newest first. A zero userID
+// returns changes for every user.
+func (s *RoleService) ListChanges(userID uint) ([]models.RoleChangeLog, error) {
+	var entries []models.RoleChangeLog
+	query := db.DB.Preload("Actor").Preload("TargetUser").Order("created_at DESC")
+	if userID != 0 {
+		query = query.Where("target_user_id = ?", userID)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list role changes: %w", err)
+	}
+	return entries, nil
+}
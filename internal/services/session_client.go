@@ -0,0 +1,43 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RefreshStoredSession exchanges the refresh token held in the keyring for a
+// new session token pair and stores it. serverURL is the server root, e.g.
+// https://ossyne.example.com. It returns the new access token.
+func RefreshStoredSession(client *http.Client, serverURL string, keyring *KeyringService) (string, error) {
+	refreshToken, err := keyring.GetRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal refresh request: %w", err)
+	}
+	resp, err := client.Post(serverURL+"/auth/refresh", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh session: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read refresh response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("session expired, please run 'osm auth login' (%s)", string(body))
+	}
+	var tokens SessionTokens
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return "", fmt.Errorf("failed to parse refresh response: %w", err)
+	}
+	if err := keyring.SetSession(tokens.AccessToken, tokens.RefreshToken); err != nil {
+		return "", err
+	}
+	return tokens.AccessToken, nil
+}
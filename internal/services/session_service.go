@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+
+const (
+	SessionTokenPrefix = "oss_"
+	RefreshTokenPrefix = "ossr_"
+	sessionAccessTTL   = 24 * time.Hour
+	sessionRefreshTTL  = 30 * 24 * time.Hour
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrInvalidSession  = errors.New("invalid or expired session")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// SessionTokens is the token pair handed to a client when a session is
+// created or refreshed. Only hashes of these values are stored.
+type SessionTokens struct {
+	SessionID    uint      `json:"session_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// SessionService mints and validates Ossyne-issued session tokens.
+type SessionService struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+func NewSessionService() *SessionService {
+	return &SessionService{
+		AccessTTL:  sessionAccessTTL,
+		RefreshTTL: sessionRefreshTTL,
+	}
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *SessionService) Create(userID uint, userAgent string) (*SessionTokens, error) {
+	accessToken, refreshToken, err := newSessionTokenPair()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := models.Session{
+		UserID:           userID,
+		TokenHash:        HashToken(accessToken),
+		RefreshTokenHash: HashToken(refreshToken),
+		ExpiresAt:        now.Add(s.AccessTTL),
+		RefreshExpiresAt: now.Add(s.RefreshTTL),
+		UserAgent:        truncate(userAgent, 255),
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return &SessionTokens{
+		SessionID:    session.ID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    session.ExpiresAt,
+	}, nil
+}
+
+// Authenticate resolves an access token to its user and session.
+func (s *SessionService) Authenticate(token string) (*models.User, *models.Session, error) {
+	if !strings.HasPrefix(token, SessionTokenPrefix) {
+		return nil, nil, ErrInvalidSession
+	}
+	var session models.Session
+	if err := db.DB.Preload("User").Where("token_hash = ?", HashToken(token)).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidSession
+		}
+		return nil, nil, fmt.Errorf("failed to look up session: %w", err)
+	}
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return nil, nil, ErrInvalidSession
+	}
+	if session.LastUsedAt == nil || now.Sub(*session.LastUsedAt) > lastUsedResolution {
+		db.DB.Model(&session).UpdateColumn("last_used_at", now)
+		session.LastUsedAt = &now
+	}
+	user := session.User
+	return &user, &session, nil
+}
+
+// Refresh rotates both tokens of the session that owns refreshToken.
+func (s *SessionService) Refresh(refreshToken string) (*SessionTokens, error) {
+	if !strings.HasPrefix(refreshToken, RefreshTokenPrefix) {
+		return nil, ErrInvalidSession
+	}
+	var session models.Session
+	if err := db.DB.Where("refresh_token_hash = ?", HashToken(refreshToken)).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, fmt.Errorf("failed to look up session: %w", err)
+	}
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(session.RefreshExpiresAt) {
+		return nil, ErrInvalidSession
+	}
+
+	accessToken, newRefreshToken, err := newSessionTokenPair()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(s.AccessTTL)
+	result := db.DB.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, session.RefreshTokenHash).
+		Updates(map[string]interface{}{
+			"token_hash":         HashToken(accessToken),
+			"refresh_token_hash": HashToken(newRefreshToken),
+			"expires_at":         expiresAt,
+			"refresh_expires_at": now.Add(s.RefreshTTL),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to refresh session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Another request rotated this refresh token first.
+		return nil, ErrInvalidSession
+	}
+	return &SessionTokens{
+		SessionID:    session.ID,
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// List returns the user's sessions that have not been revoked and can still
+// be refreshed.
+func (s *SessionService) List(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := db.DB.Where("user_id = ? AND revoked_at IS NULL AND refresh_expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sessions for user %d: %w", userID, err)
+	}
+	return sessions, nil
+}
+
+func (s *SessionService) Revoke(userID, sessionID uint) error {
+	result := db.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke session: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("session %d: %w", sessionID, ErrSessionNotFound)
+	}
+	return nil
+}
+
+func newSessionTokenPair() (string, string, error) {
+	access, err := randomToken(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	refresh, err := randomToken(32)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return SessionTokenPrefix + access, RefreshTokenPrefix + refresh, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
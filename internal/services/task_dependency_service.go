@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTaskBlocked        = errors.New("task is blocked by unfinished prerequisites")
+)
+
+// TaskDependencyService manages "task B depends on task A" links. A task with
+// a prerequisite that is not completed yet is blocked and takes no claims.
+type TaskDependencyService struct{}
+
+func NewTaskDependencyService() *TaskDependencyService {
+	return &TaskDependencyService{}
+}
+
+// Add makes taskID depend on dependsOnID. Links that would close a cycle are
+// refused, including a task depending on itself.
+func (s *TaskDependencyService) Add(taskID, dependsOnID uint, actorID *uint) (*models.TaskDependency, error) {
+	if taskID == dependsOnID {
+		return nil, fmt.Errorf("%w: task %d cannot depend on itself", ErrDependencyCycle, taskID)
+	}
+	var prerequisite models.Task
+	if err := db.DB.First(&prerequisite, dependsOnID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("prerequisite task %d: %w", dependsOnID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", dependsOnID, err)
+	}
+
+	dependency := models.TaskDependency{
+		TaskID:      taskID,
+		DependsOnID: dependsOnID,
+		AddedByID:   actorID,
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.TaskDependency{}).
+			Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("failed to check existing dependencies: %w", err)
+		}
+		if existing > 0 {
+			return fmt.Errorf("%w: task %d already depends on task %d", ErrDependencyExists, taskID, dependsOnID)
+		}
+		// The new link closes a cycle if taskID is already a (transitive)
+		// prerequisite of dependsOnID.
+		cyclic, err := dependsOn(tx, dependsOnID, taskID)
+		if err != nil {
+			return err
+		}
+		if cyclic {
+			return fmt.Errorf("%w: task %d already depends on task %d", ErrDependencyCycle, dependsOnID, taskID)
+		}
+		if err := tx.Omit("Task", "DependsOn").Create(&dependency).Error; err != nil {
+			return fmt.Errorf("failed to add dependency: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	dependency.DependsOn = &prerequisite
+	return &dependency, nil
+}
+
+// Remove drops the link between taskID and its prerequisite dependsOnID.
+func (s *TaskDependencyService) Remove(taskID, dependsOnID uint) error {
+	// Hard delete so the link can be added again later without tripping the
+	// unique (task_id, depends_on_id) index.
+	result := db.DB.Unscoped().
+		Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).
+		Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove dependency: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("task %d on task %d: %w", taskID, dependsOnID, ErrDependencyNotFound)
+	}
+	return nil
+}
+
+// Tree returns taskID with its prerequisites, their prerequisites and so on.
+// Blocked tasks in the tree have BlockedBy set.
+func (s *TaskDependencyService) Tree(taskID uint) (*models.DependencyNode, error) {
+	var root models.Task
+	if err := db.DB.First(&root, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+
+	// Load the whole reachable graph breadth-first, one query per level.
+	tasks := map[uint]models.Task{root.ID: root}
+	prerequisites := map[uint][]uint{}
+	frontier := []uint{root.ID}
+	for len(frontier) > 0 {
+		var links []models.TaskDependency
+		if err := db.DB.Preload("DependsOn").Where("task_id IN ?", frontier).Order("depends_on_id").Find(&links).Error; err != nil {
+			return nil, fmt.Errorf("failed to load dependencies: %w", err)
+		}
+		frontier = nil
+		for _, link := range links {
+			if link.DependsOn == nil {
+				continue
+			}
+			prerequisites[link.TaskID] = append(prerequisites[link.TaskID], link.DependsOnID)
+			if _, seen := tasks[link.DependsOnID]; !seen {
+				tasks[link.DependsOnID] = *link.DependsOn
+				frontier = append(frontier, link.DependsOnID)
+			}
+		}
+	}
+
+	var build func(id uint) models.DependencyNode
+	build = func(id uint) models.DependencyNode {
+		node := models.DependencyNode{Task: tasks[id], DependsOn: []models.DependencyNode{}}
+		for _, prereqID := range prerequisites[id] {
+			if tasks[prereqID].Status != models.TaskStatusCompleted {
+				node.Task.BlockedBy = append(node.Task.BlockedBy, prereqID)
+			}
+			node.DependsOn = append(node.DependsOn, build(prereqID))
+		}
+		return node
+	}
+	tree := build(root.ID)
+	return &tree, nil
+}
+
+// FillBlockedBy sets BlockedBy on every task in tasks.
+func (s *TaskDependencyService) FillBlockedBy(tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	blocking, err := openPrerequisites(db.DB, ids)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].BlockedBy = blocking[tasks[i].ID]
+	}
+	return nil
+}
+
+// openPrerequisites maps each of taskIDs that is blocked to the IDs of its
+// prerequisites that are not completed yet.
+func openPrerequisites(tx *gorm.DB, taskIDs []uint) (map[uint][]uint, error) {
+	var rows []struct {
+		TaskID      uint
+		DependsOnID uint
+	}
+	if err := tx.Model(&models.TaskDependency{}).
+		Select("task_dependencies.task_id, task_dependencies.depends_on_id").
+		Joins("JOIN tasks ON tasks.id = task_dependencies.depends_on_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.task_id IN ? AND tasks.status <> ?", taskIDs, models.TaskStatusCompleted).
+		Order("task_dependencies.depends_on_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to check task prerequisites: %w", err)
+	}
+	blocking := map[uint][]uint{}
+	for _, row := range rows {
+		blocking[row.TaskID] = append(blocking[row.TaskID], row.DependsOnID)
+	}
+	return blocking, nil
+}
+
+// checkNotBlocked fills task.BlockedBy and returns ErrTaskBlocked if any
+// prerequisite is unfinished.
+func checkNotBlocked(tx *gorm.DB, task *models.Task) error {
+	blocking, err := openPrerequisites(tx, []uint{task.ID})
+	if err != nil {
+		return err
+	}
+	task.BlockedBy = blocking[task.ID]
+	if task.IsBlocked() {
+		return fmt.Errorf("%w: task '%s' waits on tasks %v", ErrTaskBlocked, task.Title, task.BlockedBy)
+	}
+	return nil
+}
+
+// dependsOn reports whether from transitively depends on target.
+func dependsOn(tx *gorm.DB, from, target uint) (bool, error) {
+	seen := map[uint]bool{from: true}
+	frontier := []uint{from}
+	for len(frontier) > 0 {
+		var next []uint
+		if err := tx.Model(&models.TaskDependency{}).
+			Where("task_id IN ?", frontier).
+			Pluck("depends_on_id", &next).Error; err != nil {
+			return false, fmt.Errorf("failed to walk task dependencies: %w", err)
+		}
+		frontier = nil
+		for _, id := range next {
+			if id == target {
+				return true, nil
+			}
+			if !seen[id] {
+				seen[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"strings"
+	"time"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTaskPageSize = 50
+	MaxTaskPageSize     = 200
+	DefaultTaskSort     = "-created_at"
+)
+
+var (
+	ErrInvalidTaskSearch = errors.New("invalid task search")
+	ErrInvalidCursor     = errors.New("invalid or expired cursor")
+)
+
+// taskSortColumns maps the sort keys clients may use to the SQL expression
+// ordered on. A leading "-" on the key sorts descending.
+var taskSortColumns = map[string]string{
+	"created_at":      "tasks.created_at",
+	"bounty_amount":   "tasks.bounty_amount",
+	"estimated_hours": "COALESCE(tasks.estimated_hours, 0)",
+	"title":           "tasks.title",
+}
+
+// TaskSearch holds the filters of a task search. Zero values mean "no
+// filter"; every filter that is set must match.
+type TaskSearch struct {
+	Text         string // matched against title and description
+	ProjectID    uint
+	MilestoneID  uint
+	Status       string // archived tasks are excluded unless asked for
+	Tags         []string
+	Skills       []string
+	Difficulties []string
+	MinBounty    *float64
+	MaxBounty    *float64
+	MinHours     *int
+	MaxHours     *int
+	Sort         string // a key of taskSortColumns, optionally prefixed with "-"
+	Limit        int
+	Cursor       string // NextCursor of the previous page
+}
+
+// TaskPage is one page of search results. NextCursor is empty on the last
+// page.
+type TaskPage struct {
+	Tasks      []models.Task
+	NextCursor string
+}
+
+// taskCursor marks the last task of a page. It records the sort it was made
+// for so it cannot be replayed against a different ordering.
+type taskCursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// Search returns one page of tasks of live projects matching search, using
+// keyset pagination on the sort column and the task ID.
+func (s *TaskService) Search(search TaskSearch) (*TaskPage, error) {
+	if search.Sort == "" {
+		search.Sort = DefaultTaskSort
+	}
+	key, desc := strings.TrimPrefix(search.Sort, "-"), strings.HasPrefix(search.Sort, "-")
+	column, ok := taskSortColumns[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidTaskSearch, search.Sort)
+	}
+	if search.Limit <= 0 {
+		search.Limit = DefaultTaskPageSize
+	}
+	if search.Limit > MaxTaskPageSize {
+		search.Limit = MaxTaskPageSize
+	}
+
+	query, err := applyTaskFilters(db.DB.Model(&models.Task{}), search)
+	if err != nil {
+		return nil, err
+	}
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+	if search.Cursor != "" {
+		cursor, value, err := decodeTaskCursor(search.Cursor, search.Sort, key)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND tasks.id %s ?))", column, comparison, column, comparison),
+			value, value, cursor.ID)
+	}
+
+	var tasks []models.Task
+	if err := query.Order(fmt.Sprintf("%s %s, tasks.id %s", column, direction, direction)).
+		Limit(search.Limit + 1).Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+	page := &TaskPage{Tasks: tasks}
+	if len(tasks) > search.Limit {
+		page.Tasks = tasks[:search.Limit]
+		page.NextCursor, err = encodeTaskCursor(search.Sort, key, &page.Tasks[search.Limit-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func applyTaskFilters(query *gorm.DB, search TaskSearch) (*gorm.DB, error) {
+	// Tasks of deleted projects stay hidden until the project is restored.
+	query = query.Where("tasks.project_id IN (?)", db.DB.Model(&models.Project{}).Select("id"))
+	if search.ProjectID != 0 {
+		query = query.Where("tasks.project_id = ?", search.ProjectID)
+	}
+	if search.MilestoneID != 0 {
+		query = query.Where("tasks.milestone_id = ?", search.MilestoneID)
+	}
+	if search.Status != "" {
+		query = query.Where("tasks.status = ?", search.Status)
+	} else {
+		// Archived tasks only show up when asked for explicitly.
+		query = query.Where("tasks.status <> ?", models.TaskStatusArchived)
+	}
+	if text := strings.TrimSpace(search.Text); text != "" {
+		pattern := "%" + escapeLike(text) + "%"
+		query = query.Where("(tasks.title LIKE ? OR tasks.description LIKE ?)", pattern, pattern)
+	}
+	for _, tag := range search.Tags {
+		query = query.Where("JSON_CONTAINS(tasks.tags, JSON_QUOTE(?))", tag)
+	}
+	for _, skill := range search.Skills {
+		query = query.Where("JSON_CONTAINS(tasks.skills_required, JSON_QUOTE(?))", skill)
+	}
+	if len(search.Difficulties) > 0 {
+		for _, difficulty := range search.Difficulties {
+			switch difficulty {
+			case "easy", "medium", "hard":
+			default:
+				return nil, fmt.Errorf("%w: difficulty must be easy, medium or hard, not %q", ErrInvalidTaskSearch, difficulty)
+			}
+		}
+		query = query.Where("tasks.difficulty_level IN ?", search.Difficulties)
+	}
+	if search.MinBounty != nil && search.MaxBounty != nil && *search.MinBounty > *search.MaxBounty {
+		return nil, fmt.Errorf("%w: min bounty is above max bounty", ErrInvalidTaskSearch)
+	}
+	if search.MinBounty != nil {
+		query = query.Where("tasks.bounty_amount >= ?", *search.MinBounty)
+	}
+	if search.MaxBounty != nil {
+		query = query.Where("tasks.bounty_amount <= ?", *search.MaxBounty)
+	}
+	if search.MinHours != nil && search.MaxHours != nil && *search.MinHours > *search.MaxHours {
+		return nil, fmt.Errorf("%w: min hours is above max hours", ErrInvalidTaskSearch)
+	}
+	if search.MinHours != nil {
+		query = query.Where("COALESCE(tasks.estimated_hours, 0) >= ?", *search.MinHours)
+	}
+	if search.MaxHours != nil {
+		query = query.Where("COALESCE(tasks.estimated_hours, 0) <= ?", *search.MaxHours)
+	}
+	return query, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func encodeTaskCursor(sort, key string, last *models.Task) (string, error) {
+	var value interface{}
+	switch key {
+	case "created_at":
+		value = last.CreatedAt
+	case "bounty_amount":
+		value = last.BountyAmount
+	case "estimated_hours":
+		value = last.EstimatedHours
+	case "title":
+		value = last.Title
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	encoded, err := json.Marshal(taskCursor{Sort: sort, Value: raw, ID: last.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// decodeTaskCursor unpacks a cursor made for sort and returns the sort value
+// typed for the column it is compared with.
+func decodeTaskCursor(encoded, sort, key string) (*taskCursor, interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	var cursor taskCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort || cursor.ID == 0 {
+		return nil, nil, ErrInvalidCursor
+	}
+	var value interface{}
+	switch key {
+	case "created_at":
+		var t time.Time
+		err = json.Unmarshal(cursor.Value, &t)
+		value = t
+	case "bounty_amount":
+		var f float64
+		err = json.Unmarshal(cursor.Value, &f)
+		value = f
+	case "estimated_hours":
+		var n int
+		err = json.Unmarshal(cursor.Value, &n)
+		value = n
+	case "title":
+		var s string
+		err = json.Unmarshal(cursor.Value, &s)
+		value = s
+	}
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	return &cursor, value, nil
+}
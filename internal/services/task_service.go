@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"time"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskService handles task lifecycle operations that touch more than the
+// task row itself, such as archiving a task that still holds a bounty.
+type TaskService struct {
+	Payments *PaymentService
+	States   *TaskStateService
+}
+
+func NewTaskService(payments *PaymentService, states *TaskStateService) *TaskService {
+	return &TaskService{Payments: payments, States: states}
+}
+
+// Archive takes a task off the board. Claims still waiting for review are
+// rejected. Unless the task was completed, accepted claims are released and an
+// escrowed bounty is refunded, all in one transaction with the status change.
+func (s *TaskService) Archive(taskID uint, actorID *uint, reason string) (*models.Task, error) {
+	note := "Task archived"
+	if reason != "" {
+		note += ": " + reason
+	}
+	var task models.Task
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the row so the bounty cannot be funded or paid out meanwhile.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		wasCompleted := task.Status == models.TaskStatusCompleted
+		if err := s.States.Transition(tx, &task, models.TaskStatusArchived, actorID, note); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&models.Claim{}).
+			Where("task_id = ? AND status = ?", task.ID, models.ClaimStatusPending).
+			Updates(map[string]interface{}{
+				"status":         models.ClaimStatusRejected,
+				"reviewed_by_id": actorID,
+				"reviewed_at":    now,
+				"review_note":    note,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to reject pending claims: %w", err)
+		}
+		// Claims on a completed task record finished work and stay accepted.
+		if wasCompleted {
+			return nil
+		}
+		if err := tx.Model(&models.Claim{}).
+			Where("task_id = ? AND status = ?", task.ID, models.ClaimStatusAccepted).
+			Updates(map[string]interface{}{
+				"status":         models.ClaimStatusWithdrawn,
+				"released_at":    now,
+				"release_reason": note,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to release accepted claims: %w", err)
+		}
+		// The gateway refund cannot be undone, so it goes last.
+		if task.BountyEscrowID != nil && *task.BountyEscrowID != "" {
+			if err := s.Payments.RefundTaskBountyTx(tx, &task); err != nil {
+				return fmt.Errorf("failed to refund bounty: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Reopen puts an archived task back on the board. Its bounty was refunded on
+// archiving, so it has to be funded again.
+func (s *TaskService) Reopen(taskID uint, actorID *uint, reason string) (*models.Task, error) {
+	var task models.Task
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		if task.Status != models.TaskStatusArchived {
+			return fmt.Errorf("%w: task %d is %s, not archived", ErrIllegalTaskTransition, task.ID, task.Status)
+		}
+		return s.States.Transition(tx, &task, models.TaskStatusOpen, actorID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
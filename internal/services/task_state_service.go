@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTaskStatus     = errors.New("invalid task status")
+	ErrIllegalTaskTransition = errors.New("illegal task status transition")
+	ErrTransitionNotDirect   = errors.New("this status change has its own workflow")
+)
+
+// taskTransitions lists, for every status, the statuses a task may move to.
+var taskTransitions = map[string][]string{
+	models.TaskStatusOpen:       {models.TaskStatusClaimed, models.TaskStatusArchived},
+	models.TaskStatusClaimed:    {models.TaskStatusInProgress, models.TaskStatusSubmitted, models.TaskStatusOpen, models.TaskStatusArchived},
+	models.TaskStatusInProgress: {models.TaskStatusSubmitted, models.TaskStatusOpen, models.TaskStatusArchived},
+	models.TaskStatusSubmitted:  {models.TaskStatusCompleted, models.TaskStatusClaimed, models.TaskStatusArchived},
+	models.TaskStatusCompleted:  {models.TaskStatusArchived},
+	models.TaskStatusArchived:   {models.TaskStatusOpen},
+}
+
+// directTransitions are the moves that change nothing but the task's status.
+// Every other move goes through the service that owns its side effects:
+// claims, contributions and payouts, or escrowed bounties.
+var directTransitions = map[string][]string{
+	models.TaskStatusClaimed:   {models.TaskStatusInProgress},
+	models.TaskStatusSubmitted: {models.TaskStatusClaimed},
+}
+
+// TaskStateService is the only place task statuses change. It enforces the
+// lifecycle in taskTransitions and records every change in
+// task_status_history.
+type TaskStateService struct{}
+
+func NewTaskStateService() *TaskStateService {
+	return &TaskStateService{}
+}
+
+func IsValidTaskStatus(status string) bool {
+	_, ok := taskTransitions[status]
+	return ok
+}
+
+// CanTransition reports whether a task may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range taskTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionDirectly reports whether a task may move from one status to
+// another without going through another service.
+func CanTransitionDirectly(from, to string) bool {
+	for _, next := range directTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves task to status inside tx and logs the change. actorID is
+// nil for changes made by the system. The update only applies if the task
+// still has the status it was loaded with, so concurrent transitions cannot
+// both succeed.
+func (s *TaskStateService) Transition(tx *gorm.DB, task *models.Task, to string, actorID *uint, reason string) error {
+	if !IsValidTaskStatus(to) {
+		return fmt.Errorf("%w: %q", ErrInvalidTaskStatus, to)
+	}
+	from := task.Status
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: task %d cannot move from %s to %s", ErrIllegalTaskTransition, task.ID, from, to)
+	}
+	result := tx.Model(&models.Task{}).
+		Where("id = ? AND status = ?", task.ID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update status of task %d: %w", task.ID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: task %d is no longer %s", ErrIllegalTaskTransition, task.ID, from)
+	}
+	entry := models.TaskStatusHistory{
+		TaskID:     task.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record status change of task %d: %w", task.ID, err)
+	}
+	task.Status = to
+	return nil
+}
+
+// TransitionByID loads the task and transitions it in its own transaction.
+func (s *TaskStateService) TransitionByID(taskID uint, to string, actorID *uint, reason string) (*models.Task, error) {
+	var task models.Task
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		return s.Transition(tx, &task, to, actorID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// TransitionDirectly is TransitionByID limited to the moves in
+// directTransitions.
+func (s *TaskStateService) TransitionDirectly(taskID uint, to string, actorID *uint, reason string) (*models.Task, error) {
+	var task models.Task
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+			}
+			return fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+		}
+		if !CanTransitionDirectly(task.Status, to) {
+			return fmt.Errorf("%w: task %d cannot move from %s to %s here", ErrTransitionNotDirect, task.ID, task.Status, to)
+		}
+		return s.Transition(tx, &task, to, actorID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// History returns the task's status changes, oldest first.
+func (s *TaskStateService) History(taskID uint) ([]models.TaskStatusHistory, error) {
+	var entries []models.TaskStatusHistory
+	if err := db.DB.Preload("Actor").Where("task_id = ?", taskID).Order("created_at, id").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load history of task %d: %w", taskID, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"ossyne/internal/db/dbtest"
+	"ossyne/internal/models"
+	"testing"
+)
+
+// useTestDB points db.DB at the migrated test database, or skips the test
+// when OSSYNE_TEST_DSN is not set.
+func useTestDB(t *testing.T) {
+	t.Helper()
+	dbtest.Use(t)
+}
+
+func createTestUser(t *testing.T, username string, roles ...string) *models.User {
+	t.Helper()
+	return dbtest.CreateUser(t, username, roles...)
+}
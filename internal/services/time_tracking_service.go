@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"ossyne/internal/db"
+	"ossyne/internal/models"
+	"time"
+	"gorm.io/gorm"
+)
+
+// MaxTimeEntryNoteLength caps time entry notes, in bytes.
+const MaxTimeEntryNoteLength = 1000
+
+var (
+	ErrInvalidTimeEntry   = errors.New("invalid time entry")
+	ErrNoAcceptedClaim    = errors.New("you need an accepted claim on this task to log time")
+	ErrTimeEntryNotFound  = errors.New("time entry not found")
+	ErrNotTimeEntryAuthor = errors.New("only the user who logged this time can delete it")
+)
+
+// TimeTrackingService records the effort claimants put into their claims and
+// compares it with the estimates on the tasks.
+type TimeTrackingService struct {
+	Access *ProjectAccessService
+}
+
+func NewTimeTrackingService(access *ProjectAccessService) *TimeTrackingService {
+	return &TimeTrackingService{Access: access}
+}
+
+// LogTime records minutes of work by user on the task, against their
+// accepted claim. spentOn is the day the work was done and may not be in the
+// future.
+func (s *TimeTrackingService) LogTime(user *models.User, taskID uint, minutes int, spentOn time.Time, note string) (*models.TimeEntry, error) {
+	if minutes < 1 || minutes > models.MaxTimeEntryMinutes {
+		return nil, fmt.Errorf("%w: log between 1 minute and %d hours per entry", ErrInvalidTimeEntry, models.MaxTimeEntryMinutes/60)
+	}
+	if len(note) > MaxTimeEntryNoteLength {
+		return nil, fmt.Errorf("%w: note is longer than %d bytes", ErrInvalidTimeEntry, MaxTimeEntryNoteLength)
+	}
+	spentOn = time.Date(spentOn.Year(), spentOn.Month(), spentOn.Day(), 0, 0, 0, 0, time.Local)
+	if spentOn.After(time.Now()) {
+		return nil, fmt.Errorf("%w: %s is in the future", ErrInvalidTimeEntry, spentOn.Format("2006-01-02"))
+	}
+
+	var claim models.Claim
+	err := db.DB.Where("task_id = ? AND user_id = ? AND status = ?", taskID, user.ID, models.ClaimStatusAccepted).First(&claim).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrNoAcceptedClaim)
+		}
+		return nil, fmt.Errorf("failed to fetch claim on task %d: %w", taskID, err)
+	}
+
+	entry := models.TimeEntry{
+		ClaimID: claim.ID,
+		TaskID:  claim.TaskID,
+		UserID:  user.ID,
+		Minutes: minutes,
+		SpentOn: spentOn,
+		Note:    note,
+	}
+	if err := db.DB.Omit("User").Create(&entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to log time on task %d: %w", taskID, err)
+	}
+	return &entry, nil
+}
+
+// DeleteEntry removes a time entry. Only its author may delete it.
+func (s *TimeTrackingService) DeleteEntry(user *models.User, entryID uint) error {
+	var entry models.TimeEntry
+	if err := db.DB.First(&entry, entryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("time entry %d: %w", entryID, ErrTimeEntryNotFound)
+		}
+		return fmt.Errorf("failed to fetch time entry %d: %w", entryID, err)
+	}
+	if entry.UserID != user.ID {
+		return ErrNotTimeEntryAuthor
+	}
+	if err := db.DB.Delete(&entry).Error; err != nil {
+		return fmt.Errorf("failed to delete time entry %d: %w", entryID, err)
+	}
+	return nil
+}
+
+// ForTask returns the time logged on a task, per user and entry by entry,
+// newest first.
+func (s *TimeTrackingService) ForTask(taskID uint) (*models.TaskTime, error) {
+	var task models.Task
+	if err := db.DB.First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task %d: %w", taskID, ErrTaskNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch task %d: %w", taskID, err)
+	}
+
+	summary := &models.TaskTime{TaskID: task.ID, EstimatedHours: task.EstimatedHours, Users: []models.UserTime{}}
+	if err := db.DB.Model(&models.TimeEntry{}).
+		Select("time_entries.user_id, users.username, SUM(time_entries.minutes) AS minutes").
+		Joins("JOIN users ON users.id = time_entries.user_id").
+		Where("time_entries.task_id = ?", task.ID).
+		Group("time_entries.user_id, users.username").
+		Order("minutes DESC").
+		Scan(&summary.Users).Error; err != nil {
+		return nil, fmt.Errorf("failed to total time on task %d: %w", task.ID, err)
+	}
+	for _, user := range summary.Users {
+		summary.Minutes += user.Minutes
+	}
+	if err := db.DB.Preload("User").Where("task_id = ?", task.ID).
+		Order("spent_on DESC, id DESC").Find(&summary.Entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load time entries of task %d: %w", task.ID, err)
+	}
+	return summary, nil
+}
+
+// ForUser returns the time a user logged, per task, most first.
+func (s *TimeTrackingService) ForUser(userID uint) (*models.UserTimeSummary, error) {
+	var user models.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user %d: %w", userID, ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch user %d: %w", userID, err)
+	}
+
+	summary := &models.UserTimeSummary{UserID: user.ID, Tasks: []models.UserTaskTime{}}
+	if err := db.DB.Model(&models.TimeEntry{}).
+		Select("time_entries.task_id, tasks.title, SUM(time_entries.minutes) AS minutes").
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id").
+		Where("time_entries.user_id = ?", user.ID).
+		Group("time_entries.task_id, tasks.title").
+		Order("minutes DESC").
+		Scan(&summary.Tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to total time of user %d: %w", user.ID, err)
+	}
+	for _, task := range summary.Tasks {
+		summary.Minutes += task.Minutes
+	}
+	return summary, nil
+}
+
+// ProjectReport compares the estimate of each of the project's tasks with the
+// time logged on it. Only maintainers may see it.
+func (s *TimeTrackingService) ProjectReport(actor *models.User, projectID uint) (*models.EstimateReport, error) {
+	project, err := s.Access.AuthorizeProject(actor, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	if err := db.DB.Where("project_id = ?", project.ID).Order("id").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load tasks of project %d: %w", project.ID, err)
+	}
+	var totals []struct {
+		TaskID  uint
+		Minutes int
+	}
+	if err := db.DB.Model(&models.TimeEntry{}).
+		Select("task_id, SUM(minutes) AS minutes").
+		Where("task_id IN (?)", db.DB.Model(&models.Task{}).Select("id").Where("project_id = ?", project.ID)).
+		Group("task_id").
+		Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to total time on project %d: %w", project.ID, err)
+	}
+	minutes := make(map[uint]int, len(totals))
+	for _, total := range totals {
+		minutes[total.TaskID] = total.Minutes
+	}
+	report := models.NewEstimateReport(project.ID, tasks, minutes)
+	return &report, nil
+}
@@ -18,15 +18,15 @@ func max(a, b int) int {
 	return b
 }
 
-func InitModel() model {
+func InitModel(serverURL, profile string) model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	filterInput := textinput.New()
-	filterInput.Placeholder = "Filter tasks..."
+	filterInput.Placeholder = "text tag:go bounty>=50 ... (enter to search)"
 	filterInput.Blur()
-	filterInput.CharLimit = 150
+	filterInput.CharLimit = 250
 	filterInput.Prompt = "Filter: "
 	filterInput.ShowSuggestions = true
 	filterInput.Cursor.SetMode(cursor.CursorStatic)
@@ -157,14 +157,36 @@ func InitModel() model {
 	projectTasksList.Styles.PaginationStyle = lipgloss.NewStyle().PaddingLeft(4)
 	projectTasksList.Styles.HelpStyle = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("241"))
 
+	pendingClaimsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	pendingClaimsList.Title = "Pending Claims"
+	pendingClaimsList.SetShowStatusBar(false)
+	pendingClaimsList.SetFilteringEnabled(true)
+	pendingClaimsList.Styles.Title = titleStyle
+	pendingClaimsList.Styles.FilterPrompt = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	pendingClaimsList.Styles.FilterCursor = lipgloss.NewStyle().Foreground(yellow)
+	pendingClaimsList.Styles.PaginationStyle = lipgloss.NewStyle().PaddingLeft(4)
+	pendingClaimsList.Styles.HelpStyle = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("241"))
+
+	milestonesList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	milestonesList.Title = "Milestones"
+	milestonesList.SetShowStatusBar(false)
+	milestonesList.SetFilteringEnabled(true)
+	milestonesList.Styles.Title = titleStyle
+	milestonesList.Styles.FilterPrompt = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	milestonesList.Styles.FilterCursor = lipgloss.NewStyle().Foreground(green)
+	milestonesList.Styles.PaginationStyle = lipgloss.NewStyle().PaddingLeft(4)
+	milestonesList.Styles.HelpStyle = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("241"))
+
 	return model{
 		state:             viewLanding,
 		tasksList:         tasksList,
 		projectsList:      projectsList,
 		projectTasksList:  projectTasksList,
+		pendingClaimsList: pendingClaimsList,
+		milestonesList:    milestonesList,
 		spinner:           s,
 		status:            "Welcome to OSSYNE!",
-		apiClient:         NewAPIClient("http://localhost:8080"),
+		apiClient:         NewAPIClient(serverURL, profile),
 		loading:           false,
 		filterInput:       filterInput,
 		submitInput:       submitInput,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"ossyne/internal/models"
 	"ossyne/internal/services"
 	"strconv"
@@ -19,61 +20,67 @@ type APIClient struct {
 	Keyring services.KeyringService
 }
 
-func checkLoginStatusCmd() tea.Cmd {
+func (c *APIClient) checkLoginStatusCmd() tea.Cmd {
 	return func() tea.Msg {
-		keyring := services.KeyringService{}
-		token, err := keyring.GetToken()
+		token, err := c.Keyring.GetToken()
 		if err != nil {
 			return notLoggedInMsg{}
 		}
 		// If token exists, fetch the user profile
-		apiClient := NewAPIClient("http://localhost:8080")
-		return apiClient.fetchMeCmd(token)()
+		return c.fetchMeCmd(token)()
 	}
 }
 
 func (c *APIClient) fetchMeCmd(token string) tea.Cmd {
 	return func() tea.Msg {
-		req, err := http.NewRequest(http.MethodGet, c.BaseURL+"/api/users/me", nil)
-		if err != nil {
-			return errMsg{fmt.Errorf("failed to create /me request: %w", err)}
-		}
-		req.Header.Set("Authorization", "Bearer "+token)
+		return c.fetchMe(token, true)
+	}
+}
 
-		resp, err := c.Client.Do(req)
-		if err != nil {
-			return errMsg{fmt.Errorf("failed to connect to server for /me: %w", err)}
-		}
-		defer resp.Body.Close()
+func (c *APIClient) fetchMe(token string, allowRefresh bool) tea.Msg {
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+"/api/users/me", nil)
+	if err != nil {
+		return errMsg{fmt.Errorf("failed to create /me request: %w", err)}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 
-		if resp.StatusCode == http.StatusUnauthorized {
-			keyringService := services.KeyringService{}
-			keyringService.ClearToken()
-			return notLoggedInMsg{}
-		}
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return errMsg{fmt.Errorf("API error for /me: %s (%s)", resp.Status, string(body))}
-		}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return errMsg{fmt.Errorf("failed to connect to server for /me: %w", err)}
+	}
+	defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return errMsg{fmt.Errorf("failed to read /me response body: %w", err)}
+	if resp.StatusCode == http.StatusUnauthorized {
+		if allowRefresh {
+			if newToken, err := services.RefreshStoredSession(c.Client, c.BaseURL, &c.Keyring); err == nil {
+				return c.fetchMe(newToken, false)
+			}
 		}
+		c.Keyring.ClearToken()
+		return notLoggedInMsg{}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errMsg{fmt.Errorf("API error for /me: %s (%s)", resp.Status, string(body))}
+	}
 
-		var user models.User
-		if err := json.Unmarshal(body, &user); err != nil {
-			return errMsg{fmt.Errorf("failed to unmarshal user from /me: %w", err)}
-		}
-		return userFetchedMsg{user: &user}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errMsg{fmt.Errorf("failed to read /me response body: %w", err)}
 	}
+
+	var user models.User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return errMsg{fmt.Errorf("failed to unmarshal user from /me: %w", err)}
+	}
+	return userFetchedMsg{user: &user}
 }
 
-func NewAPIClient(baseURL string) *APIClient {
+func NewAPIClient(baseURL, profile string) *APIClient {
 	return &APIClient{
 		BaseURL: baseURL,
 		Client:  &http.Client{Timeout: 10 * time.Second},
-		Keyring: services.KeyringService{},
+		Keyring: services.KeyringService{Profile: profile},
 	}
 }
 
@@ -83,13 +90,30 @@ func (c *APIClient) DoAuthenticatedRequest(method, endpoint string, payload inte
 		return nil, fmt.Errorf("not authenticated: %w", err)
 	}
 
-	var body io.Reader
+	var jsonPayload []byte
 	if payload != nil {
-		jsonPayload, err := json.Marshal(payload)
+		jsonPayload, err = json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonPayload)
+	}
+
+	resp, err := c.doWithToken(method, endpoint, jsonPayload, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	token, refreshErr := services.RefreshStoredSession(c.Client, c.BaseURL, &c.Keyring)
+	if refreshErr != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return c.doWithToken(method, endpoint, jsonPayload, token)
+}
+
+func (c *APIClient) doWithToken(method, endpoint string, jsonPayload []byte, token string) (*http.Response, error) {
+	var body io.Reader
+	if jsonPayload != nil {
+		body = bytes.NewReader(jsonPayload)
 	}
 
 	req, err := http.NewRequest(method, c.BaseURL+endpoint, body)
@@ -154,9 +178,22 @@ func (c *APIClient) claimTaskCmd(taskID, userID uint) tea.Cmd {
 	}
 }
 
-func (c *APIClient) fetchTasksCmd() tea.Cmd {
+// fetchTasksCmd searches tasks with the given GET /tasks parameters. An empty
+// cursor fetches the first page.
+func (c *APIClient) fetchTasksCmd(query url.Values, cursor string) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := c.Client.Get(c.BaseURL + "/tasks")
+		params := url.Values{}
+		for key, values := range query {
+			params[key] = values
+		}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+		endpoint := c.BaseURL + "/tasks"
+		if len(params) > 0 {
+			endpoint += "?" + params.Encode()
+		}
+		resp, err := c.Client.Get(endpoint)
 		if err != nil {
 			return errMsg{fmt.Errorf("failed to connect to server: %w", err)}
 		}
@@ -176,34 +213,97 @@ func (c *APIClient) fetchTasksCmd() tea.Cmd {
 		if err := json.Unmarshal(body, &tasks); err != nil {
 			return errMsg{fmt.Errorf("failed to unmarshal tasks: %w", err)}
 		}
-		return tasks
+		return taskPageMsg{tasks: tasks, nextCursor: resp.Header.Get("X-Next-Cursor"), appended: cursor != ""}
 	}
 }
 
 func (c *APIClient) fetchProjectTasksCmd(projectID uint) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("%s/tasks?project_id=%d", c.BaseURL, projectID)
+		params := url.Values{}
+		params.Set("project_id", strconv.FormatUint(uint64(projectID), 10))
+		params.Set("limit", strconv.Itoa(services.MaxTaskPageSize))
+
+		// The project view shows every task, so follow X-Next-Cursor until
+		// the last page.
+		tasks := []models.Task{}
+		for {
+			resp, err := c.Client.Get(c.BaseURL + "/tasks?" + params.Encode())
+			if err != nil {
+				return errMsg{fmt.Errorf("failed to connect to server: %w", err)}
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return errMsg{fmt.Errorf("failed to read response body: %w", err)}
+			}
+			if resp.StatusCode != http.StatusOK {
+				return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
+			}
+
+			var page []models.Task
+			if err := json.Unmarshal(body, &page); err != nil {
+				return errMsg{fmt.Errorf("failed to unmarshal project tasks: %w", err)}
+			}
+			tasks = append(tasks, page...)
+
+			cursor := resp.Header.Get("X-Next-Cursor")
+			if cursor == "" {
+				return tasks
+			}
+			params.Set("cursor", cursor)
+		}
+	}
+}
+
+func (c *APIClient) fetchMilestonesCmd(projectID uint) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("%s/milestones?project_id=%d", c.BaseURL, projectID)
 		resp, err := c.Client.Get(url)
 		if err != nil {
 			return errMsg{fmt.Errorf("failed to connect to server: %w", err)}
 		}
 		defer resp.Body.Close()
 
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to read response body: %w", err)}
+		}
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
 			return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
 		}
 
+		var milestones []models.Milestone
+		if err := json.Unmarshal(body, &milestones); err != nil {
+			return errMsg{fmt.Errorf("failed to unmarshal milestones: %w", err)}
+		}
+		return milestones
+	}
+}
+
+// fetchTaskCommentsCmd loads the discussion thread shown in the task details
+// pane.
+func (c *APIClient) fetchTaskCommentsCmd(taskID uint) tea.Cmd {
+	return func() tea.Msg {
+		url := fmt.Sprintf("%s/tasks/%d/comments", c.BaseURL, taskID)
+		resp, err := c.Client.Get(url)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to connect to server: %w", err)}
+		}
+		defer resp.Body.Close()
+
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return errMsg{fmt.Errorf("failed to read response body: %w", err)}
 		}
+		if resp.StatusCode != http.StatusOK {
+			return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
+		}
 
-		var tasks []models.Task
-		if err := json.Unmarshal(body, &tasks); err != nil {
-			return errMsg{fmt.Errorf("failed to unmarshal project tasks: %w", err)}
+		var comments []models.Comment
+		if err := json.Unmarshal(body, &comments); err != nil {
+			return errMsg{fmt.Errorf("failed to unmarshal comments: %w", err)}
 		}
-		return tasks
+		return taskCommentsMsg{taskID: taskID, comments: comments}
 	}
 }
 
@@ -347,8 +447,7 @@ func (c *APIClient) createTaskFormCmd(
 
 func (c *APIClient) createProjectCmd(title, shortDesc, repoURL, visibility string, tags []string) tea.Cmd {
 	return func() tea.Msg {
-		keyring := services.KeyringService{}
-		token, err := keyring.GetToken()
+		token, err := c.Keyring.GetToken()
 		if err != nil {
 			return errMsg{fmt.Errorf("authentication required to create project: %w", err)}
 		}
@@ -395,4 +494,77 @@ func (c *APIClient) createProjectCmd(title, shortDesc, repoURL, visibility strin
 
 		return projectCreatedMsg{projectID: createdProject.ID}
 	}
-}
\ No newline at end of file
+}
+func (c *APIClient) fetchPendingClaimsCmd() tea.Cmd {
+	return func() tea.Msg {
+		resp, err := c.DoAuthenticatedRequest(http.MethodGet, "/api/claims/pending", nil)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to fetch pending claims: %w", err)}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			return errMsg{fmt.Errorf("authentication required to review claims")}
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to read response body: %w", err)}
+		}
+		if resp.StatusCode != http.StatusOK {
+			return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
+		}
+
+		var claims []models.Claim
+		if err := json.Unmarshal(body, &claims); err != nil {
+			return errMsg{fmt.Errorf("failed to unmarshal claims: %w", err)}
+		}
+		return claims
+	}
+}
+
+// fetchRecommendationsCmd loads the tasks shown in the landing view's "For
+// you" panel.
+func (c *APIClient) fetchRecommendationsCmd() tea.Cmd {
+	return func() tea.Msg {
+		resp, err := c.DoAuthenticatedRequest(http.MethodGet, "/api/users/me/recommended-tasks?limit=5", nil)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to fetch recommended tasks: %w", err)}
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to read response body: %w", err)}
+		}
+		if resp.StatusCode != http.StatusOK {
+			return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
+		}
+
+		var recommendations []models.TaskRecommendation
+		if err := json.Unmarshal(body, &recommendations); err != nil {
+			return errMsg{fmt.Errorf("failed to unmarshal recommended tasks: %w", err)}
+		}
+		return recommendationsMsg{recommendations: recommendations}
+	}
+}
+
+// reviewClaimCmd accepts or rejects a pending claim.
+func (c *APIClient) reviewClaimCmd(claimID uint, accept bool) tea.Cmd {
+	return func() tea.Msg {
+		action := "reject"
+		if accept {
+			action = "accept"
+		}
+		resp, err := c.DoAuthenticatedRequest(http.MethodPut, fmt.Sprintf("/api/claims/%d/%s", claimID, action), map[string]string{})
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to %s claim: %w", action, err)}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return errMsg{fmt.Errorf("API error: %s (%s)", resp.Status, string(body))}
+		}
+		return claimReviewedMsg{claimID: claimID, accepted: accept}
+	}
+}
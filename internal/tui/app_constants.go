@@ -19,9 +19,15 @@ const (
 	viewMyContributions
 	viewReviewContributions
 	viewMyWallet
+	viewPendingClaims
+	viewMilestones
 )
 
 type taskClaimedMsg struct{ taskID uint }
+type claimReviewedMsg struct {
+	claimID  uint
+	accepted bool
+}
 type contributionSubmittedMsg struct{ taskID uint }
 type taskCreatedMsg struct{ taskID uint }
 type projectCreatedMsg struct{ projectID uint }
@@ -36,6 +42,18 @@ type bountyFundedMsg struct {
 	taskID uint
 	amount float64
 }
+// taskPageMsg is one page of the task search. Pages fetched with a cursor are
+// appended to the list instead of replacing it.
+type taskPageMsg struct {
+	tasks      []models.Task
+	nextCursor string
+	appended   bool
+}
+type taskCommentsMsg struct {
+	taskID   uint
+	comments []models.Comment
+}
+type recommendationsMsg struct{ recommendations []models.TaskRecommendation }
 type userFetchedMsg struct{ user *models.User }
 type notLoggedInMsg struct{}
 type startLoginFlowMsg struct{}
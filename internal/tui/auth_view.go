@@ -63,6 +63,15 @@ func (m model) updateLandingView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = viewReviewContributions
 			return m, nil
 
+		case "f": // Refresh the "For you" suggestions (protected)
+			if m.loggedInUser == nil {
+				m.status = statusMessageStyle("Please login first to get task suggestions.")
+				m.state = viewAuth
+				return m, nil
+			}
+			m.status = statusMessageStyle("Refreshing suggestions...")
+			return m, m.apiClient.fetchRecommendationsCmd()
+
 		case "w": // My wallet (protected)
 			if m.loggedInUser == nil {
 				m.status = statusMessageStyle("Please login first to view your wallet.")
@@ -273,6 +282,13 @@ func (m model) viewLandingView() string {
 		}
 		protectedContent := strings.Join(protLines, "\n")
 		protectedCard := protectedCardStyle.Render(protectedContent)
+		if forYouCard := m.renderForYouCard(cardWidth, compactMode); forYouCard != "" {
+			if cardWidth < m.width-20 {
+				protectedCard = lipgloss.JoinHorizontal(lipgloss.Top, protectedCard, forYouCard)
+			} else {
+				protectedCard += "\n" + forYouCard
+			}
+		}
 		if cardWidth < m.width-20 {
 			aLines := strings.Count(authCard, "\n")
 			pLines := strings.Count(publicCard, "\n")
@@ -353,6 +369,37 @@ func (m model) viewLandingView() string {
 	return b.String()
 }
 
+// renderForYouCard lists the tasks recommended to the logged-in user, or
+// returns "" when there are none.
+func (m model) renderForYouCard(width int, compact bool) string {
+	if len(m.recommendations) == 0 {
+		return ""
+	}
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(pink).
+		Margin(0, 1).
+		Width(width)
+	if compact {
+		style = style.Padding(0, 1)
+	} else {
+		style = style.Padding(1, 1)
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(pink).Render("For you"),
+		"",
+	}
+	for _, rec := range m.recommendations {
+		lines = append(lines, fmt.Sprintf("#%d %s (%.0f%%)", rec.Task.ID, rec.Task.Title, rec.Score))
+		if len(rec.Reasons) > 0 && !compact {
+			lines = append(lines, lipgloss.NewStyle().Foreground(gray).Render("  "+rec.Reasons[0]))
+		}
+	}
+	lines = append(lines, "", "[f] Refresh suggestions")
+	return style.Render(strings.Join(lines, "\n"))
+}
+
 func (m model) viewAuthView() string {
 	var b strings.Builder
 
@@ -78,6 +78,20 @@ func (m model) updateManageProjectsView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = statusMessageStyle(fmt.Sprintf("Creating task for project '%s'", project.Title))
 			return m, textinput.Blink
 
+		case "m":
+			selectedItem := m.projectsList.SelectedItem()
+			if selectedItem == nil {
+				m.status = statusMessageStyle("Select a project first to see its milestones.")
+				return m, nil
+			}
+			return m.openMilestones(selectedItem.(projectItem).Project)
+
+		case "a":
+			m.state = viewPendingClaims
+			m.loading = true
+			m.status = statusMessageStyle("Loading claims waiting for your approval...")
+			return m, m.apiClient.fetchPendingClaimsCmd()
+
 		case "f":
 			selectedItem := m.projectsList.SelectedItem()
 			if selectedItem == nil {
@@ -138,7 +152,7 @@ func (m model) viewManageProjectsView() string {
 		Align(lipgloss.Left).
 		Render(m.status)
 
-	helpText := "↑/k up • ↓/j down • enter view tasks • p create project • t create task • f fund bounty • r refresh • esc back"
+	helpText := "↑/k up • ↓/j down • enter view tasks • p create project • t create task • f fund bounty • m milestones • a review claims • r refresh • esc back"
 	help := lipgloss.NewStyle().
 		Width(m.width).
 		Align(lipgloss.Center).
@@ -253,6 +267,7 @@ func (m model) renderActionsPanel(width, height int) string {
 		{"t", "Create Task", yellow, m.currentProject != nil},
 		{"f", "Fund Bounties", pink, m.currentProject != nil},
 		{"enter", "View Tasks", blue, m.currentProject != nil},
+		{"a", "Review Claims", purple, true},
 	}
 
 	for _, action := range actions {
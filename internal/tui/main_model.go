@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"net/url"
 	"ossyne/internal/models"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -17,8 +18,15 @@ type model struct {
 	tasksList          list.Model
 	projectsList       list.Model
 	projectTasksList   list.Model
+	pendingClaimsList  list.Model
+	milestonesList     list.Model
 	currentTask        *models.Task
 	currentProject     *models.Project
+	taskComments       []models.Comment
+	taskQuery          url.Values
+	tasksNextCursor    string
+	recommendations    []models.TaskRecommendation
+	commentsTaskID     uint
 	spinner            spinner.Model
 	status             string
 	err                error
@@ -38,7 +46,7 @@ type model struct {
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		checkLoginStatusCmd(),
+		m.apiClient.checkLoginStatusCmd(),
 	)
 }
 
@@ -52,6 +60,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tasksList.SetSize(listWidth, max(5, listHeight-textInputHeight))
 		m.projectsList.SetSize(listWidth, listHeight)
 		m.projectTasksList.SetSize(listWidth, listHeight)
+		m.pendingClaimsList.SetSize(listWidth, listHeight)
+		m.milestonesList.SetSize(listWidth, listHeight)
 		m.filterInput.Width = listWidth
 		return m, nil
 
@@ -71,6 +81,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = viewLanding
 		m.loading = false
 		m.status = statusMessageStyle(fmt.Sprintf("Welcome back, %s!", m.loggedInUser.Username))
+		return m, m.apiClient.fetchRecommendationsCmd()
+
+	case recommendationsMsg:
+		m.recommendations = msg.recommendations
 		return m, nil
 
 	case startLoginFlowMsg:
@@ -96,7 +110,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.loading = true
 			m.status = statusMessageStyle(fmt.Sprintf("Contribution for task %d submitted! Refreshing...", msg.taskID))
-			return m, m.apiClient.fetchTasksCmd()
+			return m, m.apiClient.fetchTasksCmd(m.taskQuery, "")
 		} else {
 			m.loading = true
 			m.status = statusMessageStyle(fmt.Sprintf("Contribution for task %d submitted! Refreshing...", msg.taskID))
@@ -131,6 +145,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status = statusMessageStyle(fmt.Sprintf("Bounty $%.2f funded for task %d!", msg.amount, msg.taskID))
 		return m, nil
 
+	case taskCommentsMsg:
+		m.taskComments = msg.comments
+		m.commentsTaskID = msg.taskID
+		return m, nil
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		if m.loading {
@@ -258,6 +277,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateReviewContributionsView(msg)
 	case viewMyWallet:
 		return m.updateMyWalletView(msg)
+	case viewPendingClaims:
+		return m.updatePendingClaimsView(msg)
+	case viewMilestones:
+		return m.updateMilestonesView(msg)
 	}
 
 	return m, nil
@@ -295,6 +318,10 @@ func (m model) View() string {
 		return appStyle.Render(m.viewReviewContributionsView())
 	case viewMyWallet:
 		return appStyle.Render(m.viewMyWalletView())
+	case viewPendingClaims:
+		return appStyle.Render(m.viewPendingClaimsView())
+	case viewMilestones:
+		return appStyle.Render(m.viewMilestonesView())
 	default:
 		return appStyle.Render(m.viewTasksView())
 	}
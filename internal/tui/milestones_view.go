@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"ossyne/internal/models"
+	"strings"
+	"time"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type milestoneItem struct {
+	models.Milestone
+}
+
+func (i milestoneItem) FilterValue() string { return i.Milestone.Title }
+func (i milestoneItem) Title() string       { return i.Milestone.Title }
+func (i milestoneItem) Description() string {
+	due := "No due date"
+	if i.DueDate != nil {
+		due = "Due " + i.DueDate.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s | %.0f%% done | %s", strings.ToTitle(i.Kind), i.Progress.Percent, due)
+}
+
+var _ list.Item = milestoneItem{}
+
+// openMilestones switches to the milestones panel for project, remembering
+// the view to return to.
+func (m model) openMilestones(project models.Project) (tea.Model, tea.Cmd) {
+	m.currentProject = &project
+	m.previousView = m.state
+	m.state = viewMilestones
+	m.milestonesList.SetItems(nil)
+	m.loading = true
+	m.status = statusMessageStyle(fmt.Sprintf("Loading milestones of project '%s'...", project.Title))
+	return m, m.apiClient.fetchMilestonesCmd(project.ID)
+}
+
+func (m model) updateMilestonesView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case errMsg:
+		m.err = msg.err
+		m.status = statusMessageStyle(fmt.Sprintf("Error: %v", msg.err))
+		m.loading = false
+		return m, nil
+
+	case []models.Milestone:
+		items := make([]list.Item, len(msg))
+		for i, milestone := range msg {
+			items[i] = milestoneItem{milestone}
+		}
+		m.milestonesList.SetItems(items)
+		m.err = nil
+		m.loading = false
+		m.status = statusMessageStyle(fmt.Sprintf("%d milestones in this project.", len(msg)))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.loading || m.milestonesList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch msg.String() {
+		case "esc", "b":
+			m.state = m.previousView
+			m.status = statusMessageStyle("Returned to projects.")
+			return m, nil
+
+		case "r":
+			if m.currentProject == nil {
+				return m, nil
+			}
+			m.loading = true
+			m.status = statusMessageStyle("Refreshing milestones...")
+			return m, m.apiClient.fetchMilestonesCmd(m.currentProject.ID)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.milestonesList, cmd = m.milestonesList.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewMilestonesView() string {
+	spinnerView := ""
+	if m.loading {
+		spinnerView = " " + m.spinner.View() + " Loading..."
+	} else if m.err != nil {
+		spinnerView = " " + lipgloss.NewStyle().Foreground(red).Render(m.err.Error())
+	}
+	title := " Milestones"
+	if m.currentProject != nil {
+		title = fmt.Sprintf(" Milestones of '%s'", m.currentProject.Title)
+	}
+	header := titleStyle.Render(title + spinnerView)
+
+	panelWidth := (m.width-appStyle.GetHorizontalFrameSize())/2 - 1
+	leftPanel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Height(m.height - appStyle.GetVerticalFrameSize() - 6).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(green).
+		Padding(1).
+		Render(m.milestonesList.View())
+
+	details := lipgloss.NewStyle().Foreground(gray).Italic(true).Render("This project has no milestones yet.")
+	if selectedItem := m.milestonesList.SelectedItem(); selectedItem != nil {
+		details = m.renderMilestoneDetails(selectedItem.(milestoneItem).Milestone, panelWidth-4)
+	}
+	rightPanel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Height(m.height - appStyle.GetVerticalFrameSize() - 4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(1).
+		Render(details)
+
+	helpText := "↑/k up • ↓/j down • / filter • r refresh • esc back"
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel),
+		lipgloss.NewStyle().Width(m.width-appStyle.GetHorizontalFrameSize()).Align(lipgloss.Left).Render(m.status),
+		lipgloss.NewStyle().Width(m.width-appStyle.GetHorizontalFrameSize()).Align(lipgloss.Center).Foreground(gray).Render(helpText),
+	)
+}
+
+func (m model) renderMilestoneDetails(milestone models.Milestone, width int) string {
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(green).Render(milestone.Title))
+	sb.WriteString("\n\n")
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Kind: "))
+	sb.WriteString(fmt.Sprintf("%s (ID: %d)\n", strings.ToTitle(milestone.Kind), milestone.ID))
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Due: "))
+	switch {
+	case milestone.DueDate == nil:
+		sb.WriteString("No due date\n")
+	case milestone.IsOverdue(time.Now()):
+		sb.WriteString(lipgloss.NewStyle().Foreground(red).Render(milestone.DueDate.Format("2006-01-02") + " (overdue)"))
+		sb.WriteString("\n")
+	default:
+		sb.WriteString(milestone.DueDate.Format("2006-01-02") + "\n")
+	}
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Tasks: "))
+	sb.WriteString(fmt.Sprintf("%d of %d completed\n", milestone.Progress.CompletedTasks, milestone.Progress.TotalTasks))
+	if milestone.Progress.BountyTotal > 0 {
+		sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Bounties: "))
+		sb.WriteString(fmt.Sprintf("$%.2f\n", milestone.Progress.BountyTotal))
+	}
+	sb.WriteString("\n")
+
+	barWidth := max(10, width-8)
+	filled := min(barWidth, int(milestone.Progress.Percent/100*float64(barWidth)))
+	sb.WriteString(lipgloss.NewStyle().Foreground(green).Render(strings.Repeat("█", filled)))
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render(strings.Repeat("░", barWidth-filled)))
+	sb.WriteString(fmt.Sprintf(" %3.0f%%\n", milestone.Progress.Percent))
+
+	if milestone.Description != "" {
+		sb.WriteString("\n")
+		sb.WriteString(milestone.Description)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"fmt"
+	"ossyne/internal/models"
+	"strings"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type claimItem struct {
+	models.Claim
+}
+
+func (i claimItem) FilterValue() string { return i.Claim.Task.Title + " " + i.Claim.User.Username }
+func (i claimItem) Title() string       { return i.Claim.Task.Title }
+func (i claimItem) Description() string {
+	mentor := ""
+	if i.Claim.MentorID != nil {
+		mentor = " | Mentor: " + i.Claim.Mentor.Username
+	}
+	return fmt.Sprintf("Claim ID: %d | By: %s | Task ID: %d%s", i.Claim.ID, i.Claim.User.Username, i.Claim.TaskID, mentor)
+}
+
+var _ list.Item = claimItem{}
+
+func (m model) updatePendingClaimsView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case errMsg:
+		m.err = msg.err
+		m.status = statusMessageStyle(fmt.Sprintf("Error: %v", msg.err))
+		m.loading = false
+		return m, nil
+
+	case []models.Claim:
+		items := make([]list.Item, len(msg))
+		for i, claim := range msg {
+			items[i] = claimItem{claim}
+		}
+		m.pendingClaimsList.SetItems(items)
+		m.err = nil
+		m.loading = false
+		m.status = statusMessageStyle(fmt.Sprintf("%d claims waiting for your approval.", len(msg)))
+		return m, nil
+
+	case claimReviewedMsg:
+		verdict := "rejected"
+		if msg.accepted {
+			verdict = "accepted; other claims on the task were rejected"
+		}
+		m.status = statusMessageStyle(fmt.Sprintf("Claim %d %s. Refreshing...", msg.claimID, verdict))
+		return m, m.apiClient.fetchPendingClaimsCmd()
+
+	case tea.KeyMsg:
+		if m.loading || m.pendingClaimsList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch keypress := msg.String(); keypress {
+		case "esc", "b":
+			m.state = viewManageProjects
+			m.status = statusMessageStyle("Returned to project management.")
+			return m, nil
+
+		case "r":
+			m.loading = true
+			m.status = statusMessageStyle("Refreshing pending claims...")
+			return m, m.apiClient.fetchPendingClaimsCmd()
+
+		case "a", "x":
+			selectedItem := m.pendingClaimsList.SelectedItem()
+			if selectedItem == nil {
+				m.status = statusMessageStyle("No claim selected")
+				return m, nil
+			}
+			claim := selectedItem.(claimItem).Claim
+			accept := keypress == "a"
+			m.loading = true
+			if accept {
+				m.status = statusMessageStyle(fmt.Sprintf("Accepting claim %d...", claim.ID))
+			} else {
+				m.status = statusMessageStyle(fmt.Sprintf("Rejecting claim %d...", claim.ID))
+			}
+			return m, m.apiClient.reviewClaimCmd(claim.ID, accept)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.pendingClaimsList, cmd = m.pendingClaimsList.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewPendingClaimsView() string {
+	spinnerView := ""
+	if m.loading {
+		spinnerView = " " + m.spinner.View() + " Loading..."
+	} else if m.err != nil {
+		spinnerView = " " + lipgloss.NewStyle().Foreground(red).Render(m.err.Error())
+	}
+	header := titleStyle.Render(" Claims Awaiting Approval" + spinnerView)
+
+	panelWidth := (m.width-appStyle.GetHorizontalFrameSize())/2 - 1
+	leftPanel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Height(m.height - appStyle.GetVerticalFrameSize() - 6).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(yellow).
+		Padding(1).
+		Render(m.pendingClaimsList.View())
+
+	details := lipgloss.NewStyle().Foreground(gray).Italic(true).Render("No claims are waiting for your approval.")
+	if selectedItem := m.pendingClaimsList.SelectedItem(); selectedItem != nil {
+		details = m.renderClaimDetails(selectedItem.(claimItem).Claim)
+	}
+	rightPanel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Height(m.height - appStyle.GetVerticalFrameSize() - 4).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(1).
+		Render(details)
+
+	helpText := "↑/k up • ↓/j down • a accept • x reject • r refresh • esc back to projects"
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		header,
+		lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel),
+		lipgloss.NewStyle().Width(m.width-appStyle.GetHorizontalFrameSize()).Align(lipgloss.Left).Render(m.status),
+		lipgloss.NewStyle().Width(m.width-appStyle.GetHorizontalFrameSize()).Align(lipgloss.Center).Foreground(gray).Render(helpText),
+	)
+}
+
+func (m model) renderClaimDetails(claim models.Claim) string {
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(yellow).Render(claim.Task.Title))
+	sb.WriteString("\n\n")
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Claim ID: "))
+	sb.WriteString(fmt.Sprintf("%d\n", claim.ID))
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Contributor: "))
+	sb.WriteString(fmt.Sprintf("%s (ID: %d)\n", claim.User.Username, claim.UserID))
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Claimed: "))
+	sb.WriteString(fmt.Sprintf("%s\n", claim.ClaimDate.Format("2006-01-02 15:04")))
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Task ID: "))
+	sb.WriteString(fmt.Sprintf("%d (Project ID: %d)\n", claim.TaskID, claim.Task.ProjectID))
+	if claim.Notes != "" {
+		sb.WriteString("\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(gray).Render("Notes: "))
+		sb.WriteString(claim.Notes)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(lipgloss.NewStyle().Foreground(gray).Italic(true).Render("Accepting locks the task for this contributor and rejects every other pending claim on it."))
+	return sb.String()
+}
@@ -30,9 +30,9 @@ func (m model) updateProjectTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				task := selectedItem.(taskItem).Task
 				m.currentTask = &task
 				m.status = statusMessageStyle(fmt.Sprintf("Selected task ID: %d", m.currentTask.ID))
-			} else {
-				m.status = statusMessageStyle("No task selected")
+				return m, m.apiClient.fetchTaskCommentsCmd(task.ID)
 			}
+			m.status = statusMessageStyle("No task selected")
 			return m, nil
 		case "esc", "p":
 			m.state = m.previousView
@@ -64,10 +64,12 @@ func (m model) updateProjectTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			selectedItem := m.projectTasksList.SelectedItem()
 			if selectedItem != nil {
 				task := selectedItem.(taskItem).Task
-				if task.Status == models.TaskStatusOpen {
+				if task.AcceptsClaims() {
 					m.loading = true
 					m.status = statusMessageStyle(fmt.Sprintf("Claiming task %d...", task.ID))
 					return m, m.apiClient.claimTaskCmd(task.ID, m.loggedInUser.ID)
+				} else if task.IsBlocked() {
+					m.status = statusMessageStyle(fmt.Sprintf("Cannot claim task %d: blocked by tasks %v", task.ID, task.BlockedBy))
 				} else {
 					m.status = statusMessageStyle(fmt.Sprintf("Cannot claim task %d (Status: %s)", task.ID, strings.ToTitle(task.Status)))
 				}
@@ -48,6 +48,14 @@ func (m model) updateProjectsView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "m":
+			selectedItem := m.projectsList.SelectedItem()
+			if selectedItem == nil {
+				m.status = statusMessageStyle("No project selected")
+				return m, nil
+			}
+			return m.openMilestones(selectedItem.(projectItem).Project)
+
 		case "esc", "b":
 			m.state = viewLanding
 			m.projectsList.FilterInput.Blur()
@@ -111,7 +119,7 @@ func (m model) viewProjectsView() string {
 		BorderForeground(yellow).
 		Padding(1).
 		Render(projectDetails)
-	helpText := "↑/k up • ↓/j down • enter view tasks • r refresh • esc back to landing"
+	helpText := "↑/k up • ↓/j down • enter view tasks • m milestones • r refresh • esc back to landing"
 	ui := lipgloss.JoinVertical(
 		lipgloss.Top,
 		header,
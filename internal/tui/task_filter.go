@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// taskFilterHelp summarises the filter syntax for the help line.
+const taskFilterHelp = "words • tag:go • skill:sql • difficulty:easy • status:open • project:3 • bounty>=50 • hours<=8 • sort:-bounty_amount"
+
+// taskFilterKeys maps "key:value" filter terms to GET /tasks parameters.
+// List parameters collect every term given for them.
+var taskFilterKeys = map[string]string{
+	"tag":        "tags",
+	"skill":      "skills",
+	"difficulty": "difficulty",
+	"status":     "status",
+	"project":    "project_id",
+	"milestone":  "milestone_id",
+	"sort":       "sort",
+}
+
+// taskFilterRanges maps "field>=n" and "field<=n" filter terms to GET /tasks
+// parameters.
+var taskFilterRanges = map[string][2]string{
+	"bounty": {"min_bounty", "max_bounty"},
+	"hours":  {"min_hours", "max_hours"},
+}
+
+// parseTaskFilter turns the text of the task filter input into search
+// parameters. Terms it does not recognise are searched for as text.
+func parseTaskFilter(input string) (url.Values, error) {
+	params := url.Values{}
+	var text []string
+	for _, term := range strings.Fields(input) {
+		if field, bound, value, ok := splitRangeTerm(term); ok {
+			names, known := taskFilterRanges[field]
+			if !known {
+				return nil, fmt.Errorf("unknown range filter %q, expected bounty or hours", field)
+			}
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("%s needs a number, got %q", term, value)
+			}
+			params.Set(names[bound], value)
+			continue
+		}
+		if key, value, ok := strings.Cut(term, ":"); ok && value != "" {
+			if param, known := taskFilterKeys[strings.ToLower(key)]; known {
+				switch param {
+				case "tags", "skills", "difficulty":
+					params.Add(param, value)
+				default:
+					params.Set(param, value)
+				}
+				continue
+			}
+		}
+		text = append(text, term)
+	}
+	if len(text) > 0 {
+		params.Set("q", strings.Join(text, " "))
+	}
+	return params, nil
+}
+
+// splitRangeTerm splits "bounty>=50" into ("bounty", 0, "50") and "hours<=8"
+// into ("hours", 1, "8"); bound is 0 for a minimum and 1 for a maximum.
+func splitRangeTerm(term string) (field string, bound int, value string, ok bool) {
+	if field, value, ok = strings.Cut(term, ">="); ok {
+		return strings.ToLower(field), 0, value, field != "" && value != ""
+	}
+	if field, value, ok = strings.Cut(term, "<="); ok {
+		return strings.ToLower(field), 1, value, field != "" && value != ""
+	}
+	return "", 0, "", false
+}
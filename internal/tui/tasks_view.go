@@ -21,7 +21,11 @@ func (i taskItem) Description() string {
 	if i.BountyAmount > 0 {
 		bounty = fmt.Sprintf(" ($%.2f)", i.BountyAmount)
 	}
-	return fmt.Sprintf("Project ID: %d | Status: %s%s", i.ProjectID, strings.ToTitle(i.Status), bounty)
+	blocked := ""
+	if i.IsBlocked() {
+		blocked = " | BLOCKED"
+	}
+	return fmt.Sprintf("Project ID: %d | Status: %s%s%s", i.ProjectID, strings.ToTitle(i.Status), bounty, blocked)
 }
 
 func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -34,13 +38,21 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		return m, nil
 
-	case []models.Task:
-		items := make([]list.Item, len(msg))
-		for i, task := range msg {
-			items[i] = taskItem{task}
+	case taskPageMsg:
+		var items []list.Item
+		if msg.appended {
+			items = m.tasksList.Items()
+		}
+		for _, task := range msg.tasks {
+			items = append(items, taskItem{task})
 		}
 		m.tasksList.SetItems(items)
-		m.status = statusMessageStyle(fmt.Sprintf("Fetched %d tasks.", len(msg)))
+		m.tasksNextCursor = msg.nextCursor
+		more := ""
+		if msg.nextCursor != "" {
+			more = " Press n for more."
+		}
+		m.status = statusMessageStyle(fmt.Sprintf("Showing %d tasks.%s", len(items), more))
 		m.loading = false
 		if m.currentTask != nil {
 			found := false
@@ -71,14 +83,43 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case taskClaimedMsg:
 		m.loading = true
-		m.status = statusMessageStyle(fmt.Sprintf("Task %d claimed! Refreshing list...", msg.taskID))
-		return m, m.apiClient.fetchTasksCmd()
+		m.status = statusMessageStyle(fmt.Sprintf("Claim on task %d sent for maintainer approval. Refreshing list...", msg.taskID))
+		return m, m.apiClient.fetchTasksCmd(m.taskQuery, "")
 
 	case tea.KeyMsg:
 		if m.loading {
 			return m, nil
 		}
 
+		if m.filterInput.Focused() {
+			switch msg.String() {
+			case "enter":
+				query, err := parseTaskFilter(m.filterInput.Value())
+				if err != nil {
+					m.status = statusMessageStyle(fmt.Sprintf("Invalid filter: %v", err))
+					return m, nil
+				}
+				m.filterInput.Blur()
+				m.taskQuery = query
+				m.loading = true
+				m.status = statusMessageStyle("Searching tasks...")
+				return m, m.apiClient.fetchTasksCmd(m.taskQuery, "")
+			case "esc":
+				m.filterInput.Blur()
+				m.filterInput.SetValue("")
+				if len(m.taskQuery) == 0 {
+					return m, nil
+				}
+				m.taskQuery = nil
+				m.loading = true
+				m.status = statusMessageStyle("Filter cleared. Refreshing tasks...")
+				return m, m.apiClient.fetchTasksCmd(nil, "")
+			}
+			var cmd tea.Cmd
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			return m, cmd
+		}
+
 		switch keypress := msg.String(); keypress {
 		case "enter":
 			selectedItem := m.tasksList.SelectedItem()
@@ -86,28 +127,29 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				task := selectedItem.(taskItem).Task
 				m.currentTask = &task
 				m.status = statusMessageStyle(fmt.Sprintf("Selected task ID: %d", m.currentTask.ID))
-			} else {
-				m.status = statusMessageStyle("No task selected")
+				return m, m.apiClient.fetchTaskCommentsCmd(task.ID)
 			}
+			m.status = statusMessageStyle("No task selected")
 			return m, nil
 
 		case "/":
-			if !m.filterInput.Focused() {
-				m.filterInput.Focus()
-				return m, textinput.Blink
-			}
+			m.filterInput.Focus()
+			m.status = statusMessageStyle("Filter: " + taskFilterHelp)
+			return m, textinput.Blink
 
-		case "esc":
-			if m.filterInput.Focused() {
-				m.filterInput.Blur()
-				m.filterInput.SetValue("")
-				m.tasksList.FilterInput.SetValue("")
-				return m, nil
-			} else {
-				m.state = viewLanding
-				m.status = statusMessageStyle("Returned to landing page.")
+		case "n":
+			if m.tasksNextCursor == "" {
+				m.status = statusMessageStyle("No more tasks match.")
 				return m, nil
 			}
+			m.loading = true
+			m.status = statusMessageStyle("Loading more tasks...")
+			return m, m.apiClient.fetchTasksCmd(m.taskQuery, m.tasksNextCursor)
+
+		case "esc":
+			m.state = viewLanding
+			m.status = statusMessageStyle("Returned to landing page.")
+			return m, nil
 
 		case "c":
 			if m.loggedInUser == nil {
@@ -118,10 +160,12 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			selectedItem := m.tasksList.SelectedItem()
 			if selectedItem != nil {
 				task := selectedItem.(taskItem).Task
-				if task.Status == models.TaskStatusOpen {
+				if task.AcceptsClaims() {
 					m.loading = true
 					m.status = statusMessageStyle(fmt.Sprintf("Claiming task %d...", task.ID))
 					return m, m.apiClient.claimTaskCmd(task.ID, m.loggedInUser.ID)
+				} else if task.IsBlocked() {
+					m.status = statusMessageStyle(fmt.Sprintf("Cannot claim task %d: blocked by tasks %v", task.ID, task.BlockedBy))
 				} else {
 					m.status = statusMessageStyle(fmt.Sprintf("Cannot claim task %d (Status: %s)", task.ID, strings.ToTitle(task.Status)))
 				}
@@ -154,18 +198,15 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "p":
-			if !m.filterInput.Focused() {
-				m.state = viewProjects
-				m.loading = true
-				m.status = statusMessageStyle("Loading projects...")
-				return m, m.apiClient.fetchUserProjectsCmd(1)
-			}
-			return m, nil
+			m.state = viewProjects
+			m.loading = true
+			m.status = statusMessageStyle("Loading projects...")
+			return m, m.apiClient.fetchUserProjectsCmd(1)
 
 		case "r":
 			m.loading = true
 			m.status = statusMessageStyle("Refreshing tasks...")
-			return m, m.apiClient.fetchTasksCmd()
+			return m, m.apiClient.fetchTasksCmd(m.taskQuery, "")
 		}
 	}
 
@@ -175,7 +216,6 @@ func (m model) updateTasksView(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	newFilterInputModel, filterInputCmd := m.filterInput.Update(msg)
 	m.filterInput = newFilterInputModel
-	m.tasksList.FilterInput.SetValue(m.filterInput.Value())
 	cmds = append(cmds, filterInputCmd)
 
 	sel := m.tasksList.Index()
@@ -230,7 +270,7 @@ func (m model) viewTasksView() string {
 	if m.loggedInUser == nil {
 		authStatus = " (login required)"
 	}
-	helpText := fmt.Sprintf("↑/k up • ↓/j down • / filter • c claim%s • s submit%s • p projects • r refresh • esc back", authStatus, authStatus)
+	helpText := fmt.Sprintf("↑/k up • ↓/j down • / filter • n more • c claim%s • s submit%s • p projects • r refresh • esc back", authStatus, authStatus)
 	ui := lipgloss.JoinVertical(
 		lipgloss.Top,
 		header,
@@ -249,10 +289,26 @@ func (m model) renderTaskDetails(task *models.Task) string {
 	sb.WriteString(fmt.Sprintf("Title: %s\n", lipgloss.NewStyle().Foreground(pink).Render(task.Title)))
 	sb.WriteString(fmt.Sprintf("Description: %s\n", task.Description))
 	sb.WriteString(fmt.Sprintf("Project ID: %d\n", task.ProjectID))
+	if task.MilestoneID != nil {
+		sb.WriteString(fmt.Sprintf("Milestone ID: %d\n", *task.MilestoneID))
+	}
+	if task.GitHubIssueURL != "" {
+		sb.WriteString(fmt.Sprintf("GitHub Issue: %s\n", task.GitHubIssueURL))
+	}
 	sb.WriteString(fmt.Sprintf("Status: %s\n", lipgloss.NewStyle().Foreground(blue).Render(strings.ToTitle(task.Status))))
+	if task.IsBlocked() {
+		sb.WriteString(lipgloss.NewStyle().Foreground(red).Render(fmt.Sprintf("Blocked by tasks: %v", task.BlockedBy)))
+		sb.WriteString("\n")
+	}
 	if task.BountyAmount > 0 {
 		sb.WriteString(fmt.Sprintf("Bounty: $%.2f\n", task.BountyAmount))
 	}
+	switch task.ClaimPolicy {
+	case models.ClaimPolicyCompetitive:
+		sb.WriteString("Claims: Competitive (first accepted contribution wins)\n")
+	case models.ClaimPolicyLimited:
+		sb.WriteString(fmt.Sprintf("Claims: Up to %d claimants\n", task.MaxClaimants))
+	}
 	sb.WriteString(fmt.Sprintf("Difficulty: %s\n", strings.ToTitle(task.DifficultyLevel)))
 	if task.EstimatedHours > 0 {
 		sb.WriteString(fmt.Sprintf("Estimated Hours: %d\n", task.EstimatedHours))
@@ -265,5 +321,20 @@ func (m model) renderTaskDetails(task *models.Task) string {
 	}
 	sb.WriteString(fmt.Sprintf("Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04")))
 
+	if m.commentsTaskID == task.ID {
+		sb.WriteString("\n")
+		sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(blue).Render(fmt.Sprintf("Comments (%d):", len(m.taskComments))))
+		sb.WriteString("\n")
+		for _, comment := range m.taskComments {
+			indent := ""
+			if comment.ParentID != nil {
+				indent = "  ↳ "
+			}
+			author := lipgloss.NewStyle().Foreground(pink).Render("@" + comment.Author.Username)
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", indent, author, lipgloss.NewStyle().Foreground(gray).Render(comment.CreatedAt.Format("2006-01-02 15:04"))))
+			sb.WriteString(fmt.Sprintf("%s%s\n", indent, comment.Body))
+		}
+	}
+
 	return sb.String()
 }
\ No newline at end of file
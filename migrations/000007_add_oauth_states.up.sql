@@ -0,0 +1,11 @@
+CREATE TABLE oauth_states (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    state VARCHAR(128) NOT NULL UNIQUE,
+    code_verifier VARCHAR(128) NOT NULL,
+    client_state VARCHAR(128) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    INDEX idx_oauth_states_expires_at (expires_at)
+) ENGINE=InnoDB;
\ No newline at end of file
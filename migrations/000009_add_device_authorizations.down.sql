@@ -0,0 +1,3 @@
+ALTER TABLE oauth_states DROP FOREIGN KEY fk_oauth_states_device_authorization;
+ALTER TABLE oauth_states DROP COLUMN device_authorization_id;
+DROP TABLE IF EXISTS device_authorizations;
\ No newline at end of file
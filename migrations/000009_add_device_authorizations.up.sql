@@ -0,0 +1,20 @@
+CREATE TABLE device_authorizations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    device_code_hash CHAR(64) NOT NULL UNIQUE,
+    user_code VARCHAR(16) NOT NULL UNIQUE,
+    status ENUM('pending', 'approved', 'denied', 'consumed') NOT NULL DEFAULT 'pending',
+    user_id BIGINT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    poll_interval INT NOT NULL DEFAULT 5,
+    last_polled_at TIMESTAMP NULL,
+    client_user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    confirm_token_hash CHAR(64) NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+ALTER TABLE oauth_states ADD COLUMN device_authorization_id BIGINT NULL;
+ALTER TABLE oauth_states ADD CONSTRAINT fk_oauth_states_device_authorization FOREIGN KEY (device_authorization_id) REFERENCES device_authorizations(id) ON DELETE CASCADE;
\ No newline at end of file
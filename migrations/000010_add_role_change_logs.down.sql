@@ -0,0 +1 @@
+DROP TABLE IF EXISTS role_change_logs;
\ No newline at end of file
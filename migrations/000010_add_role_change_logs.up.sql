@@ -0,0 +1,15 @@
+CREATE TABLE role_change_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    actor_id BIGINT NULL, -- NULL when the change was made by the system (e.g. bootstrap admins)
+    target_user_id BIGINT NOT NULL,
+    role ENUM('admin', 'maintainer', 'contributor') NOT NULL,
+    action ENUM('grant', 'revoke') NOT NULL,
+    reason TEXT,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (target_user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+UPDATE users SET roles = JSON_ARRAY('contributor') WHERE roles IS NULL OR JSON_LENGTH(roles) = 0;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS project_maintainers;
\ No newline at end of file
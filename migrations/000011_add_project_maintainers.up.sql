@@ -0,0 +1,13 @@
+CREATE TABLE project_maintainers (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    project_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    added_by_id BIGINT NOT NULL,
+    UNIQUE KEY idx_project_maintainer (project_id, user_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (added_by_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
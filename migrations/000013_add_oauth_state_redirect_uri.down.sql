@@ -0,0 +1 @@
+ALTER TABLE oauth_states DROP COLUMN redirect_uri;
\ No newline at end of file
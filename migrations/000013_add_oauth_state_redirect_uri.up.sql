@@ -0,0 +1 @@
+ALTER TABLE oauth_states ADD COLUMN redirect_uri VARCHAR(2048) NOT NULL DEFAULT '';
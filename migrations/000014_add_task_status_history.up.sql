@@ -0,0 +1,14 @@
+CREATE TABLE task_status_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    task_id BIGINT NOT NULL,
+    from_status ENUM('open', 'claimed', 'in_progress', 'submitted', 'completed', 'archived') NOT NULL,
+    to_status ENUM('open', 'claimed', 'in_progress', 'submitted', 'completed', 'archived') NOT NULL,
+    actor_id BIGINT NULL, -- NULL when the system made the change
+    reason TEXT,
+    INDEX idx_task_status_history_task (task_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
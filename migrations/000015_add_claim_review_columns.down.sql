@@ -0,0 +1,9 @@
+ALTER TABLE claims ADD UNIQUE INDEX task_id (task_id, user_id);
+ALTER TABLE claims
+    DROP INDEX idx_task_user_active_claim,
+    DROP INDEX idx_claims_task_user,
+    DROP COLUMN active_claim,
+    DROP FOREIGN KEY fk_claims_reviewed_by,
+    DROP COLUMN review_note,
+    DROP COLUMN reviewed_at,
+    DROP COLUMN reviewed_by_id;
\ No newline at end of file
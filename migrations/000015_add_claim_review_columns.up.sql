@@ -0,0 +1,14 @@
+ALTER TABLE claims
+    ADD COLUMN reviewed_by_id BIGINT NULL,
+    ADD COLUMN reviewed_at TIMESTAMP NULL,
+    ADD COLUMN review_note TEXT,
+    ADD CONSTRAINT fk_claims_reviewed_by FOREIGN KEY (reviewed_by_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- A user may claim a task again once their earlier claim was rejected,
+-- withdrawn or expired. MySQL has no partial indexes, so the uniqueness only
+-- covers rows whose generated active_claim column is not NULL.
+ALTER TABLE claims
+    ADD COLUMN active_claim TINYINT(1) GENERATED ALWAYS AS (IF(status IN ('pending', 'accepted'), 1, NULL)) STORED,
+    ADD INDEX idx_claims_task_user (task_id, user_id),
+    ADD UNIQUE INDEX idx_task_user_active_claim (task_id, user_id, active_claim);
+ALTER TABLE claims DROP INDEX task_id;
\ No newline at end of file
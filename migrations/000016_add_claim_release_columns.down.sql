@@ -0,0 +1,5 @@
+UPDATE claims SET status = 'withdrawn' WHERE status = 'expired';
+ALTER TABLE claims
+    DROP COLUMN release_reason,
+    DROP COLUMN released_at,
+    MODIFY COLUMN status ENUM('pending', 'accepted', 'rejected', 'withdrawn') NOT NULL DEFAULT 'pending';
\ No newline at end of file
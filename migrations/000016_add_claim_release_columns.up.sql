@@ -0,0 +1,4 @@
+ALTER TABLE claims
+    MODIFY COLUMN status ENUM('pending', 'accepted', 'rejected', 'withdrawn', 'expired') NOT NULL DEFAULT 'pending',
+    ADD COLUMN released_at TIMESTAMP NULL,
+    ADD COLUMN release_reason TEXT;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP COLUMN max_claimants,
+    DROP COLUMN claim_policy;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN claim_policy ENUM('exclusive', 'competitive', 'limited') NOT NULL DEFAULT 'exclusive',
+    ADD COLUMN max_claimants INT NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE task_dependencies (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    task_id BIGINT NOT NULL,
+    depends_on_id BIGINT NOT NULL, -- the prerequisite that must be completed first
+    added_by_id BIGINT NULL,
+    UNIQUE KEY idx_task_dependency (task_id, depends_on_id),
+    INDEX idx_task_dependency_depends_on (depends_on_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (depends_on_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (added_by_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE milestones (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    project_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    kind ENUM('milestone', 'epic') NOT NULL DEFAULT 'milestone',
+    due_date TIMESTAMP NULL,
+    INDEX idx_milestones_project (project_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+ALTER TABLE tasks
+    ADD COLUMN milestone_id BIGINT NULL,
+    ADD INDEX idx_tasks_milestone (milestone_id),
+    ADD CONSTRAINT fk_tasks_milestone FOREIGN KEY (milestone_id) REFERENCES milestones(id) ON DELETE SET NULL;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE claims
+    DROP COLUMN endorsed_at;
+
+DROP TABLE IF EXISTS task_mentor_volunteers;
\ No newline at end of file
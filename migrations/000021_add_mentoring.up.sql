@@ -0,0 +1,15 @@
+CREATE TABLE task_mentor_volunteers (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    task_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    note TEXT,
+    UNIQUE KEY idx_task_mentor_volunteer (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+ALTER TABLE claims
+    ADD COLUMN endorsed_at TIMESTAMP NULL; -- set once the claim's mentor has endorsed the contributor
\ No newline at end of file
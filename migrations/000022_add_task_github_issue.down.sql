@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    DROP INDEX idx_project_github_issue,
+    DROP COLUMN github_issue_url,
+    DROP COLUMN github_issue_number;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN github_issue_number INT NULL, -- set on tasks imported from a GitHub issue
+    ADD COLUMN github_issue_url VARCHAR(512) NULL,
+    ADD UNIQUE KEY idx_project_github_issue (project_id, github_issue_number);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP INDEX idx_project_manifest_key,
+    DROP COLUMN manifest_key;
\ No newline at end of file
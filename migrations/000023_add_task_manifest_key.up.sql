@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN manifest_key VARCHAR(100) NULL, -- the task's key in the project's .ossyne.yml
+    ADD UNIQUE KEY idx_project_manifest_key (project_id, manifest_key);
\ No newline at end of file
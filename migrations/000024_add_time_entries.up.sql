@@ -0,0 +1,18 @@
+CREATE TABLE time_entries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    claim_id BIGINT NOT NULL,
+    task_id BIGINT NOT NULL, -- copied from the claim for per-task totals
+    user_id BIGINT NOT NULL,
+    minutes INT NOT NULL,
+    spent_on DATE NOT NULL, -- the day the work was done
+    note TEXT,
+    INDEX idx_time_entries_claim (claim_id),
+    INDEX idx_time_entries_task (task_id),
+    INDEX idx_time_entries_user (user_id),
+    FOREIGN KEY (claim_id) REFERENCES claims(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
\ No newline at end of file